@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/component"
 	"github.com/dobyte/due/v2/core/info"
+	"github.com/dobyte/due/v2/crypto/handshake"
 	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/log"
 	"github.com/dobyte/due/v2/network"
@@ -125,12 +127,32 @@ func (c *Client) handleReceive(conn network.Conn, data []byte) {
 		return
 	}
 
+	if cc := val.(*Conn); cc.handshake != nil {
+		cipher := cc.loadCipher()
+		if cipher == nil {
+			cc.finishHandshake(data)
+			return
+		}
+
+		msg, err := packet.Open(cipher, data)
+		if err != nil {
+			log.Errorf("open message failed: %v", err)
+			return
+		}
+
+		data = msg
+	}
+
 	message, err := packet.UnpackMessage(data)
 	if err != nil {
 		log.Errorf("unpack message failed: %v", err)
 		return
 	}
 
+	if c.opts.resume {
+		val.(*Conn).track(message)
+	}
+
 	handlers, ok := c.routes[message.Route]
 	if ok {
 		for _, handler := range handlers {
@@ -177,6 +199,23 @@ func (c *Client) dial(opts ...DialOption) (*Conn, error) {
 
 	c.conns.Store(conn, cc)
 
+	if c.opts.verifier != nil {
+		if err = c.doHandshake(cc); err != nil {
+			c.conns.Delete(conn)
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	// 恢复请求需为连接上的第一条消息，恢复令牌无需加密
+	if c.opts.resume && o.token != "" {
+		if err = cc.push(0, c.opts.resumeRoute, []byte(o.token)); err != nil {
+			c.conns.Delete(conn)
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
 	if handlers, ok := c.events[cluster.Connect]; ok {
 		for _, handler := range handlers {
 			xcall.Call(func() {
@@ -188,6 +227,26 @@ func (c *Client) dial(opts ...DialOption) (*Conn, error) {
 	return cc, nil
 }
 
+// 执行握手
+func (c *Client) doHandshake(cc *Conn) error {
+	cc.handshake = handshake.NewClient(c.opts.verifier, c.opts.suite)
+	cc.ready = make(chan error, 1)
+
+	if err := cc.startHandshake(); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(c.opts.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-cc.ready:
+		return err
+	case <-timer.C:
+		return errors.ErrHandshakeTimeout
+	}
+}
+
 // 添加路由处理器
 func (c *Client) addRouteHandler(route int32, handler RouteHandler) {
 	if c.getState() == cluster.Shut {
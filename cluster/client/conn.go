@@ -2,16 +2,26 @@ package client
 
 import (
 	"net"
+	"strconv"
+	"sync"
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/core/value"
+	"github.com/dobyte/due/v2/crypto/handshake"
+	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/network"
 	"github.com/dobyte/due/v2/packet"
 )
 
 type Conn struct {
-	conn   network.Conn
-	client *Client
+	conn      network.Conn
+	client    *Client
+	mu        sync.Mutex
+	cipher    *packet.Cipher    // 会话密码器
+	handshake *handshake.Client // 握手客户端
+	ready     chan error        // 握手结果
+	token     string            // 会话恢复令牌
+	received  uint64            // 收到令牌后已接收的消息数
 }
 
 // ID 获取连接ID
@@ -98,18 +108,109 @@ func (c *Conn) Push(message *cluster.Message) error {
 		}
 	}
 
+	return c.push(message.Seq, message.Route, buffer)
+}
+
+// ResumeToken 获取会话恢复凭证，即“令牌:已接收的消息数”；未开启会话恢复或尚未收到令牌时返回空字符串
+func (c *Conn) ResumeToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == "" {
+		return ""
+	}
+
+	return c.token + ":" + strconv.FormatUint(c.received, 10)
+}
+
+// 跟踪会话恢复，收到恢复路由上的令牌时将已接收的消息数重置为0
+func (c *Conn) track(message *packet.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if message.Route != c.client.opts.resumeRoute {
+		c.received++
+	} else if len(message.Buffer) > 0 {
+		c.token, c.received = string(message.Buffer), 0
+	}
+}
+
+// 打包并推送消息
+func (c *Conn) push(seq, route int32, buffer []byte) error {
 	msg, err := packet.PackMessage(&packet.Message{
-		Seq:    message.Seq,
-		Route:  message.Route,
+		Seq:    seq,
+		Route:  route,
 		Buffer: buffer,
 	})
 	if err != nil {
 		return err
 	}
 
+	if c.handshake == nil {
+		return c.conn.Push(msg)
+	}
+
+	// 加密与入队需在同一临界区内完成，以保证数据包的计数器按发送顺序递增
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cipher == nil {
+		return errors.ErrConnectionNotOpened
+	}
+
+	if msg, err = packet.Seal(c.cipher, msg); err != nil {
+		return err
+	}
+
+	return c.conn.Push(msg)
+}
+
+// 发起握手
+func (c *Conn) startHandshake() error {
+	hello, err := c.handshake.Hello()
+	if err != nil {
+		return err
+	}
+
+	msg, err := packet.PackHandshake(hello)
+	if err != nil {
+		return err
+	}
+
 	return c.conn.Push(msg)
 }
 
+// 完成握手
+func (c *Conn) finishHandshake(data []byte) {
+	ok, reply, err := packet.CheckHandshake(data)
+	if err == nil && !ok {
+		err = errors.ErrHandshakeFailed
+	}
+
+	if err == nil {
+		var cipher *packet.Cipher
+
+		if cipher, err = c.handshake.Finish(reply); err == nil {
+			c.mu.Lock()
+			c.cipher = cipher
+			c.mu.Unlock()
+		}
+	}
+
+	select {
+	case c.ready <- err:
+	default:
+	}
+}
+
+// 获取会话密码器，握手未完成时返回nil
+func (c *Conn) loadCipher() *packet.Cipher {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cipher
+}
+
 // Close 关闭连接
 func (c *Conn) Close() error {
 	return c.conn.Close()
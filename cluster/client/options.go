@@ -2,35 +2,49 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/dobyte/due/v2/crypto"
+	"github.com/dobyte/due/v2/crypto/handshake"
 	"github.com/dobyte/due/v2/encoding"
 	"github.com/dobyte/due/v2/etc"
 	"github.com/dobyte/due/v2/log"
 	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/utils/xconv"
 	"github.com/dobyte/due/v2/utils/xuuid"
 )
 
 const (
-	defaultName  = "client" // 默认客户端名称
-	defaultCodec = "proto"  // 默认编解码器名称
+	defaultName             = "client" // 默认客户端名称
+	defaultCodec            = "proto"  // 默认编解码器名称
+	defaultHandshakeTimeout = "5s"     // 默认握手超时时间
+	defaultResumeRoute      = -1       // 默认会话恢复路由
 )
 
 const (
-	defaultIDKey    = "etc.cluster.client.id"
-	defaultNameKey  = "etc.cluster.client.name"
-	defaultCodecKey = "etc.cluster.client.codec"
+	defaultIDKey               = "etc.cluster.client.id"
+	defaultNameKey             = "etc.cluster.client.name"
+	defaultCodecKey            = "etc.cluster.client.codec"
+	defaultHandshakeSuiteKey   = "etc.cluster.client.handshake.suite"
+	defaultHandshakeTimeoutKey = "etc.cluster.client.handshake.timeout"
+	defaultResumeKey           = "etc.cluster.client.resume.enable"
+	defaultResumeRouteKey      = "etc.cluster.client.resume.route"
 )
 
 type Option func(o *options)
 
 type options struct {
-	id        string           // 实例ID
-	name      string           // 实例名称
-	ctx       context.Context  // 上下文
-	codec     encoding.Codec   // 编解码器
-	client    network.Client   // 网络客户端
-	encryptor crypto.Encryptor // 消息加密器
+	id          string           // 实例ID
+	name        string           // 实例名称
+	ctx         context.Context  // 上下文
+	codec       encoding.Codec   // 编解码器
+	client      network.Client   // 网络客户端
+	encryptor   crypto.Encryptor // 消息加密器
+	verifier    crypto.Signer    // 握手签名校验器；设置后连接需先与网关完成握手，后续数据包均使用会话密钥加密
+	suite       handshake.Suite  // 握手使用的加密套件
+	timeout     time.Duration    // 握手超时时间
+	resume      bool             // 是否开启会话恢复；开启后连接将跟踪恢复令牌及收到令牌后已接收的消息数
+	resumeRoute int32            // 会话恢复路由，需与网关一致
 }
 
 func defaultOptions() *options {
@@ -55,6 +69,21 @@ func defaultOptions() *options {
 		opts.codec = encoding.Invoke(defaultCodec)
 	}
 
+	if suite := etc.Get(defaultHandshakeSuiteKey).String(); suite != "" {
+		if opts.suite = handshake.ParseSuite(suite); opts.suite == 0 {
+			log.Warnf("the handshake suite %s is not supported and will be ignored", suite)
+		}
+	}
+
+	if timeout := etc.Get(defaultHandshakeTimeoutKey, defaultHandshakeTimeout).Duration(); timeout > 0 {
+		opts.timeout = timeout
+	} else {
+		opts.timeout = xconv.Duration(defaultHandshakeTimeout)
+	}
+
+	opts.resume = etc.Get(defaultResumeKey).Bool()
+	opts.resumeRoute = etc.Get(defaultResumeRouteKey, defaultResumeRoute).Int32()
+
 	return opts
 }
 
@@ -124,11 +153,51 @@ func WithEncryptor(encryptor crypto.Encryptor) Option {
 	}
 }
 
+// WithHandshake 设置握手签名校验器及加密套件
+// 设置后连接需先与网关完成ECDH握手，后续数据包均使用每个连接独立的会话密钥加密
+func WithHandshake(verifier crypto.Signer, suite ...handshake.Suite) Option {
+	return func(o *options) {
+		if verifier == nil {
+			log.Warnf("the specified verifier is nil and will be automatically ignored")
+			return
+		}
+
+		o.verifier = verifier
+
+		if len(suite) > 0 && suite[0] != 0 {
+			o.suite = suite[0]
+		}
+	}
+}
+
+// WithHandshakeTimeout 设置握手超时时间
+func WithHandshakeTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.timeout = timeout
+		} else {
+			log.Warnf("the specified timeout is less than or equal to zero and will be automatically ignored")
+		}
+	}
+}
+
+// WithResume 设置是否开启会话恢复，并可指定会话恢复路由
+func WithResume(resume bool, route ...int32) Option {
+	return func(o *options) {
+		o.resume = resume
+
+		if len(route) > 0 {
+			o.resumeRoute = route[0]
+		}
+	}
+}
+
 type DialOption func(o *dialOptions)
 
 type dialOptions struct {
 	addr  string
 	attrs map[string]any
+	token string
 }
 
 // WithDialAddr 设置拨号地址
@@ -140,3 +209,9 @@ func WithDialAddr(addr string) DialOption {
 func WithConnAttr(key string, value any) DialOption {
 	return func(o *dialOptions) { o.attrs[key] = value }
 }
+
+// WithResumeToken 设置会话恢复凭证，可通过断开连接的Conn.ResumeToken获取
+// 连接建立后将首先向网关发送恢复请求，恢复结果通过会话恢复路由回复，回复内容为空时表示恢复失败
+func WithResumeToken(token string) DialOption {
+	return func(o *dialOptions) { o.token = token }
+}
@@ -1,6 +1,8 @@
 package cluster
 
 import (
+	"time"
+
 	"github.com/dobyte/due/v2/session"
 )
 
@@ -102,6 +104,39 @@ const (
 	Random           Dispatch = "random" // 随机
 	RoundRobin       Dispatch = "rr"     // 轮询
 	WeightRoundRobin Dispatch = "wrr"    // 加权轮询
+	ConsistentHash   Dispatch = "chash"  // 一致性哈希，按分发键（默认为用户ID）分配
+	LeastLoad        Dispatch = "ll"     // 最小负载，按节点上报的负载信息分配
+)
+
+// BreakerOptions 内部RPC熔断配置
+// 实例的连续失败次数或延迟分位任一达到阈值时熔断该实例，冷却后进入半开状态放行探测请求，探测成功后恢复
+type BreakerOptions struct {
+	Errors     int           // 连续失败次数阈值，为0时不检测
+	Latency    time.Duration // 延迟阈值，为0时不检测
+	Percentile float64       // 延迟分位，取值范围为(0,1]，默认为0.99
+	Window     int           // 延迟统计窗口的样本数，默认为100
+	Cooldown   time.Duration // 熔断冷却时间，默认为10s
+	Probes     int           // 半开状态下的探测请求数，默认为1
+}
+
+// RoutingRule 灰度路由规则
+// 命中规则的用户的无状态路由消息将优先分发至元数据匹配的节点实例，未命中任何规则的用户将避开所有规则的目标实例
+// 有状态路由消息仍按用户已绑定的节点分发，不受规则影响；目标实例不可用时回退至其他实例
+type RoutingRule struct {
+	Metadata map[string]string `json:"metadata"` // 目标节点实例需匹配的元数据，如 {"version": "v2"}
+	Percent  float64           `json:"percent"`  // 按用户ID哈希分流的用户百分比，取值范围为[0,100]
+	UIDs     []int64           `json:"uids"`     // 指定命中的用户ID
+	Tags     []string          `json:"tags"`     // 指定命中的用户标签白名单
+}
+
+// RoutingTagger 用户标签获取器，用于匹配灰度路由规则中的标签白名单
+type RoutingTagger func(uid int64) []string
+
+// 节点负载上报的元数据键
+const (
+	LoadInflightKey = "load.inflight" // 处理中的请求数
+	LoadActorsKey   = "load.actors"   // Actor数量
+	LoadCPUKey      = "load.cpu"      // 进程CPU使用率（百分比）
 )
 
 type GetIPArgs struct {
@@ -116,6 +151,13 @@ type Message struct {
 	Data  any   // 消息数据，接收json、proto、[]byte
 }
 
+// CodeMessage 错误码消息，框架在未设置自定义回复处理器时以此结构向客户端回复错误码（如邮箱溢出时的codes.TooManyRequests）
+// 使用proto等无法编码该结构的编解码器时，需通过自定义回复处理器按业务协议进行回复
+type CodeMessage struct {
+	Code    int    `json:"code"`    // 错误码
+	Message string `json:"message"` // 错误码消息
+}
+
 type PushArgs struct {
 	GID        string       // 网关ID，会话类型为用户时可忽略此参数
 	Kind       session.Kind // 会话类型，session.Conn 或 session.User
@@ -184,5 +226,13 @@ type DisconnectArgs struct {
 type DeliverArgs struct {
 	NID     string   // 接收节点。存在接收节点时，消息会直接投递给接收节点；不存在接收节点时，系统定位用户所在节点，然后投递。
 	UID     int64    // 用户ID
+	Key     string   // 分发键。一致性哈希分发策略下，无状态路由按分发键分配节点；为空时使用用户ID
+	Message *Message // 消息
+}
+
+type DeliverActorArgs struct {
+	NID     string   // 接收节点。存在接收节点时，消息会直接投递给接收节点；不存在接收节点时，系统通过Actor目录定位Actor所在节点，然后投递。
+	PID     string   // 接收Actor的唯一识别ID，格式为kind/id
+	UID     int64    // 用户ID
 	Message *Message // 消息
 }
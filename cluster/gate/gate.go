@@ -20,7 +20,9 @@ import (
 	"github.com/dobyte/due/v2/core/net"
 	"github.com/dobyte/due/v2/internal/transporter/gate"
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
 	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
 	"github.com/dobyte/due/v2/registry"
 	"github.com/dobyte/due/v2/session"
 )
@@ -32,6 +34,9 @@ type Gate struct {
 	cancel   context.CancelFunc
 	state    atomic.Int32
 	proxy    *proxy
+	limiter  *rateLimiter
+	resumer  *resumer
+	securer  *securer
 	instance *registry.ServiceInstance
 	session  *session.Session
 	linker   *gate.Server
@@ -48,6 +53,9 @@ func NewGate(opts ...Option) *Gate {
 	g.opts = o
 	g.ctx, g.cancel = context.WithCancel(o.ctx)
 	g.proxy = newProxy(g)
+	g.limiter = newRateLimiter(g)
+	g.resumer = newResumer(g)
+	g.securer = newSecurer(g)
 	g.session = session.NewSession()
 	g.state.Store(int32(cluster.Shut))
 	g.wg = &sync.WaitGroup{}
@@ -117,6 +125,8 @@ func (g *Gate) Destroy() {
 
 	g.deregisterServiceInstance()
 
+	g.resumer.close()
+
 	g.stopNetworkServer()
 
 	g.stopLinkerServer()
@@ -124,6 +134,12 @@ func (g *Gate) Destroy() {
 	g.cancel()
 }
 
+// SetRoutingRules 设置灰度路由规则，规则为空时清除灰度路由
+// 规则变更后立即对后续的无状态路由消息生效，已绑定节点的有状态路由消息不受影响
+func (g *Gate) SetRoutingRules(rules ...cluster.RoutingRule) {
+	g.proxy.nodeLinker.SetRoutingRules(rules...)
+}
+
 // 启动网络服务器
 func (g *Gate) startNetworkServer() {
 	g.opts.server.OnConnect(g.handleConnect)
@@ -146,8 +162,30 @@ func (g *Gate) stopNetworkServer() {
 func (g *Gate) handleConnect(conn network.Conn) {
 	g.wg.Add(1)
 
+	g.limiter.addConn(conn.ID())
+
+	metrics.GateConnections.Add(1, g.opts.id)
+
+	secure := g.securer.enabled()
+
+	// 开启握手时，连接将在握手完成后才可收发消息
+	if secure {
+		conn = g.securer.wrap(conn)
+	}
+
+	// 开启会话恢复时，连接事件将延迟至收到连接的第一条消息时触发
+	if g.opts.resume {
+		g.session.AddConn(g.resumer.wrap(conn))
+		return
+	}
+
 	g.session.AddConn(conn)
 
+	// 开启握手时，连接事件将延迟至握手完成时触发
+	if secure {
+		return
+	}
+
 	cid, uid := conn.ID(), conn.UID()
 
 	g.proxy.trigger(g.ctx, cluster.Connect, cid, uid)
@@ -155,26 +193,111 @@ func (g *Gate) handleConnect(conn network.Conn) {
 
 // 处理断开连接
 func (g *Gate) handleDisconnect(conn network.Conn) {
-	g.session.RemConn(conn)
+	defer g.wg.Done()
 
 	cid, uid := conn.ID(), conn.UID()
 
+	g.limiter.remConn(cid, uid)
+
+	metrics.GateConnections.Add(-1, g.opts.id)
+
+	g.session.RemConn(g.lookup(conn))
+
+	connected := true
+
+	if g.securer.enabled() {
+		if c, ok := g.securer.remove(cid); ok {
+			connected = c.established.Load()
+		}
+	}
+
+	if g.opts.resume {
+		if c, ok := g.resumer.load(cid); ok {
+			if g.resumer.park(c) || !c.connected.Load() {
+				return
+			}
+		}
+	}
+
+	if !connected {
+		return
+	}
+
 	if uid != 0 {
 		ctx, cancel := context.WithTimeout(g.ctx, 3*time.Second)
 		_ = g.proxy.unbindGate(ctx, cid, uid)
 		cancel()
+
+		if g.opts.resume {
+			g.resumer.forget(uid)
+		}
 	}
 
 	g.proxy.trigger(g.ctx, cluster.Disconnect, cid, uid)
-
-	g.wg.Done()
 }
 
 // 处理接收到的消息
 func (g *Gate) handleReceive(conn network.Conn, data []byte) {
+	if g.securer.enabled() {
+		c, ok := g.securer.load(conn.ID())
+		if !ok {
+			return
+		}
+
+		if data, ok = g.securer.receive(c, data); !ok {
+			return
+		}
+	}
+
+	// 网关仅解析消息头，消息体由节点服务器解析（解压），避免重复解压
+	message, err := packet.UnpackHeader(data)
+	if err != nil {
+		log.Errorf("unpack message failed: %v", err)
+		return
+	}
+
+	if g.opts.resume {
+		if c, ok := g.resumer.load(conn.ID()); ok {
+			if message.Route == g.opts.resumeRoute {
+				if message, err = packet.UnpackMessage(data); err != nil {
+					log.Errorf("unpack message failed: %v", err)
+					return
+				}
+
+				g.resumer.resume(c, message)
+				return
+			}
+
+			if c.connected.CompareAndSwap(false, true) {
+				g.proxy.trigger(g.ctx, cluster.Connect, c.ID(), c.UID())
+			}
+		}
+	}
+
+	if !g.limiter.allow(g.lookup(conn), message) {
+		return
+	}
+
 	cid, uid := conn.ID(), conn.UID()
 
-	g.proxy.deliver(g.ctx, cid, uid, data)
+	g.proxy.deliver(g.ctx, cid, uid, message, data)
+}
+
+// 获取会话中保存的连接
+func (g *Gate) lookup(conn network.Conn) network.Conn {
+	if g.opts.resume {
+		if c, ok := g.resumer.load(conn.ID()); ok {
+			return c
+		}
+	}
+
+	if g.securer.enabled() {
+		if c, ok := g.securer.load(conn.ID()); ok {
+			return c
+		}
+	}
+
+	return conn
 }
 
 // 启动传输服务器
@@ -229,14 +352,19 @@ func (g *Gate) refreshServiceInstance() {
 		return
 	}
 
+	if err := g.doRefreshServiceInstance(); err != nil {
+		log.Fatalf("refresh cluster instance failed: %v", err)
+	}
+}
+
+// 执行刷新实例状态操作
+func (g *Gate) doRefreshServiceInstance() error {
 	g.instance.State = g.getState().String()
 
 	ctx, cancel := context.WithTimeout(g.ctx, 3*time.Second)
 	defer cancel()
 
-	if err := g.opts.registry.Register(ctx, g.instance); err != nil {
-		log.Fatalf("refresh cluster instance failed: %v", err)
-	}
+	return g.opts.registry.Register(ctx, g.instance)
 }
 
 // 解注册服务实例
@@ -254,6 +382,17 @@ func (g *Gate) getState() cluster.State {
 	return cluster.State(g.state.Load())
 }
 
+// 更新状态
+func (g *Gate) setState(state cluster.State) error {
+	g.state.Store(int32(state))
+
+	if g.instance == nil {
+		return nil
+	}
+
+	return g.doRefreshServiceInstance()
+}
+
 // 打印组件信息
 func (g *Gate) printInfo() {
 	infos := make([]string, 0, 6)
@@ -0,0 +1,239 @@
+package gate
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/codes"
+	"github.com/dobyte/due/v2/core/limiter"
+	"github.com/dobyte/due/v2/encoding/json"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+)
+
+// Limit 限流配置
+type Limit struct {
+	Rate  float64 `json:"rate"`  // 每秒生成的令牌数；小于等于0时不限流
+	Burst int     `json:"burst"` // 令牌桶容量，即允许的突发请求数；小于等于0时与Rate一致
+}
+
+// LimitHandler 限流回复处理器，可在其中按业务协议向客户端回复codes.TooManyRequests，message仅包含路由和序列号
+// 未设置回复处理器时，将以JSON编码的cluster.CodeMessage回复客户端
+type LimitHandler func(conn network.Conn, message *packet.Message, code *codes.Code)
+
+type connLimiter struct {
+	limiter    *limiter.Limiter // 连接限流器
+	routes     sync.Map         // 连接的路由限流器
+	violations atomic.Int32     // 连续超出限流的次数
+}
+
+type uidLimiter struct {
+	limiter *limiter.Limiter // 用户限流器
+	active  atomic.Int64     // 最后活跃时间
+}
+
+type rateLimiter struct {
+	gate  *Gate
+	conns sync.Map // 连接限流器
+	uids  sync.Map // 用户限流器（UID -> *uidLimiter）
+}
+
+func newRateLimiter(gate *Gate) *rateLimiter {
+	return &rateLimiter{gate: gate}
+}
+
+// 检测消息是否允许投递
+func (l *rateLimiter) allow(conn network.Conn, message *packet.Message) bool {
+	var (
+		opts    = l.gate.opts
+		allowed = true
+		cl      = l.loadConnLimiter(conn.ID())
+	)
+
+	if cl == nil {
+		return true
+	}
+
+	if cl.limiter != nil {
+		allowed = cl.limiter.Allow()
+	}
+
+	if allowed {
+		if uid := conn.UID(); uid != 0 && opts.uidLimit.Rate > 0 {
+			allowed = l.loadUIDLimiter(uid).Allow()
+		}
+	}
+
+	if allowed {
+		if limit, ok := l.loadRouteLimit(message.Route); ok {
+			allowed = loadLimiter(&cl.routes, message.Route, limit).Allow()
+		}
+	}
+
+	if allowed {
+		cl.violations.Store(0)
+	} else {
+		l.reject(conn, message, cl.violations.Add(1))
+	}
+
+	return allowed
+}
+
+// 加载路由的限流配置
+// 仅对指定了限流配置或已注册到节点的路由进行限流，避免客户端伪造路由导致限流器无限增长
+func (l *rateLimiter) loadRouteLimit(route int32) (Limit, bool) {
+	opts := l.gate.opts
+
+	if limit, ok := opts.routeLimits[route]; ok {
+		return limit, limit.Rate > 0
+	}
+
+	if opts.routeLimit.Rate > 0 && l.gate.proxy.hasRoute(route) {
+		return opts.routeLimit, true
+	}
+
+	return Limit{}, false
+}
+
+// 拒绝消息
+func (l *rateLimiter) reject(conn network.Conn, message *packet.Message, violations int32) {
+	opts := l.gate.opts
+
+	log.Warnf("message exceeds the rate limit, cid: %d uid: %d seq: %d route: %d", conn.ID(), conn.UID(), message.Seq, message.Route)
+
+	if opts.maxViolations > 0 && int(violations) >= opts.maxViolations {
+		log.Warnf("connection exceeds the rate limit too many times and will be closed, cid: %d uid: %d", conn.ID(), conn.UID())
+
+		if err := conn.Close(); err != nil {
+			log.Errorf("close connection failed, cid: %d uid: %d err: %v", conn.ID(), conn.UID(), err)
+		}
+
+		return
+	}
+
+	if !opts.limitReply {
+		return
+	}
+
+	if opts.limitHandler != nil {
+		opts.limitHandler(conn, message, codes.TooManyRequests)
+	} else {
+		replyCode(conn, message, codes.TooManyRequests)
+	}
+}
+
+// 以JSON编码的cluster.CodeMessage回复错误码
+func replyCode(conn network.Conn, message *packet.Message, code *codes.Code) {
+	data, err := json.Marshal(&cluster.CodeMessage{Code: code.Code(), Message: code.Message()})
+	if err != nil {
+		log.Errorf("marshal code message failed: %v", err)
+		return
+	}
+
+	msg, err := packet.PackMessage(&packet.Message{Seq: message.Seq, Route: message.Route, Buffer: data})
+	if err != nil {
+		log.Errorf("pack code message failed: %v", err)
+		return
+	}
+
+	if err = conn.Push(msg); err != nil {
+		log.Warnf("reply code message failed, cid: %d uid: %d err: %v", conn.ID(), conn.UID(), err)
+	}
+}
+
+// 添加连接
+func (l *rateLimiter) addConn(cid int64) {
+	cl := &connLimiter{}
+
+	if limit := l.gate.opts.connLimit; limit.Rate > 0 {
+		cl.limiter = newLimiter(limit)
+	}
+
+	l.conns.Store(cid, cl)
+}
+
+// 移除连接
+// 用户限流器在令牌桶补满所需的时间内保持空闲后才移除，避免用户通过重连获得新的令牌桶
+func (l *rateLimiter) remConn(cid, uid int64) {
+	l.conns.Delete(cid)
+
+	if uid == 0 {
+		return
+	}
+
+	v, ok := l.uids.Load(uid)
+	if !ok {
+		return
+	}
+
+	ttl := refillTime(l.gate.opts.uidLimit)
+
+	var expire func()
+
+	expire = func() {
+		ul := v.(*uidLimiter)
+
+		if idle := time.Duration(time.Now().UnixNano() - ul.active.Load()); idle < ttl {
+			time.AfterFunc(ttl-idle, expire)
+		} else {
+			l.uids.CompareAndDelete(uid, ul)
+		}
+	}
+
+	time.AfterFunc(ttl, expire)
+}
+
+// 加载用户限流器
+func (l *rateLimiter) loadUIDLimiter(uid int64) *limiter.Limiter {
+	v, ok := l.uids.Load(uid)
+	if !ok {
+		v, _ = l.uids.LoadOrStore(uid, &uidLimiter{limiter: newLimiter(l.gate.opts.uidLimit)})
+	}
+
+	ul := v.(*uidLimiter)
+	ul.active.Store(time.Now().UnixNano())
+
+	return ul.limiter
+}
+
+// 加载连接限流器
+func (l *rateLimiter) loadConnLimiter(cid int64) *connLimiter {
+	if cl, ok := l.conns.Load(cid); ok {
+		return cl.(*connLimiter)
+	}
+
+	return nil
+}
+
+// 加载限流器
+func loadLimiter(limiters *sync.Map, key any, limit Limit) *limiter.Limiter {
+	if v, ok := limiters.Load(key); ok {
+		return v.(*limiter.Limiter)
+	}
+
+	v, _ := limiters.LoadOrStore(key, newLimiter(limit))
+
+	return v.(*limiter.Limiter)
+}
+
+// 创建令牌桶限流器
+func newLimiter(limit Limit) *limiter.Limiter {
+	return limiter.NewLimiter(limitBurst(limit), limit.Rate)
+}
+
+// 计算令牌桶容量
+func limitBurst(limit Limit) float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+
+	return max(limit.Rate, 1)
+}
+
+// 计算令牌桶从空到补满所需的时间，超过该时间的空闲限流器与新建的限流器等效
+func refillTime(limit Limit) time.Duration {
+	return time.Duration(limitBurst(limit) / limit.Rate * float64(time.Second))
+}
@@ -13,7 +13,10 @@ import (
 	"time"
 
 	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/crypto"
+	"github.com/dobyte/due/v2/crypto/handshake"
 	"github.com/dobyte/due/v2/etc"
+	"github.com/dobyte/due/v2/internal/breaker"
 	"github.com/dobyte/due/v2/locate"
 	"github.com/dobyte/due/v2/log"
 	"github.com/dobyte/due/v2/network"
@@ -33,44 +36,80 @@ const (
 	defaultWriteTimeout      = "0s"           // 默认写入超时时间
 	defaultWriteQueueSize    = 2048           // 默认写入队列大小
 	defaultFaultRecoveryTime = "5s"           // 默认故障恢复时间
+	defaultLimitReply        = true           // 默认超出限流时回复客户端
+	defaultResumeRoute       = -1             // 默认会话恢复路由
+	defaultResumeTimeout     = "30s"          // 默认会话恢复超时时间
+	defaultResumeBufferSize  = 128            // 默认会话恢复消息缓冲区大小
+	defaultHandshakeTimeout  = "5s"           // 默认握手超时时间
 )
 
 const (
-	defaultIDKey                = "etc.cluster.gate.id"
-	defaultNameKey              = "etc.cluster.gate.name"
-	defaultDispatchKey          = "etc.cluster.gate.dispatch"
-	defaultMetadataKey          = "etc.cluster.gate.metadata"
-	defaultAddrKey              = "etc.cluster.gate.addr"
-	defaultExposeKey            = "etc.cluster.gate.expose"
-	defaultConnNumKey           = "etc.cluster.gate.connNum"
-	defaultCallTimeoutKey       = "etc.cluster.gate.callTimeout"
-	defaultDialTimeoutKey       = "etc.cluster.gate.dialTimeout"
-	defaultDialRetryTimesKey    = "etc.cluster.gate.dialRetryTimes"
-	defaultWriteTimeoutKey      = "etc.cluster.gate.writeTimeout"
-	defaultWriteQueueSizeKey    = "etc.cluster.gate.writeQueueSize"
-	defaultFaultRecoveryTimeKey = "etc.cluster.gate.faultRecoveryTime"
+	defaultIDKey                 = "etc.cluster.gate.id"
+	defaultNameKey               = "etc.cluster.gate.name"
+	defaultDispatchKey           = "etc.cluster.gate.dispatch"
+	defaultMetadataKey           = "etc.cluster.gate.metadata"
+	defaultAddrKey               = "etc.cluster.gate.addr"
+	defaultExposeKey             = "etc.cluster.gate.expose"
+	defaultConnNumKey            = "etc.cluster.gate.connNum"
+	defaultCallTimeoutKey        = "etc.cluster.gate.callTimeout"
+	defaultDialTimeoutKey        = "etc.cluster.gate.dialTimeout"
+	defaultDialRetryTimesKey     = "etc.cluster.gate.dialRetryTimes"
+	defaultWriteTimeoutKey       = "etc.cluster.gate.writeTimeout"
+	defaultWriteQueueSizeKey     = "etc.cluster.gate.writeQueueSize"
+	defaultFaultRecoveryTimeKey  = "etc.cluster.gate.faultRecoveryTime"
+	defaultBreakerKey            = "etc.cluster.gate.breaker"
+	defaultConnLimitKey          = "etc.cluster.gate.limit.conn"
+	defaultUIDLimitKey           = "etc.cluster.gate.limit.uid"
+	defaultRouteLimitKey         = "etc.cluster.gate.limit.route"
+	defaultRouteLimitsKey        = "etc.cluster.gate.limit.routes"
+	defaultLimitReplyKey         = "etc.cluster.gate.limit.reply"
+	defaultLimitMaxViolationsKey = "etc.cluster.gate.limit.maxViolations"
+	defaultResumeKey             = "etc.cluster.gate.resume.enable"
+	defaultResumeRouteKey        = "etc.cluster.gate.resume.route"
+	defaultResumeTimeoutKey      = "etc.cluster.gate.resume.timeout"
+	defaultResumeBufferSizeKey   = "etc.cluster.gate.resume.bufferSize"
+	defaultHandshakeSuitesKey    = "etc.cluster.gate.handshake.suites"
+	defaultHandshakeTimeoutKey   = "etc.cluster.gate.handshake.timeout"
+	defaultRoutingRulesKey       = "etc.cluster.gate.routing.rules"
 )
 
 type Option func(o *options)
 
 type options struct {
-	ctx               context.Context   // 上下文
-	id                string            // 实例ID
-	name              string            // 实例名称
-	server            network.Server    // 网关服务器
-	locator           locate.Locator    // 用户定位器
-	registry          registry.Registry // 服务注册器
-	dispatch          cluster.Dispatch  // 无状态路由消息分发策略
-	metadata          map[string]string // 元数据
-	addr              string            // 内部RPC监听地址
-	expose            bool              // 内部RPC是否暴露到公网
-	connNum           int               // 内部RPC拨号连接数
-	callTimeout       time.Duration     // 内部RPC调用超时时间
-	dialTimeout       time.Duration     // 内部RPC拨号超时时间
-	dialRetryTimes    int               // 内部RPC拨号重试次数
-	writeTimeout      time.Duration     // 内部RPC写入超时时间
-	writeQueueSize    int32             // 内部RPC写入队列大小
-	faultRecoveryTime time.Duration     // 内部RPC故障恢复时间
+	ctx               context.Context        // 上下文
+	id                string                 // 实例ID
+	name              string                 // 实例名称
+	server            network.Server         // 网关服务器
+	locator           locate.Locator         // 用户定位器
+	registry          registry.Registry      // 服务注册器
+	dispatch          cluster.Dispatch       // 无状态路由消息分发策略
+	metadata          map[string]string      // 元数据
+	addr              string                 // 内部RPC监听地址
+	expose            bool                   // 内部RPC是否暴露到公网
+	connNum           int                    // 内部RPC拨号连接数
+	callTimeout       time.Duration          // 内部RPC调用超时时间
+	dialTimeout       time.Duration          // 内部RPC拨号超时时间
+	dialRetryTimes    int                    // 内部RPC拨号重试次数
+	writeTimeout      time.Duration          // 内部RPC写入超时时间
+	writeQueueSize    int32                  // 内部RPC写入队列大小
+	faultRecoveryTime time.Duration          // 内部RPC故障恢复时间
+	breaker           cluster.BreakerOptions // 内部RPC熔断配置
+	connLimit         Limit                  // 单个连接的限流配置
+	uidLimit          Limit                  // 单个用户的限流配置，仅对已绑定用户的连接生效
+	routeLimit        Limit                  // 单个连接上每个路由的默认限流配置，仅对已注册到节点的路由生效
+	routeLimits       map[int32]Limit        // 单个连接上指定路由的限流配置
+	limitReply        bool                   // 超出限流时是否回复客户端
+	limitHandler      LimitHandler           // 超出限流时的回复处理器
+	maxViolations     int                    // 连续超出限流的最大次数，超出后将断开连接；小于等于0时不断开连接
+	resume            bool                   // 是否开启会话恢复；开启后连接事件将延迟至收到连接的第一条消息时触发
+	resumeRoute       int32                  // 会话恢复路由
+	resumeTimeout     time.Duration          // 会话恢复超时时间，连接断开超过该时间后将无法恢复
+	resumeBufferSize  int                    // 会话恢复消息缓冲区大小，即每个用户最多缓存的最近推送消息数
+	signer            crypto.Signer          // 握手签名器；设置后客户端连接需先完成握手，后续数据包均使用会话密钥加密
+	suites            []handshake.Suite      // 握手允许使用的加密套件
+	handshakeTimeout  time.Duration          // 握手超时时间
+	routingRules      []cluster.RoutingRule  // 灰度路由规则
+	routingTagger     cluster.RoutingTagger  // 灰度路由用户标签获取器
 }
 
 func defaultOptions() *options {
@@ -145,10 +184,65 @@ func defaultOptions() *options {
 		opts.faultRecoveryTime = xconv.Duration(defaultFaultRecoveryTime)
 	}
 
+	opts.breaker = breaker.LoadOptions(defaultBreakerKey)
+
 	if err := etc.Get(defaultMetadataKey).Scan(&opts.metadata); err != nil {
 		log.Warnf("scan metadata failed: %v", err)
 	}
 
+	opts.limitReply = etc.Get(defaultLimitReplyKey, defaultLimitReply).Bool()
+	opts.maxViolations = etc.Get(defaultLimitMaxViolationsKey).Int()
+	opts.routeLimits = make(map[int32]Limit)
+
+	opts.resume = etc.Get(defaultResumeKey).Bool()
+	opts.resumeRoute = etc.Get(defaultResumeRouteKey, defaultResumeRoute).Int32()
+
+	if resumeTimeout := etc.Get(defaultResumeTimeoutKey, defaultResumeTimeout).Duration(); resumeTimeout > 0 {
+		opts.resumeTimeout = resumeTimeout
+	} else {
+		opts.resumeTimeout = xconv.Duration(defaultResumeTimeout)
+	}
+
+	if resumeBufferSize := etc.Get(defaultResumeBufferSizeKey, defaultResumeBufferSize).Int(); resumeBufferSize > 0 {
+		opts.resumeBufferSize = resumeBufferSize
+	} else {
+		opts.resumeBufferSize = defaultResumeBufferSize
+	}
+
+	for _, name := range etc.Get(defaultHandshakeSuitesKey).Strings() {
+		if suite := handshake.ParseSuite(name); suite != 0 {
+			opts.suites = append(opts.suites, suite)
+		} else {
+			log.Warnf("the handshake suite %s is not supported and will be ignored", name)
+		}
+	}
+
+	if handshakeTimeout := etc.Get(defaultHandshakeTimeoutKey, defaultHandshakeTimeout).Duration(); handshakeTimeout > 0 {
+		opts.handshakeTimeout = handshakeTimeout
+	} else {
+		opts.handshakeTimeout = xconv.Duration(defaultHandshakeTimeout)
+	}
+
+	if err := etc.Get(defaultConnLimitKey).Scan(&opts.connLimit); err != nil {
+		log.Warnf("scan conn limit failed: %v", err)
+	}
+
+	if err := etc.Get(defaultUIDLimitKey).Scan(&opts.uidLimit); err != nil {
+		log.Warnf("scan uid limit failed: %v", err)
+	}
+
+	if err := etc.Get(defaultRouteLimitKey).Scan(&opts.routeLimit); err != nil {
+		log.Warnf("scan route limit failed: %v", err)
+	}
+
+	if err := etc.Get(defaultRouteLimitsKey).Scan(&opts.routeLimits); err != nil {
+		log.Warnf("scan route limits failed: %v", err)
+	}
+
+	if err := etc.Get(defaultRoutingRulesKey).Scan(&opts.routingRules); err != nil {
+		log.Warnf("scan routing rules failed: %v", err)
+	}
+
 	return opts
 }
 
@@ -230,6 +324,7 @@ func WithDispatch(dispatch cluster.Dispatch) Option {
 }
 
 // WithAddr 设置监听地址
+// 支持TCP地址、Unix域套接字（unix:///path/to/due.sock）及进程内传输（inproc://name）
 func WithAddr(addr string) Option {
 	return func(o *options) {
 		if addr != "" {
@@ -336,3 +431,133 @@ func WithMetadata(metadata map[string]string) Option {
 		}
 	}
 }
+
+// WithConnLimit 设置单个连接的限流配置
+func WithConnLimit(limit Limit) Option {
+	return func(o *options) { o.connLimit = limit }
+}
+
+// WithUIDLimit 设置单个用户的限流配置
+func WithUIDLimit(limit Limit) Option {
+	return func(o *options) { o.uidLimit = limit }
+}
+
+// WithRouteLimit 设置单个连接上路由的限流配置，未指定路由时设置所有路由的默认限流配置（仅对已注册到节点的路由生效）
+func WithRouteLimit(limit Limit, routes ...int32) Option {
+	return func(o *options) {
+		if len(routes) == 0 {
+			o.routeLimit = limit
+			return
+		}
+
+		if o.routeLimits == nil {
+			o.routeLimits = make(map[int32]Limit, len(routes))
+		}
+
+		for _, route := range routes {
+			o.routeLimits[route] = limit
+		}
+	}
+}
+
+// WithLimitReply 设置超出限流时是否回复客户端，可传入回复处理器按业务协议进行回复
+// 未传入回复处理器时，将以JSON编码的cluster.CodeMessage回复客户端codes.TooManyRequests
+func WithLimitReply(reply bool, handler ...LimitHandler) Option {
+	return func(o *options) {
+		o.limitReply = reply
+
+		if len(handler) > 0 {
+			o.limitHandler = handler[0]
+		}
+	}
+}
+
+// WithLimitMaxViolations 设置连续超出限流的最大次数，超出后将断开连接
+func WithLimitMaxViolations(maxViolations int) Option {
+	return func(o *options) { o.maxViolations = maxViolations }
+}
+
+// WithResume 设置是否开启会话恢复，并可指定会话恢复路由
+func WithResume(resume bool, route ...int32) Option {
+	return func(o *options) {
+		o.resume = resume
+
+		if len(route) > 0 {
+			o.resumeRoute = route[0]
+		}
+	}
+}
+
+// WithResumeTimeout 设置会话恢复超时时间
+func WithResumeTimeout(resumeTimeout time.Duration) Option {
+	return func(o *options) {
+		if resumeTimeout > 0 {
+			o.resumeTimeout = resumeTimeout
+		} else {
+			log.Warnf("the specified resumeTimeout is less than or equal to zero and will be ignored")
+		}
+	}
+}
+
+// WithResumeBufferSize 设置会话恢复消息缓冲区大小
+func WithResumeBufferSize(resumeBufferSize int) Option {
+	return func(o *options) {
+		if resumeBufferSize > 0 {
+			o.resumeBufferSize = resumeBufferSize
+		} else {
+			log.Warnf("the specified resumeBufferSize is less than or equal to zero and will be ignored")
+		}
+	}
+}
+
+// WithHandshake 设置握手签名器及允许使用的加密套件
+// 设置后客户端连接需先完成ECDH握手，后续数据包均使用每个连接独立的会话密钥加密
+func WithHandshake(signer crypto.Signer, suites ...handshake.Suite) Option {
+	return func(o *options) {
+		if signer == nil {
+			log.Warnf("the specified signer is nil and will be ignored")
+			return
+		}
+
+		o.signer = signer
+
+		if len(suites) > 0 {
+			o.suites = suites
+		}
+	}
+}
+
+// WithHandshakeTimeout 设置握手超时时间
+func WithHandshakeTimeout(handshakeTimeout time.Duration) Option {
+	return func(o *options) {
+		if handshakeTimeout > 0 {
+			o.handshakeTimeout = handshakeTimeout
+		} else {
+			log.Warnf("the specified handshakeTimeout is less than or equal to zero and will be ignored")
+		}
+	}
+}
+
+// WithBreaker 设置内部RPC熔断配置
+// 连续失败次数阈值与延迟阈值均为0时不开启熔断
+func WithBreaker(opts cluster.BreakerOptions) Option {
+	return func(o *options) {
+		o.breaker = opts
+	}
+}
+
+// WithRoutingRules 设置灰度路由规则
+// 命中规则的用户的无状态路由消息将优先分发至元数据匹配的节点实例，运行期间可通过 Gate.SetRoutingRules 更新规则
+func WithRoutingRules(rules ...cluster.RoutingRule) Option {
+	return func(o *options) {
+		o.routingRules = rules
+	}
+}
+
+// WithRoutingTagger 设置灰度路由用户标签获取器
+// 灰度路由规则中配置了标签白名单时，将通过该获取器获取用户标签进行匹配
+func WithRoutingTagger(tagger cluster.RoutingTagger) Option {
+	return func(o *options) {
+		o.routingTagger = tagger
+	}
+}
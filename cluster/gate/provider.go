@@ -29,6 +29,10 @@ func (p *provider) Bind(ctx context.Context, cid, uid int64) error {
 		return err
 	}
 
+	if p.gate.opts.resume {
+		p.gate.resumer.issue(cid, uid)
+	}
+
 	return nil
 }
 
@@ -38,6 +42,10 @@ func (p *provider) Unbind(ctx context.Context, uid int64) error {
 		return errors.ErrInvalidArgument
 	}
 
+	if p.gate.opts.resume {
+		p.gate.resumer.forget(uid)
+	}
+
 	cid, err := p.gate.session.Unbind(uid)
 	if err != nil {
 		return err
@@ -70,6 +78,13 @@ func (p *provider) Disconnect(ctx context.Context, kind session.Kind, target int
 func (p *provider) Push(ctx context.Context, kind session.Kind, target int64, disconnect bool, message []byte) error {
 	err := p.gate.session.Push(kind, target, disconnect, message)
 
+	// 用户连接已断开但会话仍可恢复时，缓存消息以待恢复后补发
+	if kind == session.User && p.gate.opts.resume && errors.Is(err, errors.ErrNotFoundSession) {
+		if p.gate.resumer.hold(target, disconnect, message) {
+			return nil
+		}
+	}
+
 	if kind == session.User && errors.Is(err, errors.ErrNotFoundSession) {
 		xcall.Go(func() {
 			if e := p.gate.opts.locator.UnbindGate(ctx, target, p.gate.opts.id); e != nil {
@@ -83,7 +98,23 @@ func (p *provider) Push(ctx context.Context, kind session.Kind, target int64, di
 
 // Multicast 推送组播消息
 func (p *provider) Multicast(ctx context.Context, kind session.Kind, targets []int64, disconnect bool, message []byte) (int64, error) {
-	return p.gate.session.Multicast(kind, targets, disconnect, message)
+	var held int64
+
+	// 用户连接已断开但会话仍可恢复时，缓存消息以待恢复后补发
+	if kind == session.User && p.gate.opts.resume {
+		for _, target := range targets {
+			if p.gate.resumer.hold(target, disconnect, message) {
+				held++
+			}
+		}
+	}
+
+	total, err := p.gate.session.Multicast(kind, targets, disconnect, message)
+	if held > 0 {
+		return total + held, nil
+	}
+
+	return total, err
 }
 
 // Broadcast 推送广播消息
@@ -108,12 +139,10 @@ func (p *provider) Unsubscribe(ctx context.Context, kind session.Kind, targets [
 
 // GetState 获取状态
 func (p *provider) GetState() (cluster.State, error) {
-	return cluster.State(p.gate.state.Load()), nil
+	return p.gate.getState(), nil
 }
 
 // SetState 设置状态
 func (p *provider) SetState(state cluster.State) error {
-	p.gate.state.Store(int32(state))
-
-	return nil
+	return p.gate.setState(state)
 }
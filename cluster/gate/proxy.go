@@ -2,13 +2,16 @@ package gate
 
 import (
 	"context"
+	"time"
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/internal/link"
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
 	"github.com/dobyte/due/v2/mode"
 	"github.com/dobyte/due/v2/packet"
+	"github.com/dobyte/due/v2/tracing"
 )
 
 type proxy struct {
@@ -30,6 +33,9 @@ func newProxy(gate *Gate) *proxy {
 		WriteTimeout:      gate.opts.writeTimeout,
 		WriteQueueSize:    gate.opts.writeQueueSize,
 		FaultRecoveryTime: gate.opts.faultRecoveryTime,
+		Breaker:           gate.opts.breaker,
+		RoutingRules:      gate.opts.routingRules,
+		RoutingTagger:     gate.opts.routingTagger,
 	})}
 }
 
@@ -55,6 +61,11 @@ func (p *proxy) unbindGate(ctx context.Context, cid, uid int64) error {
 	return err
 }
 
+// 检测路由是否已注册到节点
+func (p *proxy) hasRoute(route int32) bool {
+	return p.nodeLinker.HasRoute(route)
+}
+
 // 触发事件
 func (p *proxy) trigger(ctx context.Context, event cluster.Event, cid, uid int64) {
 	if mode.IsDebugMode() {
@@ -76,19 +87,27 @@ func (p *proxy) trigger(ctx context.Context, event cluster.Event, cid, uid int64
 }
 
 // 投递消息
-func (p *proxy) deliver(ctx context.Context, cid, uid int64, data []byte) {
-	message, err := packet.UnpackMessage(data)
-	if err != nil {
-		log.Errorf("unpack message failed: %v", err)
-		return
-	}
+func (p *proxy) deliver(ctx context.Context, cid, uid int64, message *packet.Message, data []byte) {
+	start := time.Now()
+
+	ctx, span := tracing.Start(ctx, "gate.deliver",
+		tracing.WithSpanKind(tracing.SpanKindProducer),
+		tracing.WithAttributes(tracing.Int("route", int(message.Route)), tracing.Int64("cid", cid), tracing.Int64("uid", uid)),
+	)
+	defer span.End()
 
-	if err = p.nodeLinker.Deliver(ctx, &link.DeliverArgs{
+	err := p.nodeLinker.Deliver(ctx, &link.DeliverArgs{
 		CID:    cid,
 		UID:    uid,
 		Route:  message.Route,
 		Buffer: data,
-	}); err != nil {
+	})
+
+	metrics.GateDeliverDuration.Observe(time.Since(start).Seconds(), metrics.Route(message.Route, p.hasRoute(message.Route)), metrics.Result(err))
+
+	if err != nil {
+		span.RecordError(err)
+
 		switch {
 		case errors.Is(err, errors.ErrNotFoundRoute), errors.Is(err, errors.ErrNotFoundEndpoint):
 			log.Warnf("deliver message failed, cid: %d uid: %d seq: %d route: %d err: %v", cid, uid, message.Seq, message.Route, err)
@@ -0,0 +1,373 @@
+package gate
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+)
+
+// 会话恢复协议（路由由etc.cluster.gate.resume.route指定）：
+// 1. 用户绑定成功后，网关向客户端推送恢复令牌，消息内容为令牌字符串；客户端收到令牌后将已接收的消息数重置为0，并对后续收到的每条消息进行计数
+// 2. 断线重连后，客户端以“令牌”或“令牌:已接收的消息数”作为消息内容发送至恢复路由，该消息需为新连接上的第一条消息
+// 3. 恢复成功时，网关回复令牌并补发断线期间遗漏的消息，同时向节点触发Reconnect事件；客户端同样需将已接收的消息数重置为0，补发的消息计入其中
+//    恢复失败时回复空消息，客户端需重新登录
+
+type resumeConn struct {
+	network.Conn
+	resumer   *resumer
+	connected atomic.Bool // 是否已向节点触发连接事件
+	replaced  atomic.Bool // 是否已被恢复的连接替换
+}
+
+// Send 发送消息（同步）
+func (c *resumeConn) Send(msg []byte) error {
+	c.resumer.record(c, msg)
+
+	return c.Conn.Send(msg)
+}
+
+// Push 发送消息（异步）
+func (c *resumeConn) Push(msg []byte) error {
+	c.resumer.record(c, msg)
+
+	return c.Conn.Push(msg)
+}
+
+type resumeState struct {
+	uid     int64       // 用户ID
+	cid     int64       // 当前连接ID；为0时表示连接已断开，等待恢复
+	lastCID int64       // 最后一次断开的连接ID
+	token   string      // 恢复令牌
+	sent    uint64      // 已发送的消息数
+	packets [][]byte    // 最近发送的消息环形缓冲区
+	timer   *time.Timer // 会话过期定时器
+}
+
+// 缓存消息
+func (s *resumeState) append(msg []byte) {
+	s.sent++
+	s.packets[(s.sent-1)%uint64(len(s.packets))] = bytes.Clone(msg)
+}
+
+// 获取已接收消息数之后的消息
+func (s *resumeState) since(received uint64) ([][]byte, bool) {
+	if received > s.sent || s.sent-received > uint64(len(s.packets)) {
+		return nil, false
+	}
+
+	packets := make([][]byte, 0, s.sent-received)
+
+	for i := received + 1; i <= s.sent; i++ {
+		packets = append(packets, s.packets[(i-1)%uint64(len(s.packets))])
+	}
+
+	return packets, true
+}
+
+type resumer struct {
+	gate   *Gate
+	conns  sync.Map               // 连接（连接ID -> *resumeConn）
+	mu     sync.Mutex             // 会话锁
+	states map[int64]*resumeState // 会话（用户ID -> *resumeState）
+}
+
+func newResumer(gate *Gate) *resumer {
+	return &resumer{gate: gate, states: make(map[int64]*resumeState)}
+}
+
+// 包装连接
+func (r *resumer) wrap(conn network.Conn) *resumeConn {
+	c := &resumeConn{Conn: conn, resumer: r}
+
+	r.conns.Store(conn.ID(), c)
+
+	return c
+}
+
+// 加载连接
+func (r *resumer) load(cid int64) (*resumeConn, bool) {
+	if c, ok := r.conns.Load(cid); ok {
+		return c.(*resumeConn), true
+	}
+
+	return nil, false
+}
+
+// 记录发送的消息
+func (r *resumer) record(conn *resumeConn, msg []byte) {
+	uid := conn.UID()
+	if uid == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if state, ok := r.states[uid]; ok && state.cid == conn.ID() {
+		state.append(msg)
+	}
+}
+
+// 签发恢复令牌
+func (r *resumer) issue(cid, uid int64) {
+	conn, ok := r.load(cid)
+	if !ok {
+		return
+	}
+
+	secret := make([]byte, 16)
+
+	if _, err := rand.Read(secret); err != nil {
+		log.Errorf("generate resume token failed, cid: %d uid: %d err: %v", cid, uid, err)
+		return
+	}
+
+	state := &resumeState{
+		uid:     uid,
+		cid:     cid,
+		token:   strconv.FormatInt(uid, 10) + "." + hex.EncodeToString(secret),
+		packets: make([][]byte, r.gate.opts.resumeBufferSize),
+	}
+
+	r.mu.Lock()
+	old, ok := r.states[uid]
+	if ok && old.timer != nil {
+		old.timer.Stop()
+	}
+	r.states[uid] = state
+	r.reply(conn, 0, state.token)
+	r.mu.Unlock()
+
+	// 原会话已断开且未被恢复，需补发断开连接事件
+	if ok && old.cid == 0 {
+		r.gate.proxy.trigger(r.gate.ctx, cluster.Disconnect, old.lastCID, uid)
+	}
+}
+
+// 移除会话
+func (r *resumer) forget(uid int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if state, ok := r.states[uid]; ok {
+		if state.timer != nil {
+			state.timer.Stop()
+		}
+
+		delete(r.states, uid)
+	}
+}
+
+// 挂起会话，返回是否已接管连接断开的处理
+func (r *resumer) park(conn *resumeConn) bool {
+	r.conns.Delete(conn.ID())
+
+	if conn.replaced.Load() {
+		return true
+	}
+
+	uid := conn.UID()
+	if uid == 0 {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.states[uid]
+	if !ok || state.cid != conn.ID() {
+		return false
+	}
+
+	state.cid, state.lastCID = 0, conn.ID()
+	state.timer = time.AfterFunc(r.gate.opts.resumeTimeout, func() {
+		r.expire(state)
+	})
+
+	return true
+}
+
+// 缓存发送给挂起会话的消息，返回是否存在挂起的会话
+func (r *resumer) hold(uid int64, disconnect bool, msg []byte) bool {
+	r.mu.Lock()
+
+	state, ok := r.states[uid]
+	if !ok || state.cid != 0 {
+		r.mu.Unlock()
+		return false
+	}
+
+	state.append(msg)
+
+	r.mu.Unlock()
+
+	if disconnect {
+		r.expire(state)
+	}
+
+	return true
+}
+
+// 会话过期
+func (r *resumer) expire(state *resumeState) {
+	r.mu.Lock()
+
+	if r.states[state.uid] != state || state.cid != 0 {
+		r.mu.Unlock()
+		return
+	}
+
+	if state.timer != nil {
+		state.timer.Stop()
+	}
+
+	delete(r.states, state.uid)
+
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(r.gate.ctx, 3*time.Second)
+	_ = r.gate.proxy.unbindGate(ctx, state.lastCID, state.uid)
+	cancel()
+
+	r.gate.proxy.trigger(r.gate.ctx, cluster.Disconnect, state.lastCID, state.uid)
+}
+
+// 恢复会话
+func (r *resumer) resume(conn *resumeConn, message *packet.Message) {
+	uid, ok := r.doResume(conn, message)
+	if !ok {
+		r.reply(conn, message.Seq, "")
+
+		if conn.connected.CompareAndSwap(false, true) {
+			r.gate.proxy.trigger(r.gate.ctx, cluster.Connect, conn.ID(), conn.UID())
+		}
+
+		return
+	}
+
+	conn.connected.Store(true)
+
+	r.gate.proxy.trigger(r.gate.ctx, cluster.Reconnect, conn.ID(), uid)
+}
+
+// 执行恢复会话操作
+func (r *resumer) doResume(conn *resumeConn, message *packet.Message) (int64, bool) {
+	if conn.connected.Load() || conn.UID() != 0 {
+		return 0, false
+	}
+
+	token, ack, _ := strings.Cut(string(message.Buffer), ":")
+
+	prefix, _, _ := strings.Cut(token, ".")
+
+	uid, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || uid <= 0 {
+		return 0, false
+	}
+
+	var received uint64
+
+	if ack != "" {
+		if received, err = strconv.ParseUint(ack, 10, 64); err != nil {
+			return 0, false
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.states[uid]
+	if !ok || subtle.ConstantTimeCompare([]byte(state.token), []byte(token)) != 1 {
+		return 0, false
+	}
+
+	packets, ok := state.since(received)
+	if !ok {
+		log.Warnf("resume session failed because some messages have been lost, cid: %d uid: %d", conn.ID(), uid)
+		return 0, false
+	}
+
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+
+	if state.cid != 0 {
+		if old, ok := r.load(state.cid); ok {
+			old.replaced.Store(true)
+			_ = old.Close(true)
+		}
+	}
+
+	if err = r.gate.session.Bind(conn.ID(), uid); err != nil {
+		log.Errorf("resume session failed, cid: %d uid: %d err: %v", conn.ID(), uid, err)
+		return 0, false
+	}
+
+	state.cid = conn.ID()
+
+	r.reply(conn, message.Seq, state.token)
+
+	// 客户端收到令牌后将重置已接收的消息数，补发的消息需重新计入缓冲区，以便再次断线后恢复
+	state.sent = 0
+	clear(state.packets)
+
+	for _, msg := range packets {
+		state.append(msg)
+
+		if err = conn.Conn.Push(msg); err != nil {
+			log.Warnf("replay message failed, cid: %d uid: %d err: %v", conn.ID(), uid, err)
+			break
+		}
+	}
+
+	return uid, true
+}
+
+// 回复恢复消息
+func (r *resumer) reply(conn *resumeConn, seq int32, token string) {
+	msg, err := packet.PackMessage(&packet.Message{
+		Seq:    seq,
+		Route:  r.gate.opts.resumeRoute,
+		Buffer: []byte(token),
+	})
+	if err != nil {
+		log.Errorf("pack message failed: %v", err)
+		return
+	}
+
+	if err = conn.Conn.Push(msg); err != nil {
+		log.Warnf("push message failed, cid: %d uid: %d err: %v", conn.ID(), conn.UID(), err)
+	}
+}
+
+// 关闭所有挂起的会话
+func (r *resumer) close() {
+	r.mu.Lock()
+
+	states := make([]*resumeState, 0, len(r.states))
+
+	for _, state := range r.states {
+		if state.cid == 0 {
+			states = append(states, state)
+		}
+	}
+
+	r.mu.Unlock()
+
+	for _, state := range states {
+		r.expire(state)
+	}
+}
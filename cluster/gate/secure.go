@@ -0,0 +1,177 @@
+package gate
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/crypto/handshake"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+)
+
+type secureConn struct {
+	network.Conn
+	mu          sync.Mutex
+	cipher      *packet.Cipher // 会话密码器
+	established atomic.Bool    // 是否已完成握手
+	timer       *time.Timer    // 握手超时定时器
+}
+
+// Send 发送消息（同步）
+func (c *secureConn) Send(msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cipher == nil {
+		return errors.ErrConnectionNotOpened
+	}
+
+	data, err := packet.Seal(c.cipher, msg)
+	if err != nil {
+		return err
+	}
+
+	return c.Conn.Send(data)
+}
+
+// Push 发送消息（异步）
+// 加密与入队需在同一临界区内完成，以保证数据包的计数器按发送顺序递增
+func (c *secureConn) Push(msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cipher == nil {
+		return errors.ErrConnectionNotOpened
+	}
+
+	data, err := packet.Seal(c.cipher, msg)
+	if err != nil {
+		return err
+	}
+
+	return c.Conn.Push(data)
+}
+
+type securer struct {
+	gate   *Gate
+	server *handshake.Server
+	conns  sync.Map // 连接（连接ID -> *secureConn）
+}
+
+func newSecurer(gate *Gate) *securer {
+	s := &securer{gate: gate}
+
+	if gate.opts.signer != nil {
+		s.server = handshake.NewServer(gate.opts.signer, gate.opts.suites...)
+	}
+
+	return s
+}
+
+// 是否开启握手
+func (s *securer) enabled() bool {
+	return s.server != nil
+}
+
+// 包装连接
+func (s *securer) wrap(conn network.Conn) *secureConn {
+	c := &secureConn{Conn: conn}
+	c.timer = time.AfterFunc(s.gate.opts.handshakeTimeout, func() {
+		if !c.established.Load() {
+			log.Warnf("connection handshake timeout and will be closed, cid: %d", c.ID())
+			_ = c.Close(true)
+		}
+	})
+
+	s.conns.Store(conn.ID(), c)
+
+	return c
+}
+
+// 加载连接
+func (s *securer) load(cid int64) (*secureConn, bool) {
+	if c, ok := s.conns.Load(cid); ok {
+		return c.(*secureConn), true
+	}
+
+	return nil, false
+}
+
+// 移除连接
+func (s *securer) remove(cid int64) (*secureConn, bool) {
+	v, ok := s.conns.LoadAndDelete(cid)
+	if !ok {
+		return nil, false
+	}
+
+	c := v.(*secureConn)
+	c.timer.Stop()
+
+	return c, true
+}
+
+// 处理接收到的数据，返回解密后的数据包；握手消息或非法的数据包返回false
+func (s *securer) receive(c *secureConn, data []byte) ([]byte, bool) {
+	if c.established.Load() {
+		msg, err := packet.Open(c.cipher, data)
+		if err != nil {
+			log.Warnf("open message failed and the connection will be closed, cid: %d uid: %d err: %v", c.ID(), c.UID(), err)
+			_ = c.Close(true)
+			return nil, false
+		}
+
+		return msg, true
+	}
+
+	if err := s.doHandshake(c, data); err != nil {
+		log.Warnf("connection handshake failed and will be closed, cid: %d err: %v", c.ID(), err)
+		_ = c.Close(true)
+		return nil, false
+	}
+
+	// 开启会话恢复时，连接事件将延迟至收到连接的第一条消息时触发
+	if !s.gate.opts.resume {
+		s.gate.proxy.trigger(s.gate.ctx, cluster.Connect, c.ID(), c.UID())
+	}
+
+	return nil, false
+}
+
+// 执行握手
+func (s *securer) doHandshake(c *secureConn, data []byte) error {
+	ok, hello, err := packet.CheckHandshake(data)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.ErrHandshakeFailed
+	}
+
+	reply, cipher, err := s.server.Accept(hello)
+	if err != nil {
+		return err
+	}
+
+	msg, err := packet.PackHandshake(reply)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.Conn.Push(msg); err != nil {
+		return err
+	}
+
+	c.cipher = cipher
+	c.established.Store(true)
+	c.timer.Stop()
+
+	return nil
+}
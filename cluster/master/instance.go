@@ -0,0 +1,153 @@
+package master
+
+import (
+	"context"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/registry"
+	"github.com/dobyte/due/v2/session"
+	"golang.org/x/sync/errgroup"
+)
+
+type Instance struct {
+	ID       string            // 实例ID
+	Kind     cluster.Kind      // 实例类型
+	Name     string            // 实例名称
+	State    cluster.State     // 实例状态；网关与节点为实时状态，微服务为注册中心中的状态
+	Weight   int               // 实例权重
+	Endpoint string            // 实例暴露端点
+	Routes   []registry.Route  // 实例路由列表；仅节点有效
+	Events   []int             // 实例事件列表；仅节点有效
+	Services []string          // 实例服务列表；仅微服务有效
+	Metadata map[string]string // 实例元数据
+	Conns    int64             // 在线连接数；仅网关有效
+	Users    int64             // 在线用户数；仅网关有效
+}
+
+// 拉取集群实例概览
+func (m *Master) fetchInstances(ctx context.Context, kinds ...cluster.Kind) ([]*Instance, error) {
+	if len(kinds) == 0 {
+		kinds = []cluster.Kind{cluster.Gate, cluster.Node, cluster.Mesh}
+	}
+
+	var (
+		instances = make([]*Instance, 0)
+		eg, gctx  = errgroup.WithContext(ctx)
+	)
+
+	for _, kind := range kinds {
+		services, err := m.fetchServices(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, service := range services {
+			instance := &Instance{
+				ID:       service.ID,
+				Kind:     kind,
+				Name:     service.Alias,
+				State:    parseState(service.State),
+				Weight:   service.Weight,
+				Endpoint: service.Endpoint,
+				Routes:   service.Routes,
+				Events:   service.Events,
+				Services: service.Services,
+				Metadata: service.Metadata,
+			}
+
+			instances = append(instances, instance)
+
+			switch kind {
+			case cluster.Gate:
+				eg.Go(func() error {
+					m.fillGateInstance(gctx, instance)
+					return nil
+				})
+			case cluster.Node:
+				eg.Go(func() error {
+					m.fillNodeInstance(gctx, instance)
+					return nil
+				})
+			}
+		}
+	}
+
+	_ = eg.Wait()
+
+	return instances, nil
+}
+
+// 填充网关实例的实时状态与在线人数
+func (m *Master) fillGateInstance(ctx context.Context, instance *Instance) {
+	state, err := m.proxy.gateLinker.GetState(ctx, instance.ID)
+	if err != nil {
+		log.Warnf("get gate state failed, gid: %s err: %v", instance.ID, err)
+		return
+	}
+
+	conns, err := m.proxy.gateLinker.StatGate(ctx, instance.ID, session.Conn)
+	if err != nil {
+		log.Warnf("stat gate conns failed, gid: %s err: %v", instance.ID, err)
+	}
+
+	users, err := m.proxy.gateLinker.StatGate(ctx, instance.ID, session.User)
+	if err != nil {
+		log.Warnf("stat gate users failed, gid: %s err: %v", instance.ID, err)
+	}
+
+	instance.State = state
+	instance.Conns = conns
+	instance.Users = users
+}
+
+// 填充节点实例的实时状态
+func (m *Master) fillNodeInstance(ctx context.Context, instance *Instance) {
+	state, err := m.proxy.nodeLinker.GetState(ctx, instance.ID)
+	if err != nil {
+		log.Warnf("get node state failed, nid: %s err: %v", instance.ID, err)
+		return
+	}
+
+	instance.State = state
+}
+
+// 拉取注册中心中的服务实例列表
+func (m *Master) fetchServices(ctx context.Context, kind cluster.Kind, states ...cluster.State) ([]*registry.ServiceInstance, error) {
+	services, err := m.opts.registry.Services(ctx, kind.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(states) == 0 {
+		return services, nil
+	}
+
+	mp := make(map[string]struct{}, len(states))
+	for _, state := range states {
+		mp[state.String()] = struct{}{}
+	}
+
+	list := make([]*registry.ServiceInstance, 0, len(services))
+	for i := range services {
+		if _, ok := mp[services[i].State]; ok {
+			list = append(list, services[i])
+		}
+	}
+
+	return list, nil
+}
+
+// 解析实例状态
+func parseState(state string) cluster.State {
+	switch state {
+	case cluster.Work.String():
+		return cluster.Work
+	case cluster.Busy.String():
+		return cluster.Busy
+	case cluster.Hang.String():
+		return cluster.Hang
+	default:
+		return cluster.Shut
+	}
+}
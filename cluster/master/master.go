@@ -0,0 +1,167 @@
+package master
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/component"
+	"github.com/dobyte/due/v2/core/info"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/utils/xcall"
+)
+
+type HookHandler func(proxy *Proxy)
+
+type Master struct {
+	component.Base
+	opts   *options
+	ctx    context.Context
+	cancel context.CancelFunc
+	state  atomic.Int32
+	proxy  *Proxy
+	rw     sync.RWMutex
+	hooks  map[cluster.Hook][]HookHandler
+}
+
+func NewMaster(opts ...Option) *Master {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	m := &Master{}
+	m.opts = o
+	m.ctx, m.cancel = context.WithCancel(o.ctx)
+	m.hooks = make(map[cluster.Hook][]HookHandler)
+	m.proxy = newProxy(m)
+	m.state.Store(int32(cluster.Shut))
+
+	return m
+}
+
+// Name 组件名称
+func (m *Master) Name() string {
+	return m.opts.name
+}
+
+// Init 初始化管理服
+func (m *Master) Init() {
+	if m.opts.id == "" {
+		log.Fatal("instance id can not be empty")
+	}
+
+	if m.opts.codec == nil {
+		log.Fatal("codec component is not injected")
+	}
+
+	if m.opts.registry == nil {
+		log.Fatal("registry component is not injected")
+	}
+
+	m.runHookFunc(cluster.Init)
+}
+
+// Start 启动管理服
+func (m *Master) Start() {
+	if !m.state.CompareAndSwap(int32(cluster.Shut), int32(cluster.Work)) {
+		return
+	}
+
+	m.proxy.watch()
+
+	m.printInfo()
+
+	m.runHookFunc(cluster.Start)
+}
+
+// Close 关闭管理服
+func (m *Master) Close() {
+	if !m.state.CompareAndSwap(int32(cluster.Work), int32(cluster.Hang)) {
+		return
+	}
+
+	m.runHookFunc(cluster.Close)
+}
+
+// Destroy 销毁管理服
+func (m *Master) Destroy() {
+	if !m.state.CompareAndSwap(int32(cluster.Hang), int32(cluster.Shut)) {
+		return
+	}
+
+	m.runHookFunc(cluster.Destroy)
+
+	m.cancel()
+}
+
+// Proxy 获取管理服代理
+func (m *Master) Proxy() *Proxy {
+	return m.proxy
+}
+
+// 获取状态
+func (m *Master) getState() cluster.State {
+	return cluster.State(m.state.Load())
+}
+
+// 执行钩子函数
+func (m *Master) runHookFunc(hook cluster.Hook) {
+	m.rw.RLock()
+
+	if handlers, ok := m.hooks[hook]; ok {
+		wg := &sync.WaitGroup{}
+		wg.Add(len(handlers))
+
+		for i := range handlers {
+			handler := handlers[i]
+			xcall.Go(func() {
+				handler(m.proxy)
+				wg.Done()
+			})
+		}
+
+		m.rw.RUnlock()
+
+		wg.Wait()
+	} else {
+		m.rw.RUnlock()
+	}
+}
+
+// 添加钩子监听器
+func (m *Master) addHookListener(hook cluster.Hook, handler HookHandler) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	switch hook {
+	case cluster.Destroy:
+		m.hooks[hook] = append(m.hooks[hook], handler)
+	default:
+		if m.getState() == cluster.Shut {
+			m.hooks[hook] = append(m.hooks[hook], handler)
+		} else {
+			log.Warnf("server is working, can't add hook handler")
+		}
+	}
+}
+
+// 打印组件信息
+func (m *Master) printInfo() {
+	infos := make([]string, 0, 5)
+	infos = append(infos, fmt.Sprintf("ID: %s", m.opts.id))
+	infos = append(infos, fmt.Sprintf("Name: %s", m.Name()))
+	infos = append(infos, fmt.Sprintf("Codec: %s", m.opts.codec.Name()))
+
+	if m.opts.locator != nil {
+		infos = append(infos, fmt.Sprintf("Locator: %s", m.opts.locator.Name()))
+	} else {
+		infos = append(infos, "Locator: -")
+	}
+
+	infos = append(infos, fmt.Sprintf("Registry: %s", m.opts.registry.Name()))
+
+	info.PrintBoxInfo("Master", infos...)
+}
@@ -0,0 +1,120 @@
+package master_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/master"
+	"github.com/dobyte/due/v2/cluster/testcluster"
+	"github.com/dobyte/due/v2/encoding/json"
+	"github.com/dobyte/due/v2/registry/memory"
+)
+
+func TestMaster(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1),
+		testcluster.WithNodes(1, nil),
+	)
+
+	m := master.NewMaster(
+		master.WithCodec(json.DefaultCodec),
+		master.WithRegistry(c.Registry()),
+		master.WithLocator(c.Locator()),
+	)
+	m.Init()
+	m.Start()
+	defer func() {
+		m.Close()
+		m.Destroy()
+	}()
+
+	if _, err := c.Dial(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	// 等待管理服发现集群实例并拉取到网关的在线连接数
+	waitFor(t, func() bool {
+		instances, err := m.Proxy().FetchInstances(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var gates, nodes int
+		for _, instance := range instances {
+			switch {
+			case instance.Kind == cluster.Gate && instance.State == cluster.Work && instance.Conns == 1:
+				gates++
+			case instance.Kind == cluster.Node && instance.State == cluster.Work:
+				nodes++
+			}
+		}
+
+		return gates == 1 && nodes == 1
+	})
+
+	nid := c.Node(0).Proxy().GetID()
+
+	if err := m.Proxy().SetNodeState(ctx, nid, cluster.Hang); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := m.Proxy().GetNodeState(ctx, nid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state != cluster.Hang {
+		t.Fatalf("node state = %v, want %v", state, cluster.Hang)
+	}
+
+	waitFor(t, func() bool {
+		list, err := m.Proxy().FetchNodeList(ctx, cluster.Hang)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return len(list) == 1 && list[0].ID == nid
+	})
+}
+
+func TestMaster_AddHookListener(t *testing.T) {
+	m := master.NewMaster(
+		master.WithCodec(json.DefaultCodec),
+		master.WithRegistry(memory.NewRegistry()),
+	)
+
+	started := make(chan struct{}, 1)
+
+	m.Proxy().AddHookListener(cluster.Start, func(*master.Proxy) { started <- struct{}{} })
+
+	m.Start()
+	defer func() {
+		m.Close()
+		m.Destroy()
+	}()
+
+	select {
+	case <-started:
+	default:
+		t.Fatal("start hook is not executed")
+	}
+}
+
+// 轮询等待条件满足
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(3 * time.Second)
+
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("wait for condition timeout")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
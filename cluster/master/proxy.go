@@ -0,0 +1,155 @@
+package master
+
+import (
+	"context"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/internal/link"
+	"github.com/dobyte/due/v2/registry"
+	"github.com/dobyte/due/v2/session"
+)
+
+type Proxy struct {
+	master     *Master          // 管理服
+	gateLinker *link.GateLinker // 网关链接器
+	nodeLinker *link.NodeLinker // 节点链接器
+}
+
+func newProxy(master *Master) *Proxy {
+	opts := &link.Options{
+		ID:                master.opts.id,
+		Kind:              cluster.Master,
+		Codec:             master.opts.codec,
+		Locator:           master.opts.locator,
+		Registry:          master.opts.registry,
+		Encryptor:         master.opts.encryptor,
+		ConnNum:           master.opts.connNum,
+		CallTimeout:       master.opts.callTimeout,
+		DialTimeout:       master.opts.dialTimeout,
+		DialRetryTimes:    master.opts.dialRetryTimes,
+		WriteTimeout:      master.opts.writeTimeout,
+		WriteQueueSize:    master.opts.writeQueueSize,
+		FaultRecoveryTime: master.opts.faultRecoveryTime,
+	}
+
+	return &Proxy{
+		master:     master,
+		gateLinker: link.NewGateLinker(master.ctx, opts),
+		nodeLinker: link.NewNodeLinker(master.ctx, opts),
+	}
+}
+
+// GetID 获取当前实例ID
+func (p *Proxy) GetID() string {
+	return p.master.opts.id
+}
+
+// GetName 获取当前实例名称
+func (p *Proxy) GetName() string {
+	return p.master.opts.name
+}
+
+// AddHookListener 添加钩子监听器
+func (p *Proxy) AddHookListener(hook cluster.Hook, handler HookHandler) {
+	p.master.addHookListener(hook, handler)
+}
+
+// FetchInstances 拉取集群实例概览，不传kinds时拉取网关、节点、微服务三类实例
+func (p *Proxy) FetchInstances(ctx context.Context, kinds ...cluster.Kind) ([]*Instance, error) {
+	return p.master.fetchInstances(ctx, kinds...)
+}
+
+// FetchGateList 拉取网关列表
+func (p *Proxy) FetchGateList(ctx context.Context, states ...cluster.State) ([]*registry.ServiceInstance, error) {
+	return p.gateLinker.FetchGateList(ctx, states...)
+}
+
+// FetchNodeList 拉取节点列表
+func (p *Proxy) FetchNodeList(ctx context.Context, states ...cluster.State) ([]*registry.ServiceInstance, error) {
+	return p.nodeLinker.FetchNodeList(ctx, states...)
+}
+
+// FetchMeshList 拉取微服务列表
+func (p *Proxy) FetchMeshList(ctx context.Context, states ...cluster.State) ([]*registry.ServiceInstance, error) {
+	return p.master.fetchServices(ctx, cluster.Mesh, states...)
+}
+
+// GetGateState 获取网关状态
+func (p *Proxy) GetGateState(ctx context.Context, gid string) (cluster.State, error) {
+	return p.gateLinker.GetState(ctx, gid)
+}
+
+// SetGateState 设置网关状态
+func (p *Proxy) SetGateState(ctx context.Context, gid string, state cluster.State) error {
+	return p.gateLinker.SetState(ctx, gid, state)
+}
+
+// GetNodeState 获取节点状态
+func (p *Proxy) GetNodeState(ctx context.Context, nid string) (cluster.State, error) {
+	return p.nodeLinker.GetState(ctx, nid)
+}
+
+// SetNodeState 设置节点状态
+// 将节点状态设置为cluster.Hang时，网关将不再为该节点分配无状态路由消息，已绑定的用户不受影响，可用于节点下线前的流量排空
+func (p *Proxy) SetNodeState(ctx context.Context, nid string, state cluster.State) error {
+	return p.nodeLinker.SetState(ctx, nid, state)
+}
+
+// Stat 统计集群会话总数
+func (p *Proxy) Stat(ctx context.Context, kind session.Kind) (int64, error) {
+	return p.gateLinker.Stat(ctx, kind)
+}
+
+// StatGate 统计指定网关的会话总数
+func (p *Proxy) StatGate(ctx context.Context, gid string, kind session.Kind) (int64, error) {
+	return p.gateLinker.StatGate(ctx, gid, kind)
+}
+
+// LocateGate 定位用户所在网关
+func (p *Proxy) LocateGate(ctx context.Context, uid int64) (string, error) {
+	return p.gateLinker.LocateGate(ctx, uid)
+}
+
+// LocateNodes 定位用户所在节点列表
+func (p *Proxy) LocateNodes(ctx context.Context, uid int64) (map[string]string, error) {
+	return p.nodeLinker.LocateNodes(ctx, uid)
+}
+
+// GetIP 获取客户端IP
+func (p *Proxy) GetIP(ctx context.Context, args *cluster.GetIPArgs) (string, error) {
+	return p.gateLinker.GetIP(ctx, args)
+}
+
+// IsOnline 检测是否在线
+func (p *Proxy) IsOnline(ctx context.Context, args *cluster.IsOnlineArgs) (bool, error) {
+	return p.gateLinker.IsOnline(ctx, args)
+}
+
+// Disconnect 断开连接
+// 会话类型为用户且未指定网关ID时，需要注入定位器以定位用户所在网关
+func (p *Proxy) Disconnect(ctx context.Context, args *cluster.DisconnectArgs) error {
+	return p.gateLinker.Disconnect(ctx, args)
+}
+
+// Push 推送消息
+// args.Ack设为true时可获得消息真实发送的情况
+func (p *Proxy) Push(ctx context.Context, args *cluster.PushArgs) error {
+	return p.gateLinker.Push(ctx, args)
+}
+
+// Broadcast 推送广播消息
+// 要想获得推送成功的目标数，需将args.Ack设为true
+func (p *Proxy) Broadcast(ctx context.Context, args *cluster.BroadcastArgs) (int64, error) {
+	return p.gateLinker.Broadcast(ctx, args)
+}
+
+// 开始监听
+func (p *Proxy) watch() {
+	p.gateLinker.WatchUserLocate()
+
+	p.gateLinker.WatchClusterInstance()
+
+	p.nodeLinker.WatchUserLocate()
+
+	p.nodeLinker.WatchClusterInstance()
+}
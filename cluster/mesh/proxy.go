@@ -212,6 +212,7 @@ func (p *Proxy) Deliver(ctx context.Context, args *cluster.DeliverArgs) error {
 	return p.nodeLinker.Deliver(ctx, &link.DeliverArgs{
 		NID:    args.NID,
 		UID:    args.UID,
+		Key:    args.Key,
 		Route:  args.Message.Route,
 		Buffer: args.Message,
 	})
@@ -1,20 +1,31 @@
 package node
 
 import (
+	"context"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/dobyte/due/v2/cluster"
-	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
 )
 
 type Creator func(actor *Actor, args ...any) Processor
 
+type MessageHandler func(msg any) (any, error)
+
 const (
-	unstart   int32 = iota // 未启动
-	started                // 已启动
-	destroyed              // 已销毁
+	unstart    int32 = iota // 未启动
+	started                 // 已启动
+	destroyed               // 已销毁
+	passivated              // 已钝化
+)
+
+const (
+	defaultMailboxSize  = 4096            // 默认邮箱大小
+	defaultStoreTimeout = 3 * time.Second // 默认状态存取超时时间
 )
 
 type Actor struct {
@@ -23,12 +34,29 @@ type Actor struct {
 	state               atomic.Int32                   // 状态
 	routes              map[int32]RouteHandler         // 路由处理器
 	events              map[cluster.Event]EventHandler // 事件处理器
+	dormant             sync.Map                       // 钝化前注册的事件（cluster.Event -> struct{}）
 	defaultRouteHandler RouteHandler                   // 默认路由处理器
+	messageHandler      MessageHandler                 // 消息处理器
 	processor           Processor                      // 处理器
 	rw                  sync.RWMutex                   // 锁
 	mailbox             chan Context                   // 邮箱
 	fnChan              chan func()                    // 调用函数
 	binds               sync.Map                       // 绑定的用户
+	creator             Creator                        // 创建器
+	mu                  sync.Mutex                     // 激活锁
+	epoch               atomic.Int64                   // 激活代数
+	active              atomic.Int64                   // 最后活跃时间
+	idleTimer           *time.Timer                    // 空闲检测定时器
+	done                chan struct{}                  // 销毁信号
+	initing             atomic.Bool                    // 是否处于初始化阶段
+	parent              *Actor                         // 父Actor
+	children            sync.Map                       // 子Actor（PID -> *Actor）
+	restarts            []time.Time                    // 重启时间记录
+}
+
+type answer struct {
+	reply any   // 回复消息
+	err   error // 错误
 }
 
 // ID 获取Actor的ID
@@ -46,9 +74,14 @@ func (a *Actor) Kind() string {
 	return a.opts.kind
 }
 
-// Spawn 衍生出一个Actor
+// Parent 获取父Actor，非通过Actor衍生的Actor返回nil
+func (a *Actor) Parent() *Actor {
+	return a.parent
+}
+
+// Spawn 衍生出一个子Actor
 func (a *Actor) Spawn(creator Creator, opts ...ActorOption) (*Actor, error) {
-	return a.scheduler.spawn(creator, opts...)
+	return a.scheduler.spawn(creator, append(opts, withActorParent(a))...)
 }
 
 // Proxy 获取代理API
@@ -58,14 +91,9 @@ func (a *Actor) Proxy() *Proxy {
 
 // Invoke 调用函数（Actor内线程安全）
 func (a *Actor) Invoke(fn func()) {
-	a.rw.RLock()
-	defer a.rw.RUnlock()
-
-	if a.state.Load() != started {
-		return
-	}
-
-	a.fnChan <- fn
+	a.send(func() {
+		a.fnChan <- fn
+	})
 }
 
 // AfterFunc 延迟调用，与官方的time.AfterFunc用法一致
@@ -74,14 +102,18 @@ func (a *Actor) AfterFunc(d time.Duration, f func()) *Timer {
 		return nil
 	}
 
+	epoch := a.epoch.Load()
+
 	timer := time.AfterFunc(d, func() {
 		a.rw.RLock()
 		defer a.rw.RUnlock()
 
-		if a.state.Load() != started {
+		if a.state.Load() != started || a.epoch.Load() != epoch {
 			return
 		}
 
+		a.touch()
+
 		f()
 	})
 
@@ -94,14 +126,18 @@ func (a *Actor) AfterInvoke(d time.Duration, f func()) *Timer {
 		return nil
 	}
 
+	epoch := a.epoch.Load()
+
 	timer := time.AfterFunc(d, func() {
 		a.rw.RLock()
 		defer a.rw.RUnlock()
 
-		if a.state.Load() != started {
+		if a.state.Load() != started || a.epoch.Load() != epoch {
 			return
 		}
 
+		a.touch()
+
 		a.fnChan <- f
 	})
 
@@ -110,75 +146,100 @@ func (a *Actor) AfterInvoke(d time.Duration, f func()) *Timer {
 
 // SetDefaultRouteHandler 设置默认路由处理器
 func (a *Actor) SetDefaultRouteHandler(handler RouteHandler) {
-	a.rw.RLock()
-	defer a.rw.RUnlock()
-
-	switch a.state.Load() {
-	case unstart:
+	a.setup(func() {
 		a.defaultRouteHandler = handler
-	case started:
-		a.fnChan <- func() {
-			a.defaultRouteHandler = handler
-		}
-	default:
-		// ignore
-	}
+	})
+}
+
+// SetMessageHandler 设置消息处理器，用于处理通过Ask、Tell投递的消息
+func (a *Actor) SetMessageHandler(handler MessageHandler) {
+	a.setup(func() {
+		a.messageHandler = handler
+	})
 }
 
 // AddRouteHandler 添加路由处理器
 func (a *Actor) AddRouteHandler(route int32, handler RouteHandler) {
-	a.rw.RLock()
-	defer a.rw.RUnlock()
-
-	switch a.state.Load() {
-	case unstart:
+	a.setup(func() {
 		a.routes[route] = handler
-	case started:
-		a.fnChan <- func() {
-			a.routes[route] = handler
 
-			if a.opts.dispatch {
-				a.scheduler.routes.Store(route, a.Kind())
-			}
+		if a.opts.dispatch {
+			a.scheduler.routes.Store(route, a.Kind())
 		}
-	default:
-		// ignore
-	}
+	})
 }
 
 // AddEventHandler 添加事件处理器
 func (a *Actor) AddEventHandler(event cluster.Event, handler EventHandler) {
-	a.rw.RLock()
-	defer a.rw.RUnlock()
-
-	switch a.state.Load() {
-	case unstart:
+	a.setup(func() {
 		a.events[event] = handler
-	case started:
-		a.fnChan <- func() {
-			a.events[event] = handler
-		}
-	default:
-		// ignore
-	}
+	})
 }
 
 // Next 投递消息到Actor中进行处理
 func (a *Actor) Next(ctx Context) {
-	a.rw.RLock()
-	defer a.rw.RUnlock()
+	a.send(func() {
+		ctx.storeActor(a)
 
-	if a.state.Load() != started {
-		return
+		ctx.incrVersion()
+
+		ctx.Cancel()
+
+		a.post(ctx)
+	})
+}
+
+// Tell 投递消息到Actor中进行处理，无需等待回复（线程安全）
+func (a *Actor) Tell(msg any) error {
+	if ok := a.send(func() {
+		a.fnChan <- func() {
+			if _, err := a.receive(msg); err != nil {
+				log.Warnf("actor handle message failed, pid: %s err: %v", a.PID(), err)
+			}
+		}
+	}); !ok {
+		return errors.ErrActorDestroyed
 	}
 
-	ctx.storeActor(a)
+	return nil
+}
 
-	ctx.incrVersion()
+// Ask 投递消息到Actor中进行处理，并等待处理结果（线程安全）
+// 消息处理器在Actor内执行；ctx超时或取消、Actor被销毁时将立即返回错误
+// 注意：不可在当前Actor内调用自身的Ask方法，否则将造成死锁
+func (a *Actor) Ask(ctx context.Context, msg any) (any, error) {
+	ch := make(chan *answer, 1)
+
+	if ok := a.send(func() {
+		a.fnChan <- func() {
+			ans := &answer{err: errors.ErrUnknownError}
+
+			defer func() { ch <- ans }()
+
+			if err := ctx.Err(); err != nil {
+				ans.err = err
+				return
+			}
 
-	ctx.Cancel()
+			ans.reply, ans.err = a.receive(msg)
+		}
+	}); !ok {
+		return nil, errors.ErrActorDestroyed
+	}
 
-	a.mailbox <- ctx
+	select {
+	case ans := <-ch:
+		return ans.reply, ans.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-a.done:
+		select {
+		case ans := <-ch:
+			return ans.reply, ans.err
+		default:
+			return nil, errors.ErrActorDestroyed
+		}
+	}
 }
 
 // Deliver 投递消息到当前Actor中进行处理
@@ -188,7 +249,7 @@ func (a *Actor) Deliver(uid int64, message *cluster.Message) error {
 		return err
 	}
 
-	req := a.scheduler.node.reqPool.Get().(*request)
+	req := a.scheduler.node.acquireRequest()
 	req.nid = a.scheduler.node.opts.id
 	req.uid = uid
 	req.message.Seq = message.Seq
@@ -207,28 +268,73 @@ func (a *Actor) Push(uid int64, message *cluster.Message) error {
 		return err
 	}
 
-	a.scheduler.node.router.deliver("", a.scheduler.node.opts.id, a.PID(), 0, uid, message.Seq, message.Route, buf)
+	a.scheduler.node.router.deliver(context.Background(), "", a.scheduler.node.opts.id, a.PID(), 0, uid, message.Seq, message.Route, buf)
 
 	return nil
 }
 
+// DeliverActor 投递消息给指定Actor处理，接收方可通过Reply将消息回复给当前Actor
+func (a *Actor) DeliverActor(ctx context.Context, args *cluster.DeliverActorArgs) error {
+	return a.scheduler.node.proxy.doDeliverActor(ctx, a.PID(), args)
+}
+
 // Destroy 销毁Actor
 func (a *Actor) Destroy() (ok bool) {
-	if ok = a.destroy(); !ok {
+	if ok = a.destroy(true); !ok {
 		return
 	}
 
+	a.scheduler.unregister(a)
+
 	_, ok = a.scheduler.remove(a.Kind(), a.ID())
 	return
 }
 
+// Persist 持久化Actor状态（需在Actor内调用）
+func (a *Actor) Persist() error {
+	if a.state.Load() != started {
+		return nil
+	}
+
+	return a.persist()
+}
+
 // 销毁Actor
-func (a *Actor) destroy() bool {
-	if !a.state.CompareAndSwap(started, destroyed) {
+func (a *Actor) destroy(persist bool) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	state := a.state.Load()
+
+	if state != started && state != passivated {
 		return false
 	}
 
-	a.processor.Destroy()
+	if !a.state.CompareAndSwap(state, destroyed) {
+		return false
+	}
+
+	if a.idleTimer != nil {
+		a.idleTimer.Stop()
+	}
+
+	close(a.done)
+
+	a.stopChildren()
+
+	if a.parent != nil {
+		a.parent.children.CompareAndDelete(a.PID(), a)
+	}
+
+	if state == started {
+		if persist {
+			if err := a.persist(); err != nil {
+				log.Errorf("actor persist failed, pid: %s err: %v", a.PID(), err)
+			}
+		}
+
+		a.processor.Destroy()
+	}
 
 	a.scheduler.batchUnbindActor(func(relations map[int64]map[string]*Actor) {
 		a.binds.Range(func(uid, _ any) bool {
@@ -237,12 +343,14 @@ func (a *Actor) destroy() bool {
 		})
 	})
 
-	a.rw.Lock()
+	a.drainLock()
 	defer a.rw.Unlock()
 
-	close(a.mailbox)
+	if state == started {
+		close(a.mailbox)
 
-	close(a.fnChan)
+		close(a.fnChan)
+	}
 
 	clear(a.routes)
 
@@ -255,6 +363,28 @@ func (a *Actor) destroy() bool {
 	return true
 }
 
+// 获取写锁，等待期间持续排空邮箱，避免阻塞在已满邮箱上的投递方因持有读锁而造成死锁（需在Actor销毁后调用）
+func (a *Actor) drainLock() {
+	for !a.rw.TryLock() {
+		select {
+		case ctx := <-a.mailbox:
+			ctx.compareVersionRecycle(ctx.loadVersion())
+		case <-a.fnChan:
+		default:
+			runtime.Gosched()
+		}
+	}
+}
+
+// 处理消息（需在Actor内调用）
+func (a *Actor) receive(msg any) (any, error) {
+	if a.messageHandler == nil {
+		return nil, errors.ErrMissingMessageHandler
+	}
+
+	return a.messageHandler(msg)
+}
+
 // 绑定用户
 func (a *Actor) bindUser(uid int64) {
 	a.binds.Store(uid, struct{}{})
@@ -266,42 +396,326 @@ func (a *Actor) unbindUser(uid int64) bool {
 	return ok
 }
 
+// 设置处理器，未启动或处于初始化阶段时直接生效，否则投递到Actor内执行
+func (a *Actor) setup(fn func()) {
+	a.rw.RLock()
+	defer a.rw.RUnlock()
+
+	switch a.state.Load() {
+	case unstart:
+		fn()
+	case started:
+		if a.initing.Load() {
+			fn()
+		} else {
+			a.fnChan <- fn
+		}
+	default:
+		// ignore
+	}
+}
+
+// 初始化处理器，初始化阶段注册的处理器将直接生效
+func (a *Actor) initialize() {
+	a.initing.Store(true)
+	defer a.initing.Store(false)
+
+	a.processor.Init()
+}
+
+// 投递操作，Actor处于钝化状态时会先将其重新激活
+func (a *Actor) send(fn func()) bool {
+	for {
+		a.rw.RLock()
+
+		switch a.state.Load() {
+		case started:
+			a.touch()
+
+			fn()
+
+			a.rw.RUnlock()
+
+			return true
+		case passivated:
+			a.rw.RUnlock()
+
+			if !a.activate() {
+				return false
+			}
+		default:
+			a.rw.RUnlock()
+
+			return false
+		}
+	}
+}
+
+// 唤醒Actor
+func (a *Actor) awaken() bool {
+	switch a.state.Load() {
+	case started:
+		return true
+	case passivated:
+		return a.activate()
+	default:
+		return false
+	}
+}
+
+// 唤醒Actor处理事件，钝化的Actor仅在钝化前注册过该事件时才重新激活
+func (a *Actor) awakenFor(event cluster.Event) bool {
+	if a.state.Load() == passivated {
+		if _, ok := a.dormant.Load(event); !ok {
+			return false
+		}
+	}
+
+	return a.awaken()
+}
+
+// 刷新最后活跃时间
+func (a *Actor) touch() {
+	a.active.Store(time.Now().UnixNano())
+}
+
+// 启动Actor
+func (a *Actor) start() {
+	go a.dispatch()
+
+	a.processor.Start()
+
+	if a.opts.passivation <= 0 {
+		return
+	}
+
+	if a.idleTimer == nil {
+		a.idleTimer = time.AfterFunc(a.opts.passivation, a.checkIdle)
+	} else {
+		a.idleTimer.Reset(a.opts.passivation)
+	}
+}
+
+// 重新激活钝化的Actor
+func (a *Actor) activate() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch a.state.Load() {
+	case started:
+		return true
+	case passivated:
+		// continue
+	default:
+		return false
+	}
+
+	a.rw.Lock()
+	a.mailbox = make(chan Context, a.opts.mailboxSize)
+	a.fnChan = make(chan func(), a.opts.mailboxSize)
+	a.state.Store(started)
+	a.rw.Unlock()
+
+	a.touch()
+
+	a.processor = a.creator(a, a.opts.args...)
+
+	a.initialize()
+
+	a.restore()
+
+	a.start()
+
+	return true
+}
+
+// 检测Actor是否空闲
+func (a *Actor) checkIdle() {
+	a.rw.RLock()
+	defer a.rw.RUnlock()
+
+	if a.state.Load() != started {
+		return
+	}
+
+	a.fnChan <- a.passivate
+}
+
+// 钝化Actor（需在Actor内调用）
+func (a *Actor) passivate() {
+	ttl := a.opts.passivation
+
+	if elapsed := time.Duration(time.Now().UnixNano() - a.active.Load()); elapsed < ttl {
+		a.idleTimer.Reset(ttl - elapsed)
+		return
+	}
+
+	if !a.mu.TryLock() {
+		a.idleTimer.Reset(ttl)
+		return
+	}
+	defer a.mu.Unlock()
+
+	if !a.rw.TryLock() {
+		a.idleTimer.Reset(ttl)
+		return
+	}
+
+	if a.state.Load() != started || len(a.mailbox) > 0 || len(a.fnChan) > 0 {
+		a.rw.Unlock()
+		a.idleTimer.Reset(ttl)
+		return
+	}
+
+	a.state.Store(passivated)
+
+	a.epoch.Add(1)
+
+	a.rw.Unlock()
+
+	if err := a.persist(); err != nil {
+		log.Errorf("actor persist failed, pid: %s err: %v", a.PID(), err)
+	}
+
+	a.processor.Destroy()
+
+	a.rw.Lock()
+	defer a.rw.Unlock()
+
+	a.mailbox = nil
+
+	a.fnChan = nil
+
+	clear(a.routes)
+
+	a.dormant.Clear()
+
+	for event := range a.events {
+		a.dormant.Store(event, struct{}{})
+	}
+
+	clear(a.events)
+
+	a.processor = nil
+
+	a.defaultRouteHandler = nil
+
+	a.messageHandler = nil
+}
+
+// 持久化Actor状态
+func (a *Actor) persist() error {
+	if a.opts.store == nil {
+		return nil
+	}
+
+	p, ok := a.processor.(Persistent)
+	if !ok {
+		return nil
+	}
+
+	data, err := p.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultStoreTimeout)
+	defer cancel()
+
+	return a.opts.store.Save(ctx, a.PID(), data)
+}
+
+// 恢复Actor状态
+func (a *Actor) restore() {
+	if a.opts.store == nil {
+		return
+	}
+
+	p, ok := a.processor.(Persistent)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultStoreTimeout)
+	defer cancel()
+
+	data, err := a.opts.store.Load(ctx, a.PID())
+	if err != nil {
+		log.Errorf("actor load snapshot failed, pid: %s err: %v", a.PID(), err)
+		return
+	}
+
+	if data == nil {
+		return
+	}
+
+	if err = p.Restore(data); err != nil {
+		log.Errorf("actor restore snapshot failed, pid: %s err: %v", a.PID(), err)
+	}
+}
+
 // 分发
 func (a *Actor) dispatch() {
+	a.rw.RLock()
+	epoch := a.epoch.Load()
+	mailbox := a.mailbox
+	fnChan := a.fnChan
+	a.rw.RUnlock()
+
 	for {
+		if a.epoch.Load() != epoch {
+			return
+		}
+
 		select {
-		case ctx, ok := <-a.mailbox:
+		case ctx, ok := <-mailbox:
 			if !ok {
 				return
 			}
 
 			version := ctx.loadVersion()
 
+			// Actor销毁后不再处理邮箱中剩余的消息
+			if a.state.Load() == destroyed {
+				ctx.compareVersionRecycle(version)
+				continue
+			}
+
 			if ctx.Kind() == Event {
 				if handler, ok := a.events[ctx.Event()]; ok {
-					xcall.Call(func() { handler(ctx) })
+					a.call(func() { handler(ctx) })
 
 					ctx.compareVersionExecDefer(version)
 				}
 			} else {
-				if handler, ok := a.routes[ctx.Route()]; ok {
-					xcall.Call(func() { handler(ctx) })
+				start, route := time.Now(), ctx.Route()
+
+				if handler, ok := a.routes[route]; ok {
+					a.call(func() { handler(ctx) })
+
+					observeHandleDuration(route, true, start)
 
 					ctx.compareVersionExecDefer(version)
 				} else if a.defaultRouteHandler != nil {
-					xcall.Call(func() { a.defaultRouteHandler(ctx) })
+					a.call(func() { a.defaultRouteHandler(ctx) })
+
+					observeHandleDuration(route, false, start)
 
 					ctx.compareVersionExecDefer(version)
 				}
 			}
 
 			ctx.compareVersionRecycle(version)
-		case handle, ok := <-a.fnChan:
+		case handle, ok := <-fnChan:
 			if !ok {
 				return
 			}
 
-			xcall.Call(handle)
+			if a.state.Load() == destroyed {
+				continue
+			}
+
+			a.call(handle)
 		}
 	}
 }
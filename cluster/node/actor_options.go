@@ -1,17 +1,41 @@
 package node
 
+import (
+	"time"
+
+	"github.com/dobyte/due/v2/log"
+)
+
 type actorOptions struct {
-	id       string // Actor编号
-	kind     string // Actor类型
-	args     []any  // 传递到Processor中的参数
-	wait     bool   // 是否需要等待
-	dispatch bool   // 是否接受调度器调度
+	id            string        // Actor编号
+	kind          string        // Actor类型
+	args          []any         // 传递到Processor中的参数
+	wait          bool          // 是否需要等待
+	dispatch      bool          // 是否接受调度器调度
+	global        bool          // 是否注册到集群Actor目录
+	store         Store         // 状态存储器
+	passivation   time.Duration // 空闲钝化时间
+	strategy      Strategy      // 监督策略
+	maxRestarts   int           // 最大重启次数
+	window        time.Duration // 重启次数统计窗口
+	parent        *Actor        // 父Actor
+	mailboxSize   int           // 邮箱容量
+	overflow      Overflow      // 邮箱溢出策略
+	rejectHandler RejectHandler // 邮箱溢出拒绝处理器
 }
 
 type ActorOption func(o *actorOptions)
 
 func defaultActorOptions() *actorOptions {
-	return &actorOptions{wait: true, dispatch: true}
+	return &actorOptions{
+		wait:        true,
+		dispatch:    true,
+		strategy:    Resume,
+		maxRestarts: defaultMaxRestarts,
+		window:      defaultRestartWindow,
+		mailboxSize: defaultMailboxSize,
+		overflow:    Block,
+	}
 }
 
 // WithActorID 设置Actor编号
@@ -38,3 +62,57 @@ func WithActorNonWait() ActorOption {
 func WithActorNonDispatch() ActorOption {
 	return func(o *actorOptions) { o.dispatch = false }
 }
+
+// WithActorGlobal 设置Actor为全局Actor（注册到集群Actor目录中，其他节点可通过PID向其投递消息）
+func WithActorGlobal() ActorOption {
+	return func(o *actorOptions) { o.global = true }
+}
+
+// WithActorStore 设置Actor状态存储器（Processor需实现Persistent接口）
+func WithActorStore(store Store) ActorOption {
+	return func(o *actorOptions) { o.store = store }
+}
+
+// WithActorPassivation 设置Actor空闲钝化时间（空闲超过该时间后Actor将被钝化，收到消息时自动重新激活）
+func WithActorPassivation(ttl time.Duration) ActorOption {
+	return func(o *actorOptions) { o.passivation = ttl }
+}
+
+// WithActorStrategy 设置Actor监督策略（处理器发生异常时的处理方式，默认为Resume）
+func WithActorStrategy(strategy Strategy) ActorOption {
+	return func(o *actorOptions) { o.strategy = strategy }
+}
+
+// WithActorRestartLimit 设置Actor重启限制（window时间内重启次数超过maxRestarts时Actor将被停止，maxRestarts<=0时不限制）
+func WithActorRestartLimit(maxRestarts int, window time.Duration) ActorOption {
+	return func(o *actorOptions) { o.maxRestarts, o.window = maxRestarts, window }
+}
+
+// WithActorMailboxSize 设置Actor邮箱容量（同时作用于消息队列与调用函数队列，默认为4096）
+func WithActorMailboxSize(size int) ActorOption {
+	return func(o *actorOptions) {
+		if size > 0 {
+			o.mailboxSize = size
+		} else {
+			log.Warnf("the specified mailbox size is less than or equal to zero and will be ignored")
+		}
+	}
+}
+
+// WithActorOverflow 设置Actor邮箱溢出策略（仅作用于路由消息与事件，默认为Block）
+// 溢出策略为Reject时，可传入拒绝处理器，拒绝处理器将在投递方线程中同步执行，可在其中按业务协议回复客户端codes.TooManyRequests
+// 未传入拒绝处理器时，将以cluster.CodeMessage回复客户端codes.TooManyRequests
+func WithActorOverflow(overflow Overflow, handler ...RejectHandler) ActorOption {
+	return func(o *actorOptions) {
+		o.overflow = overflow
+
+		if len(handler) > 0 {
+			o.rejectHandler = handler[0]
+		}
+	}
+}
+
+// 设置父Actor
+func withActorParent(parent *Actor) ActorOption {
+	return func(o *actorOptions) { o.parent = parent }
+}
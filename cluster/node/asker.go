@@ -0,0 +1,82 @@
+package node
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/jinzhu/copier"
+)
+
+// 请求方临时PID的类型，用于接收Actor的回复
+const askerKind = "$asker"
+
+type asker struct {
+	node  *Node
+	seq   atomic.Uint64
+	calls sync.Map // 等待回复的请求（临时PID -> chan any）
+}
+
+func newAsker(node *Node) *asker {
+	return &asker{node: node}
+}
+
+// 投递消息给Actor处理，并等待Actor通过Reply或Response回复的消息
+func (a *asker) ask(ctx context.Context, args *cluster.DeliverActorArgs, reply any) error {
+	if _, ok := ctx.Deadline(); !ok && a.node.opts.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.node.opts.callTimeout)
+		defer cancel()
+	}
+
+	pid := askerKind + "/" + strconv.FormatUint(a.seq.Add(1), 10)
+	ch := make(chan any, 1)
+
+	a.calls.Store(pid, ch)
+	defer a.calls.Delete(pid)
+
+	if err := a.node.proxy.doDeliverActor(ctx, pid, args); err != nil {
+		return err
+	}
+
+	select {
+	case data := <-ch:
+		return a.parse(data, reply)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 应答请求，pid不属于等待中的请求时返回false
+func (a *asker) answer(pid string, data any) bool {
+	ch, ok := a.calls.LoadAndDelete(pid)
+	if !ok {
+		return false
+	}
+
+	ch.(chan any) <- data
+
+	return true
+}
+
+// 解析回复消息
+func (a *asker) parse(data, reply any) error {
+	if reply == nil {
+		return nil
+	}
+
+	msg, ok := data.([]byte)
+	if !ok {
+		return copier.CopyWithOption(reply, data, copier.Option{
+			DeepCopy: true,
+		})
+	}
+
+	if len(msg) == 0 {
+		return nil
+	}
+
+	return a.node.opts.codec.Unmarshal(msg, reply)
+}
@@ -0,0 +1,117 @@
+package node
+
+import (
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/core/cpu"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/registry"
+)
+
+// 繁忙状态恢复比例，所有指标回落至阈值的该比例以下时恢复为Work状态，避免状态频繁抖动
+const busyRecoveryRatio = 0.8
+
+type load struct {
+	inflight int64   // 处理中的请求数
+	actors   int64   // Actor数量
+	cpu      float64 // 进程CPU使用率（百分比）
+}
+
+// 从对象池获取请求对象，并计入处理中的请求数，回收时扣除
+func (n *Node) acquireRequest() *request {
+	req := n.reqPool.Get().(*request)
+	req.counted = true
+	n.inflight.Add(1)
+
+	return req
+}
+
+// 定时上报负载
+func (n *Node) reportLoad() {
+	if n.opts.loadInterval <= 0 {
+		return
+	}
+
+	sampler := cpu.NewSampler()
+	ticker := time.NewTicker(n.opts.loadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-ticker.C:
+			switch n.getState() {
+			case cluster.Work, cluster.Busy:
+			default:
+				continue
+			}
+
+			l := load{
+				inflight: n.inflight.Load(),
+				actors:   n.scheduler.count.Load(),
+				cpu:      sampler.Usage(),
+			}
+
+			n.checkBusy(l)
+
+			if err := n.doReportLoad(l); err != nil {
+				log.Errorf("report node load failed: %v", err)
+			}
+		}
+	}
+}
+
+// 检测繁忙状态
+// 仅自动切换Work与Busy状态，不干预手动设置的状态
+func (n *Node) checkBusy(l load) {
+	switch {
+	case n.exceedBusy(l, 1):
+		if n.state.CompareAndSwap(int32(cluster.Work), int32(cluster.Busy)) {
+			n.autoBusy.Store(true)
+			log.Warnf("node is busy, inflight: %d actors: %d cpu: %.2f%%", l.inflight, l.actors, l.cpu)
+		}
+	case !n.exceedBusy(l, busyRecoveryRatio):
+		if n.autoBusy.Load() && n.state.CompareAndSwap(int32(cluster.Busy), int32(cluster.Work)) {
+			n.autoBusy.Store(false)
+			log.Infof("node is recovered from busy, inflight: %d actors: %d cpu: %.2f%%", l.inflight, l.actors, l.cpu)
+		}
+	}
+}
+
+// 是否超出繁忙阈值
+func (n *Node) exceedBusy(l load, ratio float64) bool {
+	if n.opts.busyInflight > 0 && float64(l.inflight) >= float64(n.opts.busyInflight)*ratio {
+		return true
+	}
+
+	if n.opts.busyActors > 0 && float64(l.actors) >= float64(n.opts.busyActors)*ratio {
+		return true
+	}
+
+	if n.opts.busyCPU > 0 && l.cpu >= n.opts.busyCPU*ratio {
+		return true
+	}
+
+	return false
+}
+
+// 执行负载上报
+func (n *Node) doReportLoad(l load) error {
+	return n.doRefreshServiceInstances(func(instance *registry.ServiceInstance) {
+		if instance.Kind != cluster.Node.String() {
+			return
+		}
+
+		metadata := make(map[string]string, len(n.opts.metadata)+3)
+		maps.Copy(metadata, n.opts.metadata)
+		metadata[cluster.LoadInflightKey] = strconv.FormatInt(l.inflight, 10)
+		metadata[cluster.LoadActorsKey] = strconv.FormatInt(l.actors, 10)
+		metadata[cluster.LoadCPUKey] = strconv.FormatFloat(l.cpu, 'f', 2, 64)
+
+		instance.Metadata = metadata
+	})
+}
@@ -0,0 +1,90 @@
+package node
+
+import (
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/codes"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+)
+
+type Overflow int
+
+const (
+	Block      Overflow = iota // 邮箱已满时阻塞等待
+	DropNewest                 // 邮箱已满时丢弃最新的消息
+	DropOldest                 // 邮箱已满时丢弃最旧的消息
+	Reject                     // 邮箱已满时拒绝最新的消息，并交由拒绝处理器回复codes.TooManyRequests；未设置拒绝处理器时默认回复cluster.CodeMessage
+)
+
+type RejectHandler func(ctx Context, code *codes.Code)
+
+// MailboxDepth 获取邮箱中待处理的消息数
+func (a *Actor) MailboxDepth() int {
+	a.rw.RLock()
+	defer a.rw.RUnlock()
+
+	return len(a.mailbox)
+}
+
+// MailboxSize 获取邮箱容量
+func (a *Actor) MailboxSize() int {
+	return a.opts.mailboxSize
+}
+
+// 投递消息到邮箱，邮箱已满时按溢出策略进行处理
+func (a *Actor) post(ctx Context) {
+	metrics.NodeActorMailboxDepth.Observe(float64(len(a.mailbox)), a.Kind())
+
+	switch a.opts.overflow {
+	case DropNewest, Reject:
+		select {
+		case a.mailbox <- ctx:
+		default:
+			a.reject(ctx)
+		}
+	case DropOldest:
+		for {
+			select {
+			case a.mailbox <- ctx:
+				return
+			default:
+				select {
+				case old := <-a.mailbox:
+					a.discard(old)
+				default:
+				}
+			}
+		}
+	default:
+		a.mailbox <- ctx
+	}
+}
+
+// 拒绝消息
+func (a *Actor) reject(ctx Context) {
+	if a.opts.overflow == Reject && ctx.Kind() == Request {
+		if a.opts.rejectHandler != nil {
+			a.opts.rejectHandler(ctx, codes.TooManyRequests)
+		} else if err := ctx.Response(&cluster.CodeMessage{
+			Code:    codes.TooManyRequests.Code(),
+			Message: codes.TooManyRequests.Message(),
+		}); err != nil {
+			log.Warnf("actor reply reject message failed, pid: %s uid: %d route: %d err: %v", a.PID(), ctx.UID(), ctx.Route(), err)
+		}
+	}
+
+	a.discard(ctx)
+}
+
+// 丢弃消息
+func (a *Actor) discard(ctx Context) {
+	metrics.NodeActorMailboxDropped.Add(1, a.Kind())
+
+	if ctx.Kind() == Request {
+		log.Warnf("actor mailbox is full and the message will be discarded, pid: %s uid: %d route: %d", a.PID(), ctx.UID(), ctx.Route())
+	} else {
+		log.Warnf("actor mailbox is full and the event will be discarded, pid: %s uid: %d event: %v", a.PID(), ctx.UID(), ctx.Event())
+	}
+
+	ctx.compareVersionRecycle(ctx.loadVersion())
+}
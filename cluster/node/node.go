@@ -32,6 +32,8 @@ type Node struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 	state       atomic.Int32
+	autoBusy    atomic.Bool
+	inflight    atomic.Int64
 	evtPool     *sync.Pool
 	reqPool     *sync.Pool
 	router      *Router
@@ -42,9 +44,11 @@ type Node struct {
 	linker      *node.Server
 	fnChan      chan func()
 	scheduler   *Scheduler
+	asker       *asker
 	transporter transport.Server
 	wg          *sync.WaitGroup
 	rw          sync.RWMutex
+	imu         sync.Mutex
 	hooks       map[cluster.Hook][]HookHandler
 }
 
@@ -61,6 +65,7 @@ func NewNode(opts ...Option) *Node {
 	n.router = newRouter(n)
 	n.trigger = newTrigger(n)
 	n.scheduler = newScheduler(n)
+	n.asker = newAsker(n)
 	n.hooks = make(map[cluster.Hook][]HookHandler)
 	n.services = make([]*serviceEntity, 0)
 	n.instances = make([]*registry.ServiceInstance, 0)
@@ -132,6 +137,8 @@ func (n *Node) Start() {
 
 	go n.dispatch()
 
+	go n.reportLoad()
+
 	n.printInfo()
 
 	n.runHookFunc(cluster.Start)
@@ -160,6 +167,8 @@ func (n *Node) Destroy() {
 
 	n.runHookFunc(cluster.Destroy)
 
+	n.proxy.gateLinker.Close()
+
 	n.deregisterServiceInstances()
 
 	n.stopLinkServer()
@@ -279,17 +288,11 @@ func (n *Node) stopTransportServer() {
 
 // 注册服务实例
 func (n *Node) registerServiceInstances() {
-	routes := make([]registry.Route, 0, len(n.router.routes))
-	events := make([]int, 0, len(n.trigger.events))
+	n.imu.Lock()
+	defer n.imu.Unlock()
 
-	for _, entity := range n.router.routes {
-		routes = append(routes, registry.Route{
-			ID:         entity.route,
-			Internal:   entity.options.Internal,
-			Stateful:   entity.options.Stateful,
-			Authorized: entity.options.Authorized,
-		})
-	}
+	routes := n.router.exportRoutes()
+	events := make([]int, 0, len(n.trigger.events))
 
 	for evt := range n.trigger.events {
 		events = append(events, int(evt))
@@ -373,9 +376,16 @@ func (n *Node) doRegisterServiceInstances() error {
 }
 
 // 执行刷新实例状态操作
-func (n *Node) doRefreshServiceInstances() error {
+func (n *Node) doRefreshServiceInstances(fns ...func(instance *registry.ServiceInstance)) error {
+	n.imu.Lock()
+	defer n.imu.Unlock()
+
 	for _, instance := range n.instances {
 		instance.State = n.getState().String()
+
+		for _, fn := range fns {
+			fn(instance)
+		}
 	}
 
 	return n.doRegisterServiceInstances()
@@ -389,6 +399,7 @@ func (n *Node) getState() cluster.State {
 // 更新状态
 func (n *Node) setState(state cluster.State) error {
 	n.state.Store(int32(state))
+	n.autoBusy.Store(false)
 
 	return n.doRefreshServiceInstances()
 }
@@ -5,9 +5,11 @@ import (
 	"maps"
 	"time"
 
+	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/crypto"
 	"github.com/dobyte/due/v2/encoding"
 	"github.com/dobyte/due/v2/etc"
+	"github.com/dobyte/due/v2/internal/breaker"
 	"github.com/dobyte/due/v2/locate"
 	"github.com/dobyte/due/v2/log"
 	"github.com/dobyte/due/v2/registry"
@@ -17,17 +19,21 @@ import (
 )
 
 const (
-	defaultName              = "node"  // 默认节点名称
-	defaultCodec             = "proto" // 默认编解码器名称
-	defaultWeight            = 1       // 默认权重
-	defaultAddr              = ":0"    // 连接器监听地址
-	defaultConnNum           = 5       // 默认连接数
-	defaultCallTimeout       = "3s"    // 默认调用超时时间
-	defaultDialTimeout       = "3s"    // 默认拨号超时时间
-	defaultDialRetryTimes    = 3       // 默认拨号重试次数
-	defaultWriteTimeout      = "0s"    // 默认写入超时时间
-	defaultWriteQueueSize    = 2048    // 默认写入队列大小
-	defaultFaultRecoveryTime = "5s"    // 默认故障恢复时间
+	defaultName              = "node"         // 默认节点名称
+	defaultCodec             = "proto"        // 默认编解码器名称
+	defaultWeight            = 1              // 默认权重
+	defaultDispatch          = cluster.Random // 默认的无状态路由分发策略
+	defaultAddr              = ":0"           // 连接器监听地址
+	defaultConnNum           = 5              // 默认连接数
+	defaultCallTimeout       = "3s"           // 默认调用超时时间
+	defaultDialTimeout       = "3s"           // 默认拨号超时时间
+	defaultDialRetryTimes    = 3              // 默认拨号重试次数
+	defaultWriteTimeout      = "0s"           // 默认写入超时时间
+	defaultWriteQueueSize    = 2048           // 默认写入队列大小
+	defaultFaultRecoveryTime = "5s"           // 默认故障恢复时间
+	defaultLoadInterval      = "0s"           // 默认负载上报间隔
+	defaultBatchWindow       = "0s"           // 默认批量推送合并窗口
+	defaultBatchSize         = 64 * 1024      // 默认批量推送合并字节数上限
 )
 
 const (
@@ -36,6 +42,7 @@ const (
 	defaultCodecKey             = "etc.cluster.node.codec"
 	defaultWeightKey            = "etc.cluster.node.weight"
 	defaultMetadataKey          = "etc.cluster.node.metadata"
+	defaultDispatchKey          = "etc.cluster.node.dispatch"
 	defaultAddrKey              = "etc.cluster.node.addr"
 	defaultExposeKey            = "etc.cluster.node.expose"
 	defaultConnNumKey           = "etc.cluster.node.connNum"
@@ -45,6 +52,13 @@ const (
 	defaultWriteTimeoutKey      = "etc.cluster.node.writeTimeout"
 	defaultWriteQueueSizeKey    = "etc.cluster.node.writeQueueSize"
 	defaultFaultRecoveryTimeKey = "etc.cluster.node.faultRecoveryTime"
+	defaultBreakerKey           = "etc.cluster.node.breaker"
+	defaultLoadIntervalKey      = "etc.cluster.node.load.interval"
+	defaultBusyInflightKey      = "etc.cluster.node.load.busyInflight"
+	defaultBusyActorsKey        = "etc.cluster.node.load.busyActors"
+	defaultBusyCPUKey           = "etc.cluster.node.load.busyCPU"
+	defaultBatchWindowKey       = "etc.cluster.node.batch.window"
+	defaultBatchSizeKey         = "etc.cluster.node.batch.size"
 )
 
 // SchedulingModel 调度模型
@@ -53,25 +67,33 @@ type SchedulingModel string
 type Option func(o *options)
 
 type options struct {
-	ctx               context.Context       // 上下文
-	id                string                // 实例ID
-	name              string                // 实例名称；相同实例名称的节点，用户只能绑定其中一个
-	codec             encoding.Codec        // 编解码器
-	weight            int                   // 服务器权重
-	locator           locate.Locator        // 用户定位器
-	registry          registry.Registry     // 服务注册器
-	encryptor         crypto.Encryptor      // 消息加密器
-	transporter       transport.Transporter // 消息传输器
-	metadata          map[string]string     // 元数据
-	addr              string                // 内部RPC监听地址
-	expose            bool                  // 内部RPC是否暴露到公网
-	connNum           int                   // 内部RPC拨号连接数
-	callTimeout       time.Duration         // 内部RPC调用超时时间
-	dialTimeout       time.Duration         // 内部RPC拨号超时时间
-	dialRetryTimes    int                   // 内部RPC拨号重试次数
-	writeTimeout      time.Duration         // 内部RPC写入超时时间
-	writeQueueSize    int32                 // 内部RPC写入队列大小
-	faultRecoveryTime time.Duration         // 内部RPC故障恢复时间
+	ctx               context.Context        // 上下文
+	id                string                 // 实例ID
+	name              string                 // 实例名称；相同实例名称的节点，用户只能绑定其中一个
+	codec             encoding.Codec         // 编解码器
+	weight            int                    // 服务器权重
+	locator           locate.Locator         // 用户定位器
+	registry          registry.Registry      // 服务注册器
+	encryptor         crypto.Encryptor       // 消息加密器
+	transporter       transport.Transporter  // 消息传输器
+	metadata          map[string]string      // 元数据
+	dispatch          cluster.Dispatch       // 无状态路由消息分发策略
+	addr              string                 // 内部RPC监听地址
+	expose            bool                   // 内部RPC是否暴露到公网
+	connNum           int                    // 内部RPC拨号连接数
+	callTimeout       time.Duration          // 内部RPC调用超时时间
+	dialTimeout       time.Duration          // 内部RPC拨号超时时间
+	dialRetryTimes    int                    // 内部RPC拨号重试次数
+	writeTimeout      time.Duration          // 内部RPC写入超时时间
+	writeQueueSize    int32                  // 内部RPC写入队列大小
+	faultRecoveryTime time.Duration          // 内部RPC故障恢复时间
+	breaker           cluster.BreakerOptions // 内部RPC熔断配置
+	loadInterval      time.Duration          // 负载上报间隔，为0时不上报
+	busyInflight      int64                  // 处理中请求数的繁忙阈值，为0时不检测
+	busyActors        int64                  // Actor数量的繁忙阈值，为0时不检测
+	busyCPU           float64                // CPU使用率（百分比）的繁忙阈值，为0时不检测
+	batchWindow       time.Duration          // 推送网关的批量合并窗口，为0时不开启批量推送
+	batchSize         int                    // 推送网关的批量合并字节数上限
 }
 
 func defaultOptions() *options {
@@ -104,6 +126,12 @@ func defaultOptions() *options {
 		opts.weight = defaultWeight
 	}
 
+	if strategy := etc.Get(defaultDispatchKey).String(); strategy != "" {
+		opts.dispatch = cluster.Dispatch(strategy)
+	} else {
+		opts.dispatch = defaultDispatch
+	}
+
 	if addr := etc.Get(defaultAddrKey, defaultAddr).String(); addr != "" {
 		opts.addr = addr
 	} else {
@@ -152,6 +180,30 @@ func defaultOptions() *options {
 		opts.faultRecoveryTime = xconv.Duration(defaultFaultRecoveryTime)
 	}
 
+	opts.breaker = breaker.LoadOptions(defaultBreakerKey)
+
+	if loadInterval := etc.Get(defaultLoadIntervalKey, defaultLoadInterval).Duration(); loadInterval >= 0 {
+		opts.loadInterval = loadInterval
+	} else {
+		opts.loadInterval = xconv.Duration(defaultLoadInterval)
+	}
+
+	opts.busyInflight = max(etc.Get(defaultBusyInflightKey).Int64(), 0)
+	opts.busyActors = max(etc.Get(defaultBusyActorsKey).Int64(), 0)
+	opts.busyCPU = max(etc.Get(defaultBusyCPUKey).Float64(), 0)
+
+	if batchWindow := etc.Get(defaultBatchWindowKey, defaultBatchWindow).Duration(); batchWindow >= 0 {
+		opts.batchWindow = batchWindow
+	} else {
+		opts.batchWindow = xconv.Duration(defaultBatchWindow)
+	}
+
+	if batchSize := etc.Get(defaultBatchSizeKey, defaultBatchSize).Int(); batchSize > 0 {
+		opts.batchSize = batchSize
+	} else {
+		opts.batchSize = defaultBatchSize
+	}
+
 	if err := etc.Get(defaultMetadataKey).Scan(&opts.metadata); err != nil {
 		log.Warnf("scan metadata failed: %v", err)
 	}
@@ -181,7 +233,19 @@ func WithName(name string) Option {
 	}
 }
 
+// WithDispatch 设置无状态路由消息分发策略
+func WithDispatch(dispatch cluster.Dispatch) Option {
+	return func(o *options) {
+		if dispatch != "" {
+			o.dispatch = dispatch
+		} else {
+			log.Warnf("the specified dispatch is empty and will be ignored")
+		}
+	}
+}
+
 // WithAddr 设置监听地址
+// 支持TCP地址、Unix域套接字（unix:///path/to/due.sock）及进程内传输（inproc://name）
 func WithAddr(addr string) Option {
 	return func(o *options) {
 		if addr != "" {
@@ -365,3 +429,52 @@ func WithFaultRecoveryTime(faultRecoveryTime time.Duration) Option {
 		}
 	}
 }
+
+// WithLoadInterval 设置负载上报间隔
+// 节点会按此间隔将负载信息写入注册中心的元数据中，供最小负载分发策略使用；为0时不上报
+func WithLoadInterval(loadInterval time.Duration) Option {
+	return func(o *options) {
+		if loadInterval >= 0 {
+			o.loadInterval = loadInterval
+		} else {
+			log.Warnf("the specified loadInterval is less than zero and will be ignored")
+		}
+	}
+}
+
+// WithBusyThreshold 设置繁忙阈值
+// 上报负载时任一指标达到阈值，节点将自动切换为Busy状态；所有指标回落至阈值的80%以下后自动恢复为Work状态
+// inflight: 处理中请求数；actors: Actor数量；cpu: CPU使用率（百分比）。为0时不检测对应指标
+func WithBusyThreshold(inflight, actors int64, cpu float64) Option {
+	return func(o *options) {
+		if inflight >= 0 && actors >= 0 && cpu >= 0 {
+			o.busyInflight = inflight
+			o.busyActors = actors
+			o.busyCPU = cpu
+		} else {
+			log.Warnf("the specified busy threshold is less than zero and will be ignored")
+		}
+	}
+}
+
+// WithBreaker 设置内部RPC熔断配置
+// 连续失败次数阈值与延迟阈值均为0时不开启熔断
+func WithBreaker(opts cluster.BreakerOptions) Option {
+	return func(o *options) {
+		o.breaker = opts
+	}
+}
+
+// WithBatch 设置推送网关的批量合并参数
+// 开启后，无需回执的推送、组播、广播及频道消息将在窗口期内按网关合并为一个批量包发送，合并字节数达到上限时立即发送；窗口为0时不开启批量推送
+// 需要回执的消息将先发送当前批次，再经由同一连接发送，以保证同一目标的消息顺序；窗口到期时的发送错误仅记录日志并计入due_link_batch_flush_failures_total指标
+func WithBatch(window time.Duration, size int) Option {
+	return func(o *options) {
+		if window >= 0 && size > 0 {
+			o.batchWindow = window
+			o.batchSize = size
+		} else {
+			log.Warnf("the specified batch window is less than zero or size is not greater than zero and will be ignored")
+		}
+	}
+}
@@ -19,3 +19,17 @@ func (b *BaseProcessor) Start() {}
 
 // Destroy 销毁回调
 func (b *BaseProcessor) Destroy() {}
+
+type Persistent interface {
+	// Snapshot 生成状态快照，Actor销毁或钝化前回调
+	Snapshot() ([]byte, error)
+	// Restore 恢复状态快照，Actor初始化后、启动前回调
+	Restore(data []byte) error
+}
+
+type Supervised interface {
+	// PreRestart 重启前回调，在发生异常的处理器上执行
+	PreRestart(reason any)
+	// PostRestart 重启后回调，在重新创建的处理器上执行
+	PostRestart(reason any)
+}
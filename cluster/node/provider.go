@@ -47,11 +47,21 @@ func (p *provider) Deliver(ctx context.Context, gid, nid string, cid, uid int64,
 		}
 	}
 
-	p.node.router.deliver(gid, nid, "", cid, uid, msg.Seq, msg.Route, msg.Buffer)
+	p.node.router.deliver(ctx, gid, nid, "", cid, uid, msg.Seq, msg.Route, msg.Buffer)
 
 	return nil
 }
 
+// DeliverActor 投递Actor消息
+func (p *provider) DeliverActor(ctx context.Context, nid string, uid int64, src, dst string, message []byte) error {
+	msg, err := packet.UnpackMessage(message)
+	if err != nil {
+		return err
+	}
+
+	return p.node.scheduler.deliver(nid, src, dst, uid, msg.Seq, msg.Route, msg.Buffer)
+}
+
 // GetState 获取状态
 func (p *provider) GetState() (cluster.State, error) {
 	return p.node.getState(), nil
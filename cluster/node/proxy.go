@@ -36,11 +36,15 @@ func newProxy(node *Node) *Proxy {
 			WriteTimeout:      node.opts.writeTimeout,
 			WriteQueueSize:    node.opts.writeQueueSize,
 			FaultRecoveryTime: node.opts.faultRecoveryTime,
+			Breaker:           node.opts.breaker,
+			BatchWindow:       node.opts.batchWindow,
+			BatchSize:         node.opts.batchSize,
 		}),
 		nodeLinker: link.NewNodeLinker(node.opts.ctx, &link.Options{
 			ID:                node.opts.id,
 			Kind:              cluster.Node,
 			Codec:             node.opts.codec,
+			Dispatch:          node.opts.dispatch,
 			Locator:           node.opts.locator,
 			Registry:          node.opts.registry,
 			Encryptor:         node.opts.encryptor,
@@ -51,6 +55,7 @@ func newProxy(node *Node) *Proxy {
 			WriteTimeout:      node.opts.writeTimeout,
 			WriteQueueSize:    node.opts.writeQueueSize,
 			FaultRecoveryTime: node.opts.faultRecoveryTime,
+			Breaker:           node.opts.breaker,
 			WaitHandler:       node.addWait,
 			DoneHandler:       node.doneWait,
 		}),
@@ -97,6 +102,11 @@ func (p *Proxy) AddRouteHandler(route int32, handler RouteHandler, opts ...Route
 	p.node.router.AddRouteHandler(route, handler, opts...)
 }
 
+// RemoveRouteHandler 移除路由处理器
+func (p *Proxy) RemoveRouteHandler(routes ...int32) {
+	p.node.router.RemoveRouteHandler(routes...)
+}
+
 // SetDefaultRouteHandler 设置默认路由处理器，所有未注册的路由均走默认路由处理器
 func (p *Proxy) SetDefaultRouteHandler(handler RouteHandler) {
 	p.node.router.SetDefaultRouteHandler(handler)
@@ -221,6 +231,53 @@ func (p *Proxy) UnbindActor(uid int64, kind string) {
 	p.node.scheduler.unbindActor(uid, kind)
 }
 
+// LocateActor 定位Actor所在节点
+func (p *Proxy) LocateActor(ctx context.Context, pid string) (string, error) {
+	if _, ok := p.node.scheduler.doLoad(pid); ok {
+		return p.node.opts.id, nil
+	}
+
+	return p.nodeLinker.LocateActor(ctx, pid)
+}
+
+// DeliverActor 投递消息给Actor处理，支持投递给其他节点上的全局Actor
+func (p *Proxy) DeliverActor(ctx context.Context, args *cluster.DeliverActorArgs) error {
+	return p.doDeliverActor(ctx, "", args)
+}
+
+// AskActor 投递消息给Actor处理，并等待Actor通过Reply或Response回复的消息，支持投递给其他节点上的全局Actor
+// ctx未设置超时时间时使用内部RPC调用超时时间；reply为回复消息的解析目标，为nil时忽略回复内容
+func (p *Proxy) AskActor(ctx context.Context, args *cluster.DeliverActorArgs, reply any) error {
+	return p.node.asker.ask(ctx, args, reply)
+}
+
+// 投递消息给Actor处理
+func (p *Proxy) doDeliverActor(ctx context.Context, src string, args *cluster.DeliverActorArgs) error {
+	if args.PID == "" || args.Message == nil {
+		return errors.ErrInvalidArgument
+	}
+
+	if args.NID == "" || args.NID == p.node.opts.id {
+		buf, err := p.PackBuffer(args.Message.Data)
+		if err != nil {
+			return err
+		}
+
+		err = p.node.scheduler.deliver(p.node.opts.id, src, args.PID, args.UID, args.Message.Seq, args.Message.Route, buf)
+		if args.NID != "" || !errors.Is(err, errors.ErrNotFoundActor) {
+			return err
+		}
+	}
+
+	return p.nodeLinker.DeliverActor(ctx, &link.DeliverActorArgs{
+		NID:    args.NID,
+		UID:    args.UID,
+		Src:    src,
+		PID:    args.PID,
+		Buffer: args.Message,
+	})
+}
+
 // PackMessage 打包消息
 func (p *Proxy) PackMessage(message *cluster.Message) ([]byte, error) {
 	buf, err := p.gateLinker.PackMessage(message, true)
@@ -299,6 +356,7 @@ func (p *Proxy) Deliver(ctx context.Context, args *cluster.DeliverArgs) error {
 	return p.nodeLinker.Deliver(ctx, &link.DeliverArgs{
 		NID:    args.NID,
 		UID:    args.UID,
+		Key:    args.Key,
 		Route:  args.Message.Route,
 		Buffer: args.Message,
 	})
@@ -344,6 +402,13 @@ func (p *Proxy) Kill(kind, id string) bool {
 	return p.node.scheduler.kill(kind, id)
 }
 
+// RangeActors 遍历当前节点上的所有Actor，可结合Actor.MailboxDepth定位热点Actor
+func (p *Proxy) RangeActors(fn func(actor *Actor) bool) {
+	p.node.scheduler.actors.Range(func(_, actor any) bool {
+		return fn(actor.(*Actor))
+	})
+}
+
 // Actor 获取Actor
 func (p *Proxy) Actor(kind, id string) (*Actor, bool) {
 	return p.node.scheduler.load(kind, id)
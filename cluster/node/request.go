@@ -34,6 +34,7 @@ type request struct {
 	version atomic.Int32     // 版本号
 	chain   *chains.Chain    // 调用链
 	actor   atomic.Value     // 当前Actor
+	counted bool             // 是否已计入处理中的请求数，克隆的请求不计入
 }
 
 // GID 获取网关ID
@@ -388,6 +389,25 @@ func (r *request) Reply(message *cluster.Message) error {
 			Message: message,
 		})
 	case r.pid != "": // 来源于Actor
+		if r.nid != "" && r.nid != r.node.opts.id {
+			var src string
+
+			if actor, ok := r.actor.Load().(*Actor); ok && actor != nil {
+				src = actor.PID()
+			}
+
+			return r.node.proxy.doDeliverActor(r.ctx, src, &cluster.DeliverActorArgs{
+				NID:     r.nid,
+				PID:     r.pid,
+				UID:     r.uid,
+				Message: message,
+			})
+		}
+
+		if r.node.asker.answer(r.pid, message.Data) {
+			return nil
+		}
+
 		if actor, ok := r.node.scheduler.doLoad(r.pid); ok {
 			return actor.Deliver(r.uid, message)
 		}
@@ -462,6 +482,11 @@ func (r *request) compareVersionRecycle(version int32) {
 			xcall.Call(func() { r.node.router.postRouteHandler(r) })
 		}
 
+		if r.counted {
+			r.counted = false
+			r.node.inflight.Add(-1)
+		}
+
 		r.reset()
 		r.node.reqPool.Put(r)
 	}
@@ -1,10 +1,17 @@
 package node
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+	"github.com/dobyte/due/v2/registry"
+	"github.com/dobyte/due/v2/tracing"
 	"github.com/dobyte/due/v2/utils/xcall"
 )
 
@@ -39,6 +46,7 @@ var (
 
 type Router struct {
 	node                *Node
+	rw                  sync.RWMutex
 	routes              map[int32]*routeEntity
 	reqChan             chan *request
 	preRouteHandler     RouteHandler
@@ -61,24 +69,34 @@ func newRouter(node *Node) *Router {
 }
 
 // AddRouteHandler 添加路由处理器
+// 节点运行期间可添加新路由或替换已有路由的处理器，变更将同步刷新到注册中心，网关通过服务监听感知路由变化
 func (r *Router) AddRouteHandler(route int32, handler RouteHandler, opts ...RouteOptions) {
-	if r.node.getState() != cluster.Shut {
-		log.Warnf("the node server is working, can't add route handler")
-		return
+	entity := &routeEntity{
+		route:   route,
+		handler: handler,
 	}
 
 	if len(opts) > 0 {
-		r.routes[route] = &routeEntity{
-			route:   route,
-			handler: handler,
-			options: opts[0],
-		}
-	} else {
-		r.routes[route] = &routeEntity{
-			route:   route,
-			handler: handler,
-		}
+		entity.options = opts[0]
+	}
+
+	r.rw.Lock()
+	r.routes[route] = entity
+	r.rw.Unlock()
+
+	r.refreshRoutes()
+}
+
+// RemoveRouteHandler 移除路由处理器
+// 节点运行期间移除路由后，变更将同步刷新到注册中心，网关将不再向当前节点分发该路由的消息
+func (r *Router) RemoveRouteHandler(routes ...int32) {
+	r.rw.Lock()
+	for _, route := range routes {
+		delete(r.routes, route)
 	}
+	r.rw.Unlock()
+
+	r.refreshRoutes()
 }
 
 // SetDefaultRouteHandler 设置默认路由处理器，所有未注册的路由均走默认路由处理器
@@ -118,7 +136,7 @@ func (r *Router) SetPostRouteHandler(handler RouteHandler) {
 
 // CheckRouteStateful 是否为有状态路由
 func (r *Router) CheckRouteStateful(route int32) (stateful bool, exist bool) {
-	if entity, ok := r.routes[route]; ok {
+	if entity, ok := r.loadRoute(route); ok {
 		exist, stateful = ok, entity.options.Stateful
 	}
 	return
@@ -138,9 +156,9 @@ func (r *Router) Group(groups ...func(group *RouterGroup)) *RouterGroup {
 	return group
 }
 
-func (r *Router) deliver(gid, nid, pid string, cid, uid int64, seq, route int32, data any) {
-	req := r.node.reqPool.Get().(*request)
-	req.ctx = context.Background()
+func (r *Router) deliver(ctx context.Context, gid, nid, pid string, cid, uid int64, seq, route int32, data any) {
+	req := r.node.acquireRequest()
+	req.ctx = ctx
 	req.gid = gid
 	req.nid = nid
 	req.pid = pid
@@ -163,13 +181,21 @@ func (r *Router) close() {
 func (r *Router) handle(req *request) {
 	version := req.incrVersion()
 
-	route, ok := r.routes[req.message.Route]
+	route, ok := r.loadRoute(req.message.Route)
 	if !ok && r.defaultRouteHandler == nil {
 		req.compareVersionRecycle(version)
 		log.Warnf("message routing does not register handler function, route: %v", req.message.Route)
 		return
 	}
 
+	start, routeID := time.Now(), req.message.Route
+
+	ctx, span := tracing.Start(req.ctx, "node.handle",
+		tracing.WithSpanKind(tracing.SpanKindServer),
+		tracing.WithAttributes(tracing.Int("route", int(routeID)), tracing.Int64("cid", req.cid), tracing.Int64("uid", req.uid)),
+	)
+	req.ctx = ctx
+
 	if r.preRouteHandler != nil {
 		xcall.Call(func() { r.preRouteHandler(req) })
 	}
@@ -178,6 +204,8 @@ func (r *Router) handle(req *request) {
 		if len(route.options.Middlewares) > 0 {
 			middleware := &Middleware{index: -1, middlewares: route.options.Middlewares, routeHandler: route.handler}
 			middleware.Next(req)
+			observeHandleDuration(routeID, ok, start)
+			span.End()
 			return
 		} else {
 			xcall.Call(func() { route.handler(req) })
@@ -186,11 +214,68 @@ func (r *Router) handle(req *request) {
 		xcall.Call(func() { r.defaultRouteHandler(req) })
 	}
 
+	observeHandleDuration(routeID, ok, start)
+
+	span.End()
+
 	req.compareVersionExecDefer(version)
 
 	req.compareVersionRecycle(version)
 }
 
+// 加载路由
+func (r *Router) loadRoute(route int32) (*routeEntity, bool) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	entity, ok := r.routes[route]
+
+	return entity, ok
+}
+
+// 导出注册中心的路由列表，按路由ID排序以保证路由未变化时导出的列表一致
+func (r *Router) exportRoutes() []registry.Route {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	routes := make([]registry.Route, 0, len(r.routes))
+
+	for _, entity := range r.routes {
+		routes = append(routes, registry.Route{
+			ID:         entity.route,
+			Internal:   entity.options.Internal,
+			Stateful:   entity.options.Stateful,
+			Authorized: entity.options.Authorized,
+		})
+	}
+
+	slices.SortFunc(routes, func(a, b registry.Route) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return routes
+}
+
+// 刷新注册中心的路由列表，节点未运行时无需刷新
+func (r *Router) refreshRoutes() {
+	if r.node.getState() == cluster.Shut {
+		return
+	}
+
+	if err := r.node.doRefreshServiceInstances(func(instance *registry.ServiceInstance) {
+		if instance.Kind == cluster.Node.String() {
+			instance.Routes = r.exportRoutes()
+		}
+	}); err != nil {
+		log.Errorf("refresh cluster routes failed: %v", err)
+	}
+}
+
+// 统计路由消息处理耗时，由默认路由处理器处理的消息归为未注册的路由
+func observeHandleDuration(route int32, known bool, start time.Time) {
+	metrics.NodeHandleDuration.Observe(time.Since(start).Seconds(), metrics.Route(route, known))
+}
+
 type RouterGroup struct {
 	router      *Router
 	middlewares []MiddlewareHandler
@@ -1,16 +1,20 @@
 package node
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
+
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/log"
-	"sync"
 )
 
 type Scheduler struct {
 	node      *Node
 	mu        sync.Mutex
 	actors    sync.Map
+	count     atomic.Int64
 	routes    sync.Map
 	kinds     sync.Map
 	rw        sync.RWMutex
@@ -34,11 +38,15 @@ func (s *Scheduler) spawn(creator Creator, opts ...ActorOption) (*Actor, error)
 	act := &Actor{}
 	act.opts = o
 	act.scheduler = s
+	act.parent = o.parent
+	act.creator = creator
 	act.state.Store(started)
 	act.routes = make(map[int32]RouteHandler)
 	act.events = make(map[cluster.Event]EventHandler, 3)
-	act.mailbox = make(chan Context, 4096)
-	act.fnChan = make(chan func(), 4096)
+	act.mailbox = make(chan Context, o.mailboxSize)
+	act.fnChan = make(chan func(), o.mailboxSize)
+	act.done = make(chan struct{})
+	act.touch()
 	act.processor = creator(act, o.args...)
 
 	s.mu.Lock()
@@ -52,7 +60,7 @@ func (s *Scheduler) spawn(creator Creator, opts ...ActorOption) (*Actor, error)
 		s.node.addWait()
 	}
 
-	act.processor.Init()
+	act.initialize()
 
 	if act.opts.dispatch {
 		if _, ok := s.kinds.Load(act.Kind()); !ok {
@@ -65,12 +73,23 @@ func (s *Scheduler) spawn(creator Creator, opts ...ActorOption) (*Actor, error)
 	}
 
 	s.actors.Store(act.PID(), act)
+	s.count.Add(1)
+
+	if act.parent != nil {
+		act.parent.children.Store(act.PID(), act)
+	}
 
 	s.mu.Unlock()
 
-	go act.dispatch()
+	act.restore()
+
+	act.start()
 
-	act.processor.Start()
+	if act.opts.global {
+		if err := s.node.proxy.nodeLinker.BindActor(context.Background(), act.PID(), s.node.opts.id); err != nil {
+			log.Errorf("bind actor location failed, pid: %s err: %v", act.PID(), err)
+		}
+	}
 
 	return act, nil
 }
@@ -82,7 +101,14 @@ func (s *Scheduler) kill(kind, id string) bool {
 		return false
 	}
 
-	ok = act.destroy()
+	return s.doKill(act, true)
+}
+
+// 执行杀死Actor
+func (s *Scheduler) doKill(act *Actor, persist bool) bool {
+	ok := act.destroy(persist)
+
+	s.unregister(act)
 
 	if act.opts.wait {
 		s.node.doneWait()
@@ -102,6 +128,7 @@ func (s *Scheduler) remove(kind, id string) (*Actor, bool) {
 	}
 
 	s.actors.Delete(act.PID())
+	s.count.Add(-1)
 
 	for _, relations := range s.relations {
 		if a, ok := relations[act.Kind()]; ok && a == act {
@@ -112,6 +139,42 @@ func (s *Scheduler) remove(kind, id string) (*Actor, bool) {
 	return act, true
 }
 
+// 从集群Actor目录中注销Actor
+func (s *Scheduler) unregister(act *Actor) {
+	if !act.opts.global {
+		return
+	}
+
+	if err := s.node.proxy.nodeLinker.UnbindActor(context.Background(), act.PID(), s.node.opts.id); err != nil {
+		log.Errorf("unbind actor location failed, pid: %s err: %v", act.PID(), err)
+	}
+}
+
+// 投递消息到本地Actor
+func (s *Scheduler) deliver(nid, src, dst string, uid int64, seq, route int32, data any) error {
+	if s.node.asker.answer(dst, data) {
+		return nil
+	}
+
+	act, ok := s.doLoad(dst)
+	if !ok {
+		return errors.ErrNotFoundActor
+	}
+
+	req := s.node.acquireRequest()
+	req.ctx = context.Background()
+	req.nid = nid
+	req.pid = src
+	req.uid = uid
+	req.message.Seq = seq
+	req.message.Route = route
+	req.message.Data = data
+
+	act.Next(req)
+
+	return nil
+}
+
 // 加载Actor
 func (s *Scheduler) load(kind, id string) (*Actor, bool) {
 	return s.doLoad(kind + "/" + id)
@@ -137,6 +200,10 @@ func (s *Scheduler) bindActor(uid int64, kind, id string) error {
 		return errors.ErrNotFoundActor
 	}
 
+	if !act.awaken() {
+		return errors.ErrNotFoundActor
+	}
+
 	act.bindUser(uid)
 
 	s.rw.Lock()
@@ -232,7 +299,8 @@ func (s *Scheduler) dispatchRequest(ctx Context) error {
 // 分发事件
 func (s *Scheduler) dispatchEvent(ctx Context) error {
 	s.actors.Range(func(_, actor any) bool {
-		if act := actor.(*Actor); act.opts.dispatch {
+		// 处于钝化状态的Actor仅在注册了该事件时才被重新激活后再处理事件
+		if act := actor.(*Actor); act.opts.dispatch && act.awakenFor(ctx.Event()) {
 			act.Next(ctx)
 		}
 
@@ -0,0 +1,115 @@
+package node
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dobyte/due/v2/cache"
+	"github.com/dobyte/due/v2/errors"
+)
+
+const defaultStoreKeyPrefix = "actor:"
+
+type Store interface {
+	// Load 加载Actor状态快照，快照不存在时返回nil
+	Load(ctx context.Context, pid string) ([]byte, error)
+	// Save 保存Actor状态快照
+	Save(ctx context.Context, pid string, data []byte) error
+	// Delete 删除Actor状态快照
+	Delete(ctx context.Context, pid string) error
+}
+
+type cacheStore struct {
+	cache      cache.Cache
+	expiration []time.Duration
+}
+
+var _ Store = &cacheStore{}
+
+// NewCacheStore 创建基于缓存的Actor状态存储器
+func NewCacheStore(cache cache.Cache, expiration ...time.Duration) Store {
+	return &cacheStore{cache: cache, expiration: expiration}
+}
+
+// Load 加载Actor状态快照
+func (s *cacheStore) Load(ctx context.Context, pid string) ([]byte, error) {
+	data, err := s.cache.Get(ctx, defaultStoreKeyPrefix+pid).Bytes()
+	if err != nil {
+		if errors.Is(err, errors.ErrNil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Save 保存Actor状态快照
+func (s *cacheStore) Save(ctx context.Context, pid string, data []byte) error {
+	return s.cache.Set(ctx, defaultStoreKeyPrefix+pid, data, s.expiration...)
+}
+
+// Delete 删除Actor状态快照
+func (s *cacheStore) Delete(ctx context.Context, pid string) error {
+	_, err := s.cache.Delete(ctx, defaultStoreKeyPrefix+pid)
+	return err
+}
+
+type fileStore struct {
+	dir string
+}
+
+var _ Store = &fileStore{}
+
+// NewFileStore 创建基于本地文件的Actor状态存储器
+func NewFileStore(dir string) Store {
+	return &fileStore{dir: dir}
+}
+
+// Load 加载Actor状态快照
+func (s *fileStore) Load(_ context.Context, pid string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(pid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Save 保存Actor状态快照
+func (s *fileStore) Save(_ context.Context, pid string, data []byte) error {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	path := s.path(pid)
+	temp := path + ".tmp"
+
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, path)
+}
+
+// Delete 删除Actor状态快照
+func (s *fileStore) Delete(_ context.Context, pid string) error {
+	if err := os.Remove(s.path(pid)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// 获取快照文件路径
+func (s *fileStore) path(pid string) string {
+	return filepath.Join(s.dir, url.PathEscape(pid)+".snapshot")
+}
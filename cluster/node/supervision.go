@@ -0,0 +1,155 @@
+package node
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/utils/xcall"
+)
+
+type Strategy int
+
+const (
+	Resume   Strategy = iota // 忽略异常，Actor继续运行
+	Restart                  // 重启Actor，重新创建处理器并恢复最近一次持久化的状态
+	Stop                     // 停止Actor
+	Escalate                 // 停止Actor，并将异常上报给父Actor，由父Actor按自身的监督策略进行处理
+)
+
+const (
+	defaultMaxRestarts   = 10          // 默认最大重启次数
+	defaultRestartWindow = time.Minute // 默认重启次数统计窗口
+)
+
+// 安全调用函数，发生异常时交由监督策略进行处理（需在Actor内调用）
+func (a *Actor) call(fn func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			switch err.(type) {
+			case runtime.Error:
+				log.Panic(err)
+			default:
+				log.Panicf("panic error: %v", err)
+			}
+
+			a.fail(err)
+		}
+	}()
+
+	fn()
+}
+
+// 处理异常（需在Actor内调用）
+func (a *Actor) fail(reason any) {
+	if a.state.Load() != started {
+		return
+	}
+
+	switch a.opts.strategy {
+	case Restart:
+		if a.allowRestart() {
+			a.restart(reason)
+		} else {
+			log.Errorf("actor restart too many times and will be stopped, pid: %s", a.PID())
+
+			a.stop()
+		}
+	case Stop:
+		a.stop()
+	case Escalate:
+		a.stop()
+
+		if parent := a.parent; parent != nil {
+			parent.Invoke(func() { parent.fail(reason) })
+		}
+	default:
+		// resume
+	}
+}
+
+// 检测是否允许重启
+func (a *Actor) allowRestart() bool {
+	if a.opts.maxRestarts <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	restarts := a.restarts[:0]
+
+	for _, t := range a.restarts {
+		if a.opts.window <= 0 || now.Sub(t) < a.opts.window {
+			restarts = append(restarts, t)
+		}
+	}
+
+	if len(restarts) >= a.opts.maxRestarts {
+		a.restarts = restarts
+		return false
+	}
+
+	a.restarts = append(restarts, now)
+
+	return true
+}
+
+// 重启Actor（需在Actor内调用）
+func (a *Actor) restart(reason any) {
+	a.mu.Lock()
+
+	if a.state.Load() != started {
+		a.mu.Unlock()
+		return
+	}
+
+	if p, ok := a.processor.(Supervised); ok {
+		xcall.Call(func() { p.PreRestart(reason) })
+	}
+
+	a.stopChildren()
+
+	xcall.Call(a.processor.Destroy)
+
+	clear(a.routes)
+
+	clear(a.events)
+
+	a.defaultRouteHandler = nil
+
+	a.messageHandler = nil
+
+	a.processor = a.creator(a, a.opts.args...)
+
+	xcall.Call(a.initialize)
+
+	a.restore()
+
+	a.mu.Unlock()
+
+	if p, ok := a.processor.(Supervised); ok {
+		xcall.Call(func() { p.PostRestart(reason) })
+	}
+
+	xcall.Call(a.processor.Start)
+}
+
+// 停止Actor，异常状态下不再进行持久化（需在Actor内调用）
+// 销毁需等待邮箱排空后获取写锁，为避免阻塞当前Actor，销毁过程将在独立协程中执行
+func (a *Actor) stop() {
+	if _, ok := a.scheduler.remove(a.Kind(), a.ID()); ok {
+		go a.scheduler.doKill(a, false)
+	}
+}
+
+// 停止所有子Actor
+func (a *Actor) stopChildren() {
+	a.children.Range(func(_, child any) bool {
+		act := child.(*Actor)
+
+		if _, ok := a.scheduler.remove(act.Kind(), act.ID()); ok {
+			a.scheduler.doKill(act, true)
+		}
+
+		return true
+	})
+}
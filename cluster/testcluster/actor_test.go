@@ -0,0 +1,628 @@
+package testcluster_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/cluster/testcluster"
+	"github.com/dobyte/due/v2/codes"
+	"github.com/dobyte/due/v2/errors"
+)
+
+const (
+	incr = "incr"
+	load = "load"
+)
+
+// 计数器处理器
+type counter struct {
+	node.BaseProcessor
+	actor       *node.Actor
+	count       int
+	disconnects chan int64
+}
+
+func newCounter(disconnects chan int64) node.Creator {
+	return func(actor *node.Actor, _ ...any) node.Processor {
+		return &counter{actor: actor, disconnects: disconnects}
+	}
+}
+
+func (c *counter) Init() {
+	c.actor.SetMessageHandler(func(msg any) (any, error) {
+		if msg == incr {
+			c.count++
+		}
+
+		return c.count, nil
+	})
+
+	c.actor.AddEventHandler(cluster.Disconnect, func(ctx node.Context) {
+		c.disconnects <- ctx.CID()
+	})
+}
+
+func (c *counter) Snapshot() ([]byte, error) {
+	return []byte(strconv.Itoa(c.count)), nil
+}
+
+func (c *counter) Restore(data []byte) (err error) {
+	c.count, err = strconv.Atoi(string(data))
+	return
+}
+
+// 内存状态存储器
+type store struct {
+	rw    sync.Mutex
+	data  map[string][]byte
+	saves chan string
+}
+
+func newStore() *store {
+	return &store{data: make(map[string][]byte), saves: make(chan string, 16)}
+}
+
+func (s *store) Load(_ context.Context, pid string) ([]byte, error) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	return s.data[pid], nil
+}
+
+func (s *store) Save(_ context.Context, pid string, data []byte) error {
+	s.rw.Lock()
+	s.data[pid] = data
+	s.rw.Unlock()
+
+	s.saves <- pid
+
+	return nil
+}
+
+func (s *store) Delete(_ context.Context, pid string) error {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	delete(s.data, pid)
+
+	return nil
+}
+
+// 等待Actor钝化时保存的状态快照
+func (s *store) waitSave(t *testing.T, pid string) {
+	t.Helper()
+
+	select {
+	case id := <-s.saves:
+		if id != pid {
+			t.Fatalf("saved pid = %s, want %s", id, pid)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("wait actor passivation timeout")
+	}
+}
+
+func ask(t *testing.T, act *node.Actor, msg string, want int) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	reply, err := act.Ask(ctx, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reply != want {
+		t.Fatalf("reply = %v, want %v", reply, want)
+	}
+}
+
+func TestActor_Passivation(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1),
+		testcluster.WithNodes(1, func(proxy *node.Proxy) {
+			proxy.AddEventHandler(cluster.Disconnect, func(ctx node.Context) { _ = ctx.Next() })
+		}),
+	)
+
+	s := newStore()
+	disconnects := make(chan int64, 1)
+
+	act, err := c.Node(0).Proxy().Spawn(newCounter(disconnects),
+		node.WithActorKind("counter"),
+		node.WithActorID("1"),
+		node.WithActorStore(s),
+		node.WithActorPassivation(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Node(0).Proxy().Kill(act.Kind(), act.ID())
+
+	if err = act.Tell(incr); err != nil {
+		t.Fatal(err)
+	}
+
+	ask(t, act, incr, 2)
+
+	s.waitSave(t, act.PID())
+
+	// 钝化后收到消息将重新激活并恢复状态
+	ask(t, act, incr, 3)
+
+	s.waitSave(t, act.PID())
+
+	// 钝化后收到事件同样将重新激活
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cid := client.CID()
+
+	if err = client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case id := <-disconnects:
+		if id != cid {
+			t.Fatalf("disconnect cid = %d, want %d", id, cid)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("passivated actor missed disconnect event")
+	}
+
+	ask(t, act, load, 3)
+}
+
+func TestActor_PassivationIgnoresUnhandledEvent(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1),
+		testcluster.WithNodes(1, func(proxy *node.Proxy) {
+			proxy.AddEventHandler(cluster.Connect, func(ctx node.Context) { _ = ctx.Next() })
+			proxy.AddEventHandler(cluster.Disconnect, func(ctx node.Context) { _ = ctx.Next() })
+		}),
+	)
+
+	var creates atomic.Int32
+
+	s := newStore()
+	creator := newCounter(make(chan int64, 1))
+
+	act, err := c.Node(0).Proxy().Spawn(func(actor *node.Actor, args ...any) node.Processor {
+		creates.Add(1)
+		return creator(actor, args...)
+	},
+		node.WithActorKind("counter"),
+		node.WithActorID("1"),
+		node.WithActorStore(s),
+		node.WithActorPassivation(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Node(0).Proxy().Kill(act.Kind(), act.ID())
+
+	s.waitSave(t, act.PID())
+
+	// 未注册连接事件的钝化Actor不会被连接事件唤醒
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	time.Sleep(100 * time.Millisecond)
+
+	if n := creates.Load(); n != 1 {
+		t.Fatalf("creates = %d, want 1", n)
+	}
+}
+
+const echoActorRoute int32 = 10
+
+// 回显处理器
+type echo struct {
+	node.BaseProcessor
+	actor *node.Actor
+}
+
+func newEcho(actor *node.Actor, _ ...any) node.Processor {
+	return &echo{actor: actor}
+}
+
+func (e *echo) Init() {
+	e.actor.AddRouteHandler(echoActorRoute, func(ctx node.Context) {
+		req := &message{}
+
+		if err := ctx.Parse(req); err != nil {
+			return
+		}
+
+		_ = ctx.Response(&message{Text: req.Text + "@" + ctx.Proxy().GetID()})
+	})
+}
+
+func TestActor_AskActor(t *testing.T) {
+	c := testcluster.Run(t, testcluster.WithNodes(3, nil))
+
+	spawn := func(i int) {
+		t.Helper()
+
+		if _, err := c.Node(i).Proxy().Spawn(newEcho,
+			node.WithActorKind("echo"),
+			node.WithActorID("1"),
+			node.WithActorGlobal(),
+			node.WithActorNonWait(),
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ask := func(want string) {
+		t.Helper()
+
+		var (
+			err   error
+			reply = &message{}
+		)
+
+		// 节点间的服务发现存在延迟
+		for i := 0; i < 50; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			err = c.Node(0).Proxy().AskActor(ctx, &cluster.DeliverActorArgs{
+				PID:     "echo/1",
+				Message: &cluster.Message{Route: echoActorRoute, Data: &message{Text: "hello"}},
+			}, reply)
+			cancel()
+
+			if err == nil {
+				break
+			}
+
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if reply.Text != want {
+			t.Fatalf("reply = %s, want %s", reply.Text, want)
+		}
+	}
+
+	// 本地Actor
+	spawn(0)
+	ask("hello@node-1")
+	c.Node(0).Proxy().Kill("echo", "1")
+
+	// 其他节点上的Actor
+	spawn(1)
+	ask("hello@node-2")
+
+	// Actor迁移后，过期的位置缓存将被清除并重新定位
+	c.Node(1).Proxy().Kill("echo", "1")
+	spawn(2)
+	ask("hello@node-3")
+}
+
+func TestActor_Ask(t *testing.T) {
+	c := testcluster.Run(t, testcluster.WithNodes(1, nil))
+
+	block := make(chan struct{})
+
+	act, err := c.Node(0).Proxy().Spawn(func(actor *node.Actor, _ ...any) node.Processor {
+		count := 0
+
+		actor.SetMessageHandler(func(msg any) (any, error) {
+			switch msg {
+			case incr:
+				count++
+			case "block":
+				<-block
+			}
+
+			return count, nil
+		})
+
+		return &node.BaseProcessor{}
+	}, node.WithActorKind("counter"), node.WithActorID("1"), node.WithActorNonWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 消息处理器运行在Actor协程中，并发投递无需加锁
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := act.Tell(incr); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ask(t, act, load, 100)
+
+	// 处理超时时立即返回
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err = act.Ask(ctx, "block"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ask err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(block)
+
+	c.Node(0).Proxy().Kill(act.Kind(), act.ID())
+
+	if _, err = act.Ask(context.Background(), load); !errors.Is(err, errors.ErrActorDestroyed) {
+		t.Fatalf("ask err = %v, want %v", err, errors.ErrActorDestroyed)
+	}
+
+	if err = act.Tell(incr); !errors.Is(err, errors.ErrActorDestroyed) {
+		t.Fatalf("tell err = %v, want %v", err, errors.ErrActorDestroyed)
+	}
+}
+
+func TestActor_Supervision(t *testing.T) {
+	c := testcluster.Run(t, testcluster.WithNodes(1, nil))
+
+	proxy := c.Node(0).Proxy()
+	block := make(chan struct{})
+
+	parent, err := proxy.Spawn(func(actor *node.Actor, _ ...any) node.Processor {
+		actor.SetMessageHandler(func(msg any) (any, error) {
+			if msg == "block" {
+				<-block
+				panic("boom")
+			}
+
+			return nil, nil
+		})
+
+		return &node.BaseProcessor{}
+	},
+		node.WithActorKind("parent"),
+		node.WithActorID("1"),
+		node.WithActorStrategy(node.Stop),
+		node.WithActorMailboxSize(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child, err := parent.Spawn(func(actor *node.Actor, _ ...any) node.Processor {
+		return &node.BaseProcessor{}
+	}, node.WithActorKind("child"), node.WithActorID("1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = parent.Tell("block"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 邮箱已满时投递方将阻塞，Actor停止时需将其释放
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_ = parent.Tell("ping")
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	close(block)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("senders blocked after actor stopped")
+	}
+
+	// 父Actor停止时子Actor同时停止
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		_, ok1 := proxy.Actor(parent.Kind(), parent.ID())
+		_, ok2 := proxy.Actor(child.Kind(), child.ID())
+
+		if !ok1 && !ok2 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("actor is not stopped, parent: %v child: %v", ok1, ok2)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err = child.Tell(incr); !errors.Is(err, errors.ErrActorDestroyed) {
+		t.Fatalf("tell err = %v, want %v", err, errors.ErrActorDestroyed)
+	}
+}
+
+const (
+	loginRoute int32 = 20
+	roomRoute  int32 = 21
+)
+
+// 房间处理器，处理每条消息前等待放行信号
+type room struct {
+	node.BaseProcessor
+	actor   *node.Actor
+	started chan string
+	block   chan struct{}
+}
+
+func (r *room) Init() {
+	r.actor.AddRouteHandler(roomRoute, func(ctx node.Context) {
+		req := &message{}
+
+		if err := ctx.Parse(req); err != nil {
+			return
+		}
+
+		r.started <- req.Text
+
+		<-r.block
+
+		_ = ctx.Response(req)
+	})
+}
+
+func spawnRoom(t *testing.T, proxy *node.Proxy, overflow node.Overflow) *room {
+	t.Helper()
+
+	r := &room{started: make(chan string, 16), block: make(chan struct{})}
+
+	if _, err := proxy.Spawn(func(actor *node.Actor, _ ...any) node.Processor {
+		r.actor = actor
+		return r
+	},
+		node.WithActorKind("room"),
+		node.WithActorID("1"),
+		node.WithActorNonWait(),
+		node.WithActorMailboxSize(1),
+		node.WithActorOverflow(overflow),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func TestActor_MailboxOverflow(t *testing.T) {
+	tests := []struct {
+		overflow node.Overflow
+		handled  []string
+	}{
+		{overflow: node.DropNewest, handled: []string{"1", "2"}},
+		{overflow: node.DropOldest, handled: []string{"1", "3"}},
+		{overflow: node.Reject, handled: []string{"1", "2"}},
+	}
+
+	for _, tt := range tests {
+		c := testcluster.Run(t, testcluster.WithNodes(1, nil))
+
+		proxy := c.Node(0).Proxy()
+		r := spawnRoom(t, proxy, tt.overflow)
+
+		deliver := func(text string) {
+			if err := r.actor.Deliver(1, &cluster.Message{Route: roomRoute, Data: &message{Text: text}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// 第一条消息处理中，第二条消息占满邮箱，第三条消息溢出
+		deliver("1")
+
+		if text := <-r.started; text != "1" {
+			t.Fatalf("handled = %s, want 1", text)
+		}
+
+		deliver("2")
+		deliver("3")
+
+		close(r.block)
+
+		if text := <-r.started; text != tt.handled[1] {
+			t.Fatalf("overflow %d handled = %s, want %s", tt.overflow, text, tt.handled[1])
+		}
+
+		select {
+		case text := <-r.started:
+			t.Fatalf("overflow %d handled unexpected message %s", tt.overflow, text)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		c.Stop()
+	}
+}
+
+func TestActor_MailboxReject(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1),
+		testcluster.WithNodes(1, func(proxy *node.Proxy) {
+			proxy.Router().AddRouteHandler(loginRoute, func(ctx node.Context) {
+				if err := ctx.BindGate(1); err != nil {
+					return
+				}
+
+				if err := ctx.BindActor("room", "1"); err != nil {
+					return
+				}
+
+				_ = ctx.Response(&message{Text: "ok"})
+			})
+
+			proxy.Router().AddRouteHandler(roomRoute, func(ctx node.Context) { _ = ctx.Next() })
+		}),
+	)
+
+	r := spawnRoom(t, c.Node(0).Proxy(), node.Reject)
+
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.Request(loginRoute, &message{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.Send(roomRoute, &message{Text: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	<-r.started
+
+	if _, err = client.Send(roomRoute, &message{Text: "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	seq, err := client.Send(roomRoute, &message{Text: "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 未设置拒绝处理器时默认回复codes.TooManyRequests
+	msg, err := client.Expect(roomRoute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply := &cluster.CodeMessage{}
+
+	if err = msg.Parse(reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Seq != seq || reply.Code != codes.TooManyRequests.Code() {
+		t.Fatalf("reply seq = %d code = %d, want %d %d", msg.Seq, reply.Code, seq, codes.TooManyRequests.Code())
+	}
+
+	close(r.block)
+}
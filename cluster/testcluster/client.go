@@ -0,0 +1,354 @@
+package testcluster
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/encoding"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/packet"
+)
+
+type Message struct {
+	Seq    int32  // 序列号
+	Route  int32  // 路由ID
+	Buffer []byte // 消息内容
+	codec  encoding.Codec
+}
+
+// Parse 解析消息
+func (m *Message) Parse(v any) error {
+	return m.codec.Unmarshal(m.Buffer, v)
+}
+
+// Client 模拟客户端，通过内存直接连接至网关
+type Client struct {
+	gate     *Gate
+	conn     *serverConn
+	codec    encoding.Codec
+	timeout  time.Duration
+	seq      atomic.Int32
+	rw       sync.Mutex
+	messages []*Message // 尚未被消费的消息列表
+	resume   *resume    // 会话恢复状态；为nil时不跟踪会话恢复
+	chNotify chan struct{}
+	done     chan struct{}
+}
+
+// 会话恢复状态
+type resume struct {
+	route    int32  // 会话恢复路由
+	token    string // 恢复令牌
+	received uint64 // 收到令牌后已接收的消息数
+}
+
+func newClient(g *Gate) *Client {
+	c := &Client{}
+	c.gate = g
+	c.codec = g.cluster.opts.codec
+	c.timeout = g.cluster.opts.timeout
+	c.chNotify = make(chan struct{}, 1)
+	c.done = make(chan struct{})
+
+	return c
+}
+
+// CID 获取连接ID
+func (c *Client) CID() int64 {
+	return c.conn.ID()
+}
+
+// UID 获取绑定的用户ID
+func (c *Client) UID() int64 {
+	return c.conn.UID()
+}
+
+// Gate 获取连接的网关
+func (c *Client) Gate() *Gate {
+	return c.gate
+}
+
+// Send 发送消息，返回消息序列号
+func (c *Client) Send(route int32, message any) (int32, error) {
+	seq := c.seq.Add(1)
+
+	if err := c.send(seq, route, message); err != nil {
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// Request 发送消息并等待相同路由及序列号的响应消息
+// reply为nil时不解析响应消息
+func (c *Client) Request(route int32, message any, reply any) error {
+	msg, err := c.request(route, message)
+	if err != nil {
+		return err
+	}
+
+	if reply == nil {
+		return nil
+	}
+
+	return msg.Parse(reply)
+}
+
+// Receive 接收下一条消息
+func (c *Client) Receive() (*Message, error) {
+	return c.wait(func(*Message) bool { return true })
+}
+
+// Expect 等待指定路由的消息；其他路由的消息将保留，供后续调用消费
+func (c *Client) Expect(route int32) (*Message, error) {
+	return c.wait(func(m *Message) bool { return m.Route == route })
+}
+
+// ExpectEvent 等待当前连接在节点上触发的事件
+func (c *Client) ExpectEvent(event cluster.Event) (*Event, error) {
+	return c.gate.cluster.recorder.wait(c.gate.cluster.opts.timeout, func(e *Event) bool {
+		return e.Event == event && e.GID == c.gate.id && e.CID == c.CID()
+	})
+}
+
+// Done 连接关闭时返回的通道
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// TrackResume 跟踪会话恢复，需在绑定用户前调用
+// 收到恢复路由上的令牌时将已接收的消息数重置为0，其后收到的其他路由的消息均计入已接收的消息数
+func (c *Client) TrackResume(route int32) {
+	c.rw.Lock()
+	c.resume = &resume{route: route}
+	c.rw.Unlock()
+}
+
+// Token 获取恢复令牌及收到令牌后已接收的消息数
+func (c *Client) Token() (string, uint64) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	if c.resume == nil {
+		return "", 0
+	}
+
+	return c.resume.token, c.resume.received
+}
+
+// Resume 在同一网关上建立新的连接并恢复会话，恢复失败时返回errors.ErrResumeFailed
+// 恢复成功后，网关补发的消息可在新的客户端上继续接收
+func (c *Client) Resume() (*Client, error) {
+	c.rw.Lock()
+	if c.resume == nil || c.resume.token == "" {
+		c.rw.Unlock()
+		return nil, errors.ErrResumeFailed
+	}
+	route, payload := c.resume.route, c.resume.token+":"+strconv.FormatUint(c.resume.received, 10)
+	c.rw.Unlock()
+
+	client, err := c.gate.Dial()
+	if err != nil {
+		return nil, err
+	}
+
+	client.TrackResume(route)
+
+	msg, err := client.request(route, []byte(payload))
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	if len(msg.Buffer) == 0 {
+		_ = client.Close()
+		return nil, errors.ErrResumeFailed
+	}
+
+	return client, nil
+}
+
+// Run 按序执行脚本步骤，遇到错误时立即返回
+func (c *Client) Run(steps ...Step) error {
+	for _, step := range steps {
+		if err := step(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 发送消息并等待相同路由及序列号的响应消息
+func (c *Client) request(route int32, message any) (*Message, error) {
+	seq, err := c.Send(route, message)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.wait(func(m *Message) bool { return m.Route == route && m.Seq == seq })
+}
+
+// 发送消息
+func (c *Client) send(seq, route int32, message any) error {
+	var (
+		err    error
+		buffer []byte
+	)
+
+	switch v := message.(type) {
+	case nil:
+	case []byte:
+		buffer = v
+	default:
+		if buffer, err = c.codec.Marshal(v); err != nil {
+			return err
+		}
+	}
+
+	data, err := packet.PackMessage(&packet.Message{Seq: seq, Route: route, Buffer: buffer})
+	if err != nil {
+		return err
+	}
+
+	return c.conn.write(data)
+}
+
+// 接收网关推送的数据包
+func (c *Client) receive(data []byte) {
+	message, err := packet.UnpackMessage(data)
+	if err != nil {
+		return
+	}
+
+	c.rw.Lock()
+	if r := c.resume; r != nil {
+		if message.Route != r.route {
+			r.received++
+		} else if len(message.Buffer) > 0 {
+			r.token, r.received = string(message.Buffer), 0
+		}
+	}
+	c.messages = append(c.messages, &Message{
+		Seq:    message.Seq,
+		Route:  message.Route,
+		Buffer: message.Buffer,
+		codec:  c.codec,
+	})
+	c.rw.Unlock()
+
+	select {
+	case c.chNotify <- struct{}{}:
+	default:
+	}
+}
+
+// 连接已关闭
+func (c *Client) closed() {
+	close(c.done)
+}
+
+// 等待满足条件的消息
+func (c *Client) wait(match func(m *Message) bool) (*Message, error) {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	for {
+		if msg, ok := c.take(match); ok {
+			return msg, nil
+		}
+
+		select {
+		case <-c.chNotify:
+		case <-c.done:
+			if msg, ok := c.take(match); ok {
+				return msg, nil
+			}
+			return nil, errors.ErrConnectionClosed
+		case <-timer.C:
+			return nil, errors.ErrDeadlineExceeded
+		}
+	}
+}
+
+// 取出第一条满足条件的消息
+func (c *Client) take(match func(m *Message) bool) (*Message, bool) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	i := slices.IndexFunc(c.messages, match)
+	if i < 0 {
+		return nil, false
+	}
+
+	msg := c.messages[i]
+	c.messages = slices.Delete(c.messages, i, i+1)
+
+	return msg, true
+}
+
+// Step 客户端脚本步骤
+type Step func(c *Client) error
+
+// Send 发送消息
+func Send(route int32, message any) Step {
+	return func(c *Client) error {
+		_, err := c.Send(route, message)
+		return err
+	}
+}
+
+// Request 发送消息并等待响应，reply为nil时不解析响应消息
+func Request(route int32, message any, reply any) Step {
+	return func(c *Client) error {
+		return c.Request(route, message, reply)
+	}
+}
+
+// Expect 等待指定路由的消息，v为nil时不解析消息
+func Expect(route int32, v any) Step {
+	return func(c *Client) error {
+		msg, err := c.Expect(route)
+		if err != nil {
+			return err
+		}
+
+		if v == nil {
+			return nil
+		}
+
+		return msg.Parse(v)
+	}
+}
+
+// ExpectEvent 等待当前连接在节点上触发的事件
+func ExpectEvent(event cluster.Event) Step {
+	return func(c *Client) error {
+		_, err := c.ExpectEvent(event)
+		return err
+	}
+}
+
+// Sleep 等待一段时间
+func Sleep(d time.Duration) Step {
+	return func(c *Client) error {
+		time.Sleep(d)
+		return nil
+	}
+}
+
+// Close 关闭连接
+func Close() Step {
+	return func(c *Client) error {
+		return c.Close()
+	}
+}
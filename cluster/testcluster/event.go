@@ -0,0 +1,87 @@
+package testcluster
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/errors"
+)
+
+type Event struct {
+	NID   string        // 处理事件的节点ID
+	GID   string        // 触发事件的网关ID
+	CID   int64         // 连接ID
+	UID   int64         // 用户ID
+	Event cluster.Event // 事件类型
+}
+
+// 节点事件记录器
+type recorder struct {
+	rw       sync.Mutex
+	events   []*Event
+	consumed []bool
+	chNotify chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{chNotify: make(chan struct{})}
+}
+
+// 生成记录事件的处理器
+func (r *recorder) handler(event cluster.Event) node.EventHandler {
+	return func(ctx node.Context) {
+		r.record(&Event{
+			NID:   ctx.NID(),
+			GID:   ctx.GID(),
+			CID:   ctx.CID(),
+			UID:   ctx.UID(),
+			Event: event,
+		})
+	}
+}
+
+// 记录事件
+func (r *recorder) record(evt *Event) {
+	r.rw.Lock()
+	r.events = append(r.events, evt)
+	r.consumed = append(r.consumed, false)
+	close(r.chNotify)
+	r.chNotify = make(chan struct{})
+	r.rw.Unlock()
+}
+
+// 获取所有已记录的事件
+func (r *recorder) list() []*Event {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+// 等待第一条尚未被等待过且满足条件的事件
+func (r *recorder) wait(timeout time.Duration, match func(e *Event) bool) (*Event, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		r.rw.Lock()
+		for i, evt := range r.events {
+			if !r.consumed[i] && match(evt) {
+				r.consumed[i] = true
+				r.rw.Unlock()
+				return evt, nil
+			}
+		}
+		chNotify := r.chNotify
+		r.rw.Unlock()
+
+		select {
+		case <-chNotify:
+		case <-timer.C:
+			return nil, errors.ErrDeadlineExceeded
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package testcluster_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/gate"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/cluster/testcluster"
+	"github.com/dobyte/due/v2/codes"
+	"github.com/dobyte/due/v2/encoding/json"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+)
+
+const unknownRoute int32 = 999
+
+// 按业务协议回复错误码
+func replyCode(rejects *atomic.Int32) gate.LimitHandler {
+	return func(conn network.Conn, message *packet.Message, code *codes.Code) {
+		rejects.Add(1)
+
+		data, err := json.Marshal(&cluster.CodeMessage{Code: code.Code(), Message: code.Message()})
+		if err != nil {
+			return
+		}
+
+		msg, err := packet.PackMessage(&packet.Message{Seq: message.Seq, Route: message.Route, Buffer: data})
+		if err != nil {
+			return
+		}
+
+		_ = conn.Push(msg)
+	}
+}
+
+func TestGate_RouteLimit(t *testing.T) {
+	var rejects atomic.Int32
+
+	c := testcluster.Run(t,
+		testcluster.WithGates(1,
+			gate.WithRouteLimit(gate.Limit{Rate: 20, Burst: 2}, echoRoute),
+			gate.WithLimitReply(true, replyCode(&rejects)),
+		),
+		testcluster.WithNodes(1, setup),
+	)
+
+	client1, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client2, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 令牌桶容量为2，第三个请求将被拒绝
+	for i := 0; i < 3; i++ {
+		reply := &cluster.CodeMessage{}
+
+		if err = client1.Request(echoRoute, &message{Text: "hello"}, reply); err != nil {
+			t.Fatal(err)
+		}
+
+		if code := reply.Code; (i < 2 && code != 0) || (i == 2 && code != codes.TooManyRequests.Code()) {
+			t.Fatalf("request %d reply code = %d", i, code)
+		}
+	}
+
+	// 路由限流器按连接隔离
+	for i := 0; i < 2; i++ {
+		reply := &message{}
+
+		if err = client2.Request(echoRoute, &message{Text: "hello"}, reply); err != nil {
+			t.Fatal(err)
+		}
+
+		if reply.Text != "hello" {
+			t.Fatalf("unexpected reply: %s", reply.Text)
+		}
+	}
+
+	// 令牌按速率补充
+	time.Sleep(100 * time.Millisecond)
+
+	reply := &message{}
+
+	if err = client1.Request(echoRoute, &message{Text: "hello"}, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Text != "hello" {
+		t.Fatalf("unexpected reply: %s", reply.Text)
+	}
+
+	if n := rejects.Load(); n != 1 {
+		t.Fatalf("rejects = %d, want 1", n)
+	}
+}
+
+func TestGate_DefaultRouteLimit(t *testing.T) {
+	var rejects atomic.Int32
+
+	c := testcluster.Run(t,
+		testcluster.WithGates(1,
+			gate.WithRouteLimit(gate.Limit{Rate: 0.1, Burst: 1}),
+			gate.WithLimitReply(true, replyCode(&rejects)),
+		),
+		testcluster.WithNodes(1, setup),
+		testcluster.WithTimeout(200*time.Millisecond),
+	)
+
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 未注册到节点的路由不创建限流器
+	for i := 0; i < 10; i++ {
+		if _, err = client.Send(unknownRoute, &message{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = client.Request(echoRoute, &message{Text: "hello"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := rejects.Load(); n != 0 {
+		t.Fatalf("rejects = %d, want 0", n)
+	}
+
+	reply := &cluster.CodeMessage{}
+
+	if err = client.Request(echoRoute, &message{Text: "hello"}, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Code != codes.TooManyRequests.Code() || rejects.Load() != 1 {
+		t.Fatalf("reply code = %d rejects = %d", reply.Code, rejects.Load())
+	}
+}
+
+func TestGate_LimitWithoutHandler(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1, gate.WithRouteLimit(gate.Limit{Rate: 0.1, Burst: 1}, echoRoute)),
+		testcluster.WithNodes(1, setup),
+	)
+
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.Request(echoRoute, &message{Text: "hello"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未设置回复处理器时以cluster.CodeMessage回复客户端
+	reply := &cluster.CodeMessage{}
+
+	if err = client.Request(echoRoute, &message{Text: "hello"}, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Code != codes.TooManyRequests.Code() {
+		t.Fatalf("reply code = %d, want %d", reply.Code, codes.TooManyRequests.Code())
+	}
+}
+
+func TestGate_UIDLimitReconnect(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1, gate.WithUIDLimit(gate.Limit{Rate: 0.1, Burst: 1})),
+		testcluster.WithNodes(1, func(proxy *node.Proxy) {
+			setup(proxy)
+
+			proxy.Router().AddRouteHandler(loginRoute, func(ctx node.Context) {
+				if err := ctx.BindGate(1); err != nil {
+					return
+				}
+
+				_ = ctx.Response(&message{Text: "ok"})
+			})
+		}),
+	)
+
+	login := func() *testcluster.Client {
+		t.Helper()
+
+		client, err := c.Dial()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = client.Request(loginRoute, &message{}, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		return client
+	}
+
+	client := login()
+
+	if err := client.Request(echoRoute, &message{Text: "hello"}, &message{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重连后仍使用原有的用户限流器
+	client = login()
+
+	reply := &cluster.CodeMessage{}
+
+	if err := client.Request(echoRoute, &message{Text: "hello"}, reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Code != codes.TooManyRequests.Code() {
+		t.Fatalf("reply code = %d, want %d", reply.Code, codes.TooManyRequests.Code())
+	}
+}
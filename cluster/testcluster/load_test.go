@@ -0,0 +1,88 @@
+package testcluster_test
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/cluster/testcluster"
+	"github.com/dobyte/due/v2/registry"
+)
+
+const cloneRoute int32 = 30
+
+func TestNode_LoadInflight(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1),
+		testcluster.WithNodes(1, func(proxy *node.Proxy) {
+			proxy.Router().AddRouteHandler(cloneRoute, func(ctx node.Context) {
+				req := &message{}
+
+				if err := ctx.Parse(req); err != nil {
+					return
+				}
+
+				// 克隆的请求回收时不应扣除处理中的请求数
+				ctx.Clone().Task(func(ctx node.Context) {})
+
+				_ = ctx.Response(req)
+			})
+		}, node.WithLoadInterval(20*time.Millisecond)),
+	)
+
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 5 {
+		if err = client.Request(cloneRoute, &message{Text: "hello"}, &message{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	services, err := c.Registry().Services(context.Background(), cluster.Node.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 {
+		t.Fatalf("services = %d, want 1", len(services))
+	}
+
+	if inflight := services[0].Metadata[cluster.LoadInflightKey]; inflight != "0" {
+		t.Fatalf("inflight = %s, want 0", inflight)
+	}
+}
+
+func TestNode_RoutesSorted(t *testing.T) {
+	c := testcluster.Run(t, testcluster.WithNodes(1, func(proxy *node.Proxy) {
+		for route := int32(100); route < 132; route++ {
+			proxy.Router().AddRouteHandler(route, func(ctx node.Context) {})
+		}
+	}, node.WithLoadInterval(20*time.Millisecond)))
+
+	// 负载更新时重新导出的路由列表保持有序，避免被误判为路由变更
+	for range 3 {
+		time.Sleep(30 * time.Millisecond)
+
+		services, err := c.Registry().Services(context.Background(), cluster.Node.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(services) != 1 {
+			t.Fatalf("services = %d, want 1", len(services))
+		}
+
+		if !slices.IsSortedFunc(services[0].Routes, func(a, b registry.Route) int { return cmp.Compare(a.ID, b.ID) }) {
+			t.Fatal("routes are not sorted by id")
+		}
+	}
+}
@@ -0,0 +1,480 @@
+package testcluster
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	xnet "github.com/dobyte/due/v2/core/net"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/network"
+)
+
+const protocol = "memory"
+
+var _ network.Server = &server{}
+
+// 进程内网络服务器，客户端通过内存直接与网关交换数据包
+type server struct {
+	addr              string
+	started           atomic.Bool
+	id                atomic.Int64
+	rw                sync.RWMutex
+	conns             map[int64]*serverConn
+	startHandler      network.StartHandler
+	stopHandler       network.CloseHandler
+	connectHandler    network.ConnectHandler
+	receiveHandler    network.ReceiveHandler
+	disconnectHandler network.DisconnectHandler
+}
+
+func newServer(addr string) *server {
+	return &server{addr: addr, conns: make(map[int64]*serverConn)}
+}
+
+// Addr 监听地址
+func (s *server) Addr() string {
+	return s.addr
+}
+
+// Start 启动服务器
+func (s *server) Start() error {
+	if !s.started.CompareAndSwap(false, true) {
+		return errors.ErrIllegalOperation
+	}
+
+	if s.startHandler != nil {
+		s.startHandler()
+	}
+
+	return nil
+}
+
+// Stop 关闭服务器
+func (s *server) Stop() error {
+	if !s.started.CompareAndSwap(true, false) {
+		return errors.ErrServerClosed
+	}
+
+	s.disconnect()
+
+	if s.stopHandler != nil {
+		s.stopHandler()
+	}
+
+	return nil
+}
+
+// Protocol 协议
+func (s *server) Protocol() string {
+	return protocol
+}
+
+// OnStart 监听服务器启动
+func (s *server) OnStart(handler network.StartHandler) {
+	s.startHandler = handler
+}
+
+// OnStop 监听服务器关闭
+func (s *server) OnStop(handler network.CloseHandler) {
+	s.stopHandler = handler
+}
+
+// OnConnect 监听连接打开
+func (s *server) OnConnect(handler network.ConnectHandler) {
+	s.connectHandler = handler
+}
+
+// OnReceive 监听接收消息
+func (s *server) OnReceive(handler network.ReceiveHandler) {
+	s.receiveHandler = handler
+}
+
+// OnDisconnect 监听连接断开
+func (s *server) OnDisconnect(handler network.DisconnectHandler) {
+	s.disconnectHandler = handler
+}
+
+// 连接的对端，接收网关推送的数据包
+type peer interface {
+	// 接收网关推送的数据包
+	receive(data []byte)
+	// 连接已关闭
+	closed()
+}
+
+// 建立连接
+func (s *server) dial(peer peer) (*serverConn, error) {
+	if !s.started.Load() {
+		return nil, errors.ErrServerClosed
+	}
+
+	conn := newServerConn(s, s.id.Add(1), peer)
+
+	s.rw.Lock()
+	s.conns[conn.id] = conn
+	s.rw.Unlock()
+
+	if s.connectHandler != nil {
+		s.connectHandler(conn)
+	}
+
+	go conn.read()
+
+	return conn, nil
+}
+
+// 断开所有连接
+func (s *server) disconnect() {
+	s.rw.RLock()
+	conns := make([]*serverConn, 0, len(s.conns))
+	for _, conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.rw.RUnlock()
+
+	for _, conn := range conns {
+		_ = conn.Close(true)
+	}
+}
+
+// 移除连接
+func (s *server) remove(id int64) {
+	s.rw.Lock()
+	delete(s.conns, id)
+	s.rw.Unlock()
+}
+
+var _ network.Conn = &serverConn{}
+
+type serverConn struct {
+	id     int64
+	uid    atomic.Int64
+	state  atomic.Int32
+	attr   *attr
+	server *server
+	peer   peer
+	chRead chan []byte
+	done   chan struct{}
+}
+
+func newServerConn(s *server, id int64, peer peer) *serverConn {
+	c := &serverConn{}
+	c.id = id
+	c.attr = &attr{}
+	c.server = s
+	c.peer = peer
+	c.chRead = make(chan []byte, 1024)
+	c.done = make(chan struct{})
+	c.state.Store(int32(network.ConnOpened))
+
+	return c
+}
+
+// ID 获取连接ID
+func (c *serverConn) ID() int64 {
+	return c.id
+}
+
+// UID 获取用户ID
+func (c *serverConn) UID() int64 {
+	return c.uid.Load()
+}
+
+// Attr 获取属性接口
+func (c *serverConn) Attr() network.Attr {
+	return c.attr
+}
+
+// Bind 绑定用户ID
+func (c *serverConn) Bind(uid int64) {
+	c.uid.Store(uid)
+}
+
+// Unbind 解绑用户ID
+func (c *serverConn) Unbind() {
+	c.uid.Store(0)
+}
+
+// Send 发送消息（同步）
+func (c *serverConn) Send(msg []byte) error {
+	return c.Push(msg)
+}
+
+// Push 发送消息（异步）
+func (c *serverConn) Push(msg []byte) error {
+	if c.State() != network.ConnOpened {
+		return errors.ErrConnectionClosed
+	}
+
+	c.peer.receive(append([]byte(nil), msg...))
+
+	return nil
+}
+
+// State 获取连接状态
+func (c *serverConn) State() network.ConnState {
+	return network.ConnState(c.state.Load())
+}
+
+// Close 关闭连接；内存连接不存在写入队列，优雅关闭与强制关闭的行为一致
+func (c *serverConn) Close(force ...bool) error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnClosed)) {
+		return errors.ErrConnectionClosed
+	}
+
+	close(c.done)
+
+	c.server.remove(c.id)
+
+	if c.server.disconnectHandler != nil {
+		c.server.disconnectHandler(c)
+	}
+
+	c.peer.closed()
+
+	return nil
+}
+
+// LocalIP 获取本地IP
+func (c *serverConn) LocalIP() (string, error) {
+	return xnet.IPv4Loopback, nil
+}
+
+// LocalAddr 获取本地地址
+func (c *serverConn) LocalAddr() (net.Addr, error) {
+	return &net.TCPAddr{IP: net.ParseIP(xnet.IPv4Loopback)}, nil
+}
+
+// RemoteIP 获取远端IP
+func (c *serverConn) RemoteIP() (string, error) {
+	return xnet.IPv4Loopback, nil
+}
+
+// RemoteAddr 获取远端地址
+func (c *serverConn) RemoteAddr() (net.Addr, error) {
+	return &net.TCPAddr{IP: net.ParseIP(xnet.IPv4Loopback)}, nil
+}
+
+// 写入客户端发送的数据包
+func (c *serverConn) write(msg []byte) error {
+	if c.State() != network.ConnOpened {
+		return errors.ErrConnectionClosed
+	}
+
+	select {
+	case c.chRead <- msg:
+		return nil
+	case <-c.done:
+		return errors.ErrConnectionClosed
+	}
+}
+
+// 按序处理客户端发送的数据包
+func (c *serverConn) read() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.chRead:
+			if c.server.receiveHandler != nil {
+				c.server.receiveHandler(c, msg)
+			}
+		}
+	}
+}
+
+var _ network.Client = &client{}
+
+// 进程内网络客户端，可作为cluster/client的网络客户端通过内存连接至网关
+type client struct {
+	server            *server
+	connectHandler    network.ConnectHandler
+	receiveHandler    network.ReceiveHandler
+	disconnectHandler network.DisconnectHandler
+}
+
+// Dial 拨号连接，忽略拨号地址
+func (c *client) Dial(_ ...string) (network.Conn, error) {
+	conn := &clientConn{client: c, attr: &attr{}, chRead: make(chan []byte, 1024), done: make(chan struct{})}
+
+	sc, err := c.server.dial(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.conn = sc
+
+	go conn.read()
+
+	if c.connectHandler != nil {
+		c.connectHandler(conn)
+	}
+
+	return conn, nil
+}
+
+// Protocol 协议
+func (c *client) Protocol() string {
+	return protocol
+}
+
+// OnConnect 监听连接打开
+func (c *client) OnConnect(handler network.ConnectHandler) {
+	c.connectHandler = handler
+}
+
+// OnReceive 监听接收消息
+func (c *client) OnReceive(handler network.ReceiveHandler) {
+	c.receiveHandler = handler
+}
+
+// OnDisconnect 监听连接断开
+func (c *client) OnDisconnect(handler network.DisconnectHandler) {
+	c.disconnectHandler = handler
+}
+
+var _ network.Conn = &clientConn{}
+
+type clientConn struct {
+	uid    atomic.Int64
+	attr   *attr
+	client *client
+	conn   *serverConn
+	chRead chan []byte
+	done   chan struct{}
+}
+
+// ID 获取连接ID
+func (c *clientConn) ID() int64 {
+	return c.conn.ID()
+}
+
+// UID 获取用户ID
+func (c *clientConn) UID() int64 {
+	return c.uid.Load()
+}
+
+// Attr 获取属性接口
+func (c *clientConn) Attr() network.Attr {
+	return c.attr
+}
+
+// Bind 绑定用户ID
+func (c *clientConn) Bind(uid int64) {
+	c.uid.Store(uid)
+}
+
+// Unbind 解绑用户ID
+func (c *clientConn) Unbind() {
+	c.uid.Store(0)
+}
+
+// Send 发送消息（同步）
+func (c *clientConn) Send(msg []byte) error {
+	return c.Push(msg)
+}
+
+// Push 发送消息（异步）
+func (c *clientConn) Push(msg []byte) error {
+	return c.conn.write(append([]byte(nil), msg...))
+}
+
+// State 获取连接状态
+func (c *clientConn) State() network.ConnState {
+	return c.conn.State()
+}
+
+// Close 关闭连接
+func (c *clientConn) Close(force ...bool) error {
+	return c.conn.Close(force...)
+}
+
+// LocalIP 获取本地IP
+func (c *clientConn) LocalIP() (string, error) {
+	return xnet.IPv4Loopback, nil
+}
+
+// LocalAddr 获取本地地址
+func (c *clientConn) LocalAddr() (net.Addr, error) {
+	return &net.TCPAddr{IP: net.ParseIP(xnet.IPv4Loopback)}, nil
+}
+
+// RemoteIP 获取远端IP
+func (c *clientConn) RemoteIP() (string, error) {
+	return xnet.IPv4Loopback, nil
+}
+
+// RemoteAddr 获取远端地址
+func (c *clientConn) RemoteAddr() (net.Addr, error) {
+	return &net.TCPAddr{IP: net.ParseIP(xnet.IPv4Loopback)}, nil
+}
+
+// 接收网关推送的数据包
+func (c *clientConn) receive(data []byte) {
+	select {
+	case c.chRead <- data:
+	case <-c.done:
+	}
+}
+
+// 连接已关闭
+func (c *clientConn) closed() {
+	close(c.done)
+}
+
+// 按序处理网关推送的数据包，连接关闭后处理完剩余的数据包再触发断开连接
+func (c *clientConn) read() {
+	for {
+		select {
+		case msg := <-c.chRead:
+			c.handle(msg)
+		case <-c.done:
+			for {
+				select {
+				case msg := <-c.chRead:
+					c.handle(msg)
+				default:
+					if c.client.disconnectHandler != nil {
+						c.client.disconnectHandler(c)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// 处理网关推送的数据包
+func (c *clientConn) handle(msg []byte) {
+	if c.client.receiveHandler != nil {
+		c.client.receiveHandler(c, msg)
+	}
+}
+
+var _ network.Attr = &attr{}
+
+type attr struct {
+	values sync.Map
+}
+
+// Set 设置属性值
+func (a *attr) Set(key, value any) {
+	a.values.Store(key, value)
+}
+
+// Get 获取属性值
+func (a *attr) Get(key any) (any, bool) {
+	return a.values.Load(key)
+}
+
+// Del 删除属性值
+func (a *attr) Del(key any) bool {
+	_, ok := a.values.LoadAndDelete(key)
+	return ok
+}
+
+// Visit 访问所有的属性值
+func (a *attr) Visit(fn func(key, value any) bool) {
+	a.values.Range(fn)
+}
@@ -0,0 +1,94 @@
+package testcluster
+
+import (
+	"time"
+
+	"github.com/dobyte/due/v2/cluster/gate"
+	"github.com/dobyte/due/v2/cluster/mesh"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/encoding"
+	"github.com/dobyte/due/v2/encoding/json"
+)
+
+const (
+	defaultAddr    = "inproc://" // 默认内部RPC监听地址，使用进程内传输
+	defaultTimeout = 3 * time.Second
+)
+
+type Option func(o *options)
+
+type NodeSetup func(proxy *node.Proxy)
+
+type MeshSetup func(proxy *mesh.Proxy)
+
+type options struct {
+	codec   encoding.Codec // 编解码器，客户端与节点共用
+	timeout time.Duration  // 启动及等待消息、事件的超时时间
+	gates   []*gateEntity  // 网关列表
+	nodes   []*nodeEntity  // 节点列表
+	meshes  []*meshEntity  // 网格列表
+}
+
+type gateEntity struct {
+	opts []gate.Option
+}
+
+type nodeEntity struct {
+	setup NodeSetup
+	opts  []node.Option
+}
+
+type meshEntity struct {
+	setup MeshSetup
+	opts  []mesh.Option
+}
+
+func defaultOptions() *options {
+	return &options{
+		codec:   json.DefaultCodec,
+		timeout: defaultTimeout,
+	}
+}
+
+// WithCodec 设置编解码器
+func WithCodec(codec encoding.Codec) Option {
+	return func(o *options) {
+		if codec != nil {
+			o.codec = codec
+		}
+	}
+}
+
+// WithTimeout 设置启动及等待消息、事件的超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithGates 添加n个网关
+func WithGates(n int, opts ...gate.Option) Option {
+	return func(o *options) {
+		for range n {
+			o.gates = append(o.gates, &gateEntity{opts: opts})
+		}
+	}
+}
+
+// WithNodes 添加n个节点，setup用于注册路由、事件等处理器
+func WithNodes(n int, setup NodeSetup, opts ...node.Option) Option {
+	return func(o *options) {
+		for range n {
+			o.nodes = append(o.nodes, &nodeEntity{setup: setup, opts: opts})
+		}
+	}
+}
+
+// WithMesh 添加一个网格服务；网格服务依赖传输器，需通过mesh.WithTransporter传入
+func WithMesh(setup MeshSetup, opts ...mesh.Option) Option {
+	return func(o *options) {
+		o.meshes = append(o.meshes, &meshEntity{setup: setup, opts: opts})
+	}
+}
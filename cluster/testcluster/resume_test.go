@@ -0,0 +1,234 @@
+package testcluster_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/gate"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/cluster/testcluster"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/session"
+)
+
+const resumeRoute int32 = 40
+
+// 启动开启会话恢复的集群，登录路由以消息内容作为用户ID进行绑定
+func runResume(t *testing.T, opts ...gate.Option) *testcluster.Cluster {
+	return testcluster.Run(t,
+		testcluster.WithGates(1, append([]gate.Option{gate.WithResume(true, resumeRoute)}, opts...)...),
+		testcluster.WithNodes(1, func(proxy *node.Proxy) {
+			setup(proxy)
+
+			proxy.Router().AddRouteHandler(loginRoute, func(ctx node.Context) {
+				req := &message{}
+
+				if err := ctx.Parse(req); err != nil {
+					return
+				}
+
+				uid, err := strconv.ParseInt(req.Text, 10, 64)
+				if err != nil {
+					return
+				}
+
+				if err = ctx.BindGate(uid); err != nil {
+					return
+				}
+
+				_ = ctx.Response(req)
+			})
+		}),
+		testcluster.WithTimeout(500*time.Millisecond),
+	)
+}
+
+// 登录并等待网关下发恢复令牌
+func login(t *testing.T, c *testcluster.Cluster, uid int64) *testcluster.Client {
+	t.Helper()
+
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.TrackResume(resumeRoute)
+
+	if err = client.Request(loginRoute, &message{Text: strconv.FormatInt(uid, 10)}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := client.Expect(resumeRoute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token, _ := client.Token(); token == "" || token != string(msg.Buffer) {
+		t.Fatalf("token = %q, want %q", token, msg.Buffer)
+	}
+
+	return client
+}
+
+// 向用户推送消息
+func push(t *testing.T, c *testcluster.Cluster, uid int64, text string) {
+	t.Helper()
+
+	if err := c.Node(0).Proxy().Push(context.Background(), &cluster.PushArgs{
+		Kind:    session.User,
+		Target:  uid,
+		Message: &cluster.Message{Route: noticeRoute, Data: &message{Text: text}},
+		Ack:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 按序接收推送的消息
+func expectNotices(t *testing.T, client *testcluster.Client, texts ...string) {
+	t.Helper()
+
+	for _, text := range texts {
+		msg, err := client.Expect(noticeRoute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		notice := &message{}
+
+		if err = msg.Parse(notice); err != nil {
+			t.Fatal(err)
+		}
+
+		if notice.Text != text {
+			t.Fatalf("notice = %s, want %s", notice.Text, text)
+		}
+	}
+}
+
+func TestGate_Resume(t *testing.T) {
+	c := runResume(t)
+
+	client := login(t, c, 1)
+
+	push(t, c, 1, "a")
+	expectNotices(t, client, "a")
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 连接断开后会话被挂起，推送的消息被缓存且不触发断开连接事件
+	push(t, c, 1, "b")
+	push(t, c, 1, "c")
+
+	if _, err := client.ExpectEvent(cluster.Disconnect); !errors.Is(err, errors.ErrDeadlineExceeded) {
+		t.Fatalf("held session should not trigger disconnect event, err: %v", err)
+	}
+
+	client, err := client.Resume()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.ExpectEvent(cluster.Reconnect); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNotices(t, client, "b", "c")
+
+	push(t, c, 1, "d")
+	expectNotices(t, client, "d")
+
+	if err = client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	push(t, c, 1, "e")
+
+	// 再次恢复时仅补发第二次断线期间遗漏的消息
+	if client, err = client.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNotices(t, client, "e")
+
+	if msg, err := client.Expect(noticeRoute); err == nil {
+		t.Fatalf("unexpected replayed message: %s", msg.Buffer)
+	}
+}
+
+func TestGate_ResumeExpired(t *testing.T) {
+	c := runResume(t, gate.WithResumeTimeout(100*time.Millisecond))
+
+	client := login(t, c, 1)
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 会话过期后触发断开连接事件，且无法再恢复
+	if _, err := client.ExpectEvent(cluster.Disconnect); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Resume(); !errors.Is(err, errors.ErrResumeFailed) {
+		t.Fatalf("expired session should not be resumed, err: %v", err)
+	}
+}
+
+func TestGate_ResumeLostMessages(t *testing.T) {
+	c := runResume(t, gate.WithResumeBufferSize(2))
+
+	client := login(t, c, 1)
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 遗漏的消息超出缓冲区大小时拒绝恢复
+	for _, text := range []string{"a", "b", "c"} {
+		push(t, c, 1, text)
+	}
+
+	if _, err := client.Resume(); !errors.Is(err, errors.ErrResumeFailed) {
+		t.Fatalf("session with lost messages should not be resumed, err: %v", err)
+	}
+}
+
+func TestGate_ResumeMulticast(t *testing.T) {
+	c := runResume(t)
+
+	client1 := login(t, c, 1)
+	client2 := login(t, c, 2)
+
+	if err := client2.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 挂起的会话缓存的消息计入推送总数
+	total, err := c.Node(0).Proxy().Multicast(context.Background(), &cluster.MulticastArgs{
+		Kind:    session.User,
+		Targets: []int64{1, 2},
+		Message: &cluster.Message{Route: noticeRoute, Data: &message{Text: "hello"}},
+		Ack:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 2 {
+		t.Fatalf("total = %d, want 2", total)
+	}
+
+	expectNotices(t, client1, "hello")
+
+	if client2, err = client2.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNotices(t, client2, "hello")
+}
@@ -0,0 +1,269 @@
+package testcluster_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/client"
+	"github.com/dobyte/due/v2/cluster/gate"
+	"github.com/dobyte/due/v2/cluster/testcluster"
+	"github.com/dobyte/due/v2/encoding/json"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/network"
+)
+
+type signer struct {
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+}
+
+func (s *signer) Name() string { return "ed25519" }
+
+func (s *signer) Sign(data []byte) ([]byte, error) { return ed25519.Sign(s.privateKey, data), nil }
+
+func (s *signer) Verify(data []byte, signature []byte) (bool, error) {
+	return ed25519.Verify(s.publicKey, data, signature), nil
+}
+
+func newSigner(t *testing.T) *signer {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &signer{publicKey: publicKey, privateKey: privateKey}
+}
+
+// 记录客户端收发的原始数据包，并可篡改下一个发送的数据包
+type tap struct {
+	network.Client
+	mu     sync.Mutex
+	conns  map[network.Conn]*tapConn
+	conn   *tapConn // 最后建立的连接
+	sent   [][]byte
+	recv   [][]byte
+	tamper atomic.Bool
+}
+
+type tapConn struct {
+	network.Conn
+	tap *tap
+}
+
+func newTap(c network.Client) *tap {
+	return &tap{Client: c, conns: make(map[network.Conn]*tapConn)}
+}
+
+func (t *tap) Dial(addr ...string) (network.Conn, error) {
+	conn, err := t.Client.Dial(addr...)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &tapConn{Conn: conn, tap: t}
+
+	t.mu.Lock()
+	t.conns[conn] = tc
+	t.conn = tc
+	t.mu.Unlock()
+
+	return tc, nil
+}
+
+// 在最后建立的连接上重新发送数据包
+func (t *tap) replay(msg []byte) error {
+	t.mu.Lock()
+	conn := t.conn
+	t.mu.Unlock()
+
+	return conn.Conn.Push(msg)
+}
+
+func (t *tap) OnReceive(handler network.ReceiveHandler) {
+	t.Client.OnReceive(func(conn network.Conn, msg []byte) {
+		t.mu.Lock()
+		t.recv = append(t.recv, msg)
+		tc := t.conns[conn]
+		t.mu.Unlock()
+
+		handler(tc, msg)
+	})
+}
+
+func (t *tap) OnDisconnect(handler network.DisconnectHandler) {
+	t.Client.OnDisconnect(func(conn network.Conn) {
+		t.mu.Lock()
+		tc := t.conns[conn]
+		t.mu.Unlock()
+
+		handler(tc)
+	})
+}
+
+// 包含明文的数据包数量
+func (t *tap) plaintext(data []byte) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := 0
+
+	for _, packets := range [][][]byte{t.sent, t.recv} {
+		for _, packet := range packets {
+			if bytes.Contains(packet, data) {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// 最后一个发送的数据包
+func (t *tap) last() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.sent[len(t.sent)-1]
+}
+
+func (c *tapConn) Push(msg []byte) error {
+	msg = bytes.Clone(msg)
+
+	if c.tap.tamper.CompareAndSwap(true, false) {
+		msg[len(msg)-1] ^= 0xff
+	}
+
+	c.tap.mu.Lock()
+	c.tap.sent = append(c.tap.sent, msg)
+	c.tap.mu.Unlock()
+
+	return c.Conn.Push(msg)
+}
+
+func TestGate_Handshake(t *testing.T) {
+	s := newSigner(t)
+
+	c := testcluster.Run(t,
+		testcluster.WithGates(1, gate.WithHandshake(s)),
+		testcluster.WithNodes(1, setup),
+		testcluster.WithTimeout(500*time.Millisecond),
+	)
+
+	var (
+		tp          = newTap(c.Gate(0).NetworkClient())
+		replies     = make(chan string, 16)
+		disconnects = make(chan int64, 4)
+		cli         = client.NewClient(client.WithClient(tp), client.WithCodec(json.DefaultCodec), client.WithHandshake(s))
+	)
+
+	for _, route := range []int32{echoRoute, noticeRoute} {
+		cli.Proxy().AddRouteHandler(route, func(ctx *client.Context) {
+			msg := &message{}
+
+			if err := ctx.Parse(msg); err == nil {
+				replies <- msg.Text
+			}
+		})
+	}
+
+	cli.Proxy().AddEventListener(cluster.Disconnect, func(conn *client.Conn) {
+		disconnects <- conn.ID()
+	})
+
+	cli.Init()
+	cli.Start()
+	defer cli.Destroy()
+
+	expect := func(want string) {
+		t.Helper()
+
+		select {
+		case text := <-replies:
+			if text != want {
+				t.Fatalf("reply = %s, want %s", text, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("wait reply %s timeout", want)
+		}
+	}
+
+	expectClosed := func(conn *client.Conn) {
+		t.Helper()
+
+		select {
+		case cid := <-disconnects:
+			if cid != conn.ID() {
+				t.Fatalf("disconnect cid = %d, want %d", cid, conn.ID())
+			}
+		case <-time.After(time.Second):
+			t.Fatal("connection should be closed by gate")
+		}
+
+		select {
+		case text := <-replies:
+			t.Fatalf("unexpected reply: %s", text)
+		default:
+		}
+	}
+
+	// 完成握手后双向收发加密的数据包
+	conn, err := cli.Proxy().Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = conn.Push(&cluster.Message{Seq: 1, Route: echoRoute, Data: &message{Text: "secret-echo"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expect("secret-echo")
+
+	request := tp.last()
+
+	if err = conn.Push(&cluster.Message{Seq: 2, Route: broadcastRoute, Data: &message{Text: "secret-notice"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expect("secret-notice")
+
+	if n := tp.plaintext([]byte("secret")); n != 0 {
+		t.Fatalf("%d packets contain plaintext", n)
+	}
+
+	// 重放的数据包将被拒绝并断开连接
+	if err = tp.replay(request); err != nil {
+		t.Fatal(err)
+	}
+
+	expectClosed(conn)
+
+	// 篡改的数据包将被拒绝并断开连接
+	conn, err = cli.Proxy().Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tp.tamper.Store(true)
+
+	if err = conn.Push(&cluster.Message{Seq: 1, Route: echoRoute, Data: &message{Text: "tampered"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expectClosed(conn)
+
+	// 未握手的明文客户端将被拒绝
+	plain, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = plain.Request(echoRoute, &message{Text: "hello"}, nil); !errors.Is(err, errors.ErrConnectionClosed) {
+		t.Fatalf("plaintext client should be refused, err: %v", err)
+	}
+}
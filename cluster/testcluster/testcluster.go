@@ -0,0 +1,304 @@
+// Package testcluster 在单个进程内启动由网关、节点及网格服务组成的完整集群，便于编写端到端测试。
+// 集群使用基于内存的服务注册发现组件及定位器，客户端通过内存直接连接至网关，内部RPC监听本地随机端口。
+package testcluster
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/gate"
+	"github.com/dobyte/due/v2/cluster/mesh"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/locate"
+	memlocate "github.com/dobyte/due/v2/locate/memory"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/registry"
+	memregistry "github.com/dobyte/due/v2/registry/memory"
+)
+
+type Cluster struct {
+	opts     *options
+	started  atomic.Bool
+	stopOnce sync.Once
+	registry *memregistry.Registry
+	locator  *memlocate.Locator
+	recorder *recorder
+	gates    []*Gate
+	nodes    []*node.Node
+	meshes   []*mesh.Mesh
+	index    atomic.Int64
+}
+
+type Gate struct {
+	*gate.Gate
+	id      string
+	server  *server
+	cluster *Cluster
+}
+
+// ID 获取网关ID
+func (g *Gate) ID() string {
+	return g.id
+}
+
+// Dial 建立客户端连接
+func (g *Gate) Dial() (*Client, error) {
+	c := newClient(g)
+
+	conn, err := g.server.dial(c)
+	if err != nil {
+		return nil, err
+	}
+
+	c.conn = conn
+
+	return c, nil
+}
+
+// NetworkClient 创建通过内存连接至网关的网络客户端，可通过client.WithClient传入cluster/client组件
+func (g *Gate) NetworkClient() network.Client {
+	return &client{server: g.server}
+}
+
+// New 创建集群；网关、节点及网格服务的ID、注册发现组件、定位器及内部RPC地址由集群统一分配
+func New(opts ...Option) *Cluster {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := &Cluster{}
+	c.opts = o
+	c.registry = memregistry.NewRegistry()
+	c.locator = memlocate.NewLocator()
+	c.recorder = newRecorder()
+
+	for i, entity := range o.nodes {
+		n := node.NewNode(append([]node.Option{node.WithCodec(o.codec)}, append(entity.opts,
+			node.WithID(fmt.Sprintf("node-%d", i+1)),
+			node.WithAddr(defaultAddr),
+			node.WithRegistry(c.registry),
+			node.WithLocator(c.locator),
+		)...)...)
+
+		// 先注册事件记录器，setup中注册的同类事件处理器将覆盖记录器
+		for _, event := range []cluster.Event{cluster.Connect, cluster.Reconnect, cluster.Disconnect} {
+			n.Proxy().AddEventHandler(event, c.recorder.handler(event))
+		}
+
+		if entity.setup != nil {
+			entity.setup(n.Proxy())
+		}
+
+		c.nodes = append(c.nodes, n)
+	}
+
+	for i, entity := range o.meshes {
+		m := mesh.NewMesh(append([]mesh.Option{mesh.WithCodec(o.codec)}, append(entity.opts,
+			mesh.WithID(fmt.Sprintf("mesh-%d", i+1)),
+			mesh.WithRegistry(c.registry),
+			mesh.WithLocator(c.locator),
+		)...)...)
+
+		if entity.setup != nil {
+			entity.setup(m.Proxy())
+		}
+
+		c.meshes = append(c.meshes, m)
+	}
+
+	for i, entity := range o.gates {
+		id := fmt.Sprintf("gate-%d", i+1)
+		srv := newServer(id)
+
+		g := gate.NewGate(append(entity.opts,
+			gate.WithID(id),
+			gate.WithAddr(defaultAddr),
+			gate.WithServer(srv),
+			gate.WithRegistry(c.registry),
+			gate.WithLocator(c.locator),
+		)...)
+
+		c.gates = append(c.gates, &Gate{Gate: g, id: id, server: srv, cluster: c})
+	}
+
+	return c
+}
+
+// Run 创建并启动集群，测试结束时自动停止集群
+func Run(t testing.TB, opts ...Option) *Cluster {
+	t.Helper()
+
+	c := New(opts...)
+
+	if err := c.Start(); err != nil {
+		c.Stop()
+		t.Fatalf("test cluster start failed: %v", err)
+	}
+
+	t.Cleanup(c.Stop)
+
+	return c
+}
+
+// Start 启动集群，并等待所有节点发现所有网关
+func (c *Cluster) Start() error {
+	if !c.started.CompareAndSwap(false, true) {
+		return errors.ErrIllegalOperation
+	}
+
+	// 先启动节点及网格服务，使网关启动时即可发现所有节点
+	for _, n := range c.nodes {
+		n.Init()
+		n.Start()
+	}
+
+	for _, m := range c.meshes {
+		m.Init()
+		m.Start()
+	}
+
+	for _, g := range c.gates {
+		g.Init()
+		g.Start()
+	}
+
+	return c.ready()
+}
+
+// Stop 停止集群
+func (c *Cluster) Stop() {
+	if !c.started.Load() {
+		return
+	}
+
+	c.stopOnce.Do(func() {
+		// 网关关闭时会等待所有连接断开，需先断开所有客户端连接
+		for _, g := range c.gates {
+			g.server.disconnect()
+			g.Close()
+		}
+
+		for _, n := range c.nodes {
+			n.Close()
+		}
+
+		for _, m := range c.meshes {
+			m.Close()
+		}
+
+		for _, g := range c.gates {
+			g.Destroy()
+		}
+
+		for _, n := range c.nodes {
+			n.Destroy()
+		}
+
+		for _, m := range c.meshes {
+			m.Destroy()
+		}
+	})
+}
+
+// Dial 建立客户端连接，多个网关时轮询选择网关
+func (c *Cluster) Dial() (*Client, error) {
+	if len(c.gates) == 0 {
+		return nil, errors.ErrNotFoundEndpoint
+	}
+
+	return c.gates[int(c.index.Add(1)-1)%len(c.gates)].Dial()
+}
+
+// Gate 获取第i个网关
+func (c *Cluster) Gate(i int) *Gate {
+	return c.gates[i]
+}
+
+// Gates 获取网关列表
+func (c *Cluster) Gates() []*Gate {
+	return c.gates
+}
+
+// Node 获取第i个节点
+func (c *Cluster) Node(i int) *node.Node {
+	return c.nodes[i]
+}
+
+// Nodes 获取节点列表
+func (c *Cluster) Nodes() []*node.Node {
+	return c.nodes
+}
+
+// Mesh 获取第i个网格服务
+func (c *Cluster) Mesh(i int) *mesh.Mesh {
+	return c.meshes[i]
+}
+
+// Meshes 获取网格服务列表
+func (c *Cluster) Meshes() []*mesh.Mesh {
+	return c.meshes
+}
+
+// Registry 获取服务注册发现组件
+func (c *Cluster) Registry() registry.Registry {
+	return c.registry
+}
+
+// Locator 获取定位器
+func (c *Cluster) Locator() locate.Locator {
+	return c.locator
+}
+
+// Events 获取节点已处理的所有事件
+func (c *Cluster) Events() []*Event {
+	return c.recorder.list()
+}
+
+// ExpectEvent 等待任意连接在节点上触发的指定事件
+func (c *Cluster) ExpectEvent(event cluster.Event) (*Event, error) {
+	return c.recorder.wait(c.opts.timeout, func(e *Event) bool {
+		return e.Event == event
+	})
+}
+
+// 等待所有节点及网格服务发现所有网关
+func (c *Cluster) ready() error {
+	deadline := time.Now().Add(c.opts.timeout)
+
+	for {
+		if c.discovered() {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.ErrDeadlineExceeded
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// 检测所有节点及网格服务是否已发现所有网关
+func (c *Cluster) discovered() bool {
+	for _, g := range c.gates {
+		for _, n := range c.nodes {
+			if !n.Proxy().HasGate(g.id) {
+				return false
+			}
+		}
+
+		for _, m := range c.meshes {
+			if !m.Proxy().HasGate(g.id) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,174 @@
+package testcluster_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/cluster/node"
+	"github.com/dobyte/due/v2/cluster/testcluster"
+	"github.com/dobyte/due/v2/registry"
+	"github.com/dobyte/due/v2/session"
+)
+
+const (
+	echoRoute      int32 = 1
+	broadcastRoute int32 = 2
+	noticeRoute    int32 = 3
+	hotRoute       int32 = 4
+)
+
+type message struct {
+	Text string `json:"text"`
+}
+
+func setup(proxy *node.Proxy) {
+	proxy.Router().AddRouteHandler(echoRoute, func(ctx node.Context) {
+		req := &message{}
+
+		if err := ctx.Parse(req); err != nil {
+			return
+		}
+
+		_ = ctx.Response(req)
+	})
+
+	proxy.Router().AddRouteHandler(broadcastRoute, func(ctx node.Context) {
+		req := &message{}
+
+		if err := ctx.Parse(req); err != nil {
+			return
+		}
+
+		_, _ = ctx.Proxy().Broadcast(ctx.Context(), &cluster.BroadcastArgs{
+			Kind:    session.Conn,
+			Message: &cluster.Message{Route: noticeRoute, Data: req},
+		})
+	})
+}
+
+func TestCluster(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(2),
+		testcluster.WithNodes(1, setup),
+	)
+
+	client1, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client2, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client1.Gate().ID() == client2.Gate().ID() {
+		t.Fatal("clients should be dispatched to different gates")
+	}
+
+	reply := &message{}
+
+	if err = client1.Run(
+		testcluster.ExpectEvent(cluster.Connect),
+		testcluster.Request(echoRoute, &message{Text: "hello"}, reply),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.Text != "hello" {
+		t.Fatalf("unexpected reply: %s", reply.Text)
+	}
+
+	if _, err = client1.Send(broadcastRoute, &message{Text: "notice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, client := range []*testcluster.Client{client1, client2} {
+		notice := &message{}
+
+		if err = client.Run(testcluster.Expect(noticeRoute, notice)); err != nil {
+			t.Fatal(err)
+		}
+
+		if notice.Text != "notice" {
+			t.Fatalf("unexpected notice: %s", notice.Text)
+		}
+	}
+
+	cid := client2.CID()
+
+	if err = client2.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	evt, err := c.ExpectEvent(cluster.Disconnect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if evt.GID != client2.Gate().ID() || evt.CID != cid {
+		t.Fatalf("unexpected event: %+v", evt)
+	}
+}
+
+func TestCluster_HotRoute(t *testing.T) {
+	c := testcluster.Run(t,
+		testcluster.WithGates(1),
+		testcluster.WithNodes(1, setup),
+		testcluster.WithTimeout(500*time.Millisecond),
+	)
+
+	client, err := c.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Node(0).Proxy().AddRouteHandler(hotRoute, func(ctx node.Context) {
+		_ = ctx.Response(&message{Text: "hot"})
+	})
+
+	waitRoute(t, c.Registry(), hotRoute, true)
+
+	reply := &message{}
+
+	for i := 0; ; i++ {
+		if err = client.Request(hotRoute, &message{}, reply); err == nil {
+			break
+		}
+
+		if i == 5 {
+			t.Fatal(err)
+		}
+	}
+
+	if reply.Text != "hot" {
+		t.Fatalf("unexpected reply: %s", reply.Text)
+	}
+
+	c.Node(0).Proxy().RemoveRouteHandler(hotRoute)
+
+	waitRoute(t, c.Registry(), hotRoute, false)
+}
+
+// 等待注册中心中节点路由的变更
+func waitRoute(t *testing.T, r registry.Registry, route int32, exist bool) {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for time.Now().Before(deadline) {
+		services, err := r.Services(context.Background(), cluster.Node.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(services) > 0 && slices.ContainsFunc(services[0].Routes, func(r registry.Route) bool { return r.ID == route }) == exist {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("wait route %d exist=%v timeout", route, exist)
+}
@@ -0,0 +1,50 @@
+package cpu
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Sampler 进程CPU使用率采样器
+type Sampler struct {
+	mu       sync.Mutex
+	lastTime time.Time
+	lastCPU  time.Duration
+}
+
+// NewSampler 新建采样器
+func NewSampler() *Sampler {
+	s := &Sampler{}
+	s.lastTime = time.Now()
+	s.lastCPU, _ = processTime()
+
+	return s
+}
+
+// Usage 获取自上次采样以来的进程CPU使用率
+// 返回值为占所有逻辑CPU的百分比（0-100），不支持的平台始终返回0
+func (s *Sampler) Usage() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	cpu, err := processTime()
+	if err != nil {
+		return 0
+	}
+
+	elapsed := now.Sub(s.lastTime)
+	used := cpu - s.lastCPU
+
+	s.lastTime = now
+	s.lastCPU = cpu
+
+	if elapsed <= 0 || used <= 0 {
+		return 0
+	}
+
+	usage := float64(used) / float64(elapsed) / float64(runtime.NumCPU()) * 100
+
+	return min(usage, 100)
+}
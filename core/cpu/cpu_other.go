@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !openbsd && !netbsd
+
+package cpu
+
+import (
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+)
+
+// 获取进程累计占用的CPU时间
+func processTime() (time.Duration, error) {
+	return 0, errors.ErrIllegalOperation
+}
@@ -0,0 +1,24 @@
+package cpu_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/core/cpu"
+)
+
+func TestSampler_Usage(t *testing.T) {
+	sampler := cpu.NewSampler()
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for time.Now().Before(deadline) {
+	}
+
+	usage := sampler.Usage()
+
+	if usage < 0 || usage > 100 {
+		t.Fatalf("unexpected usage: %f", usage)
+	}
+
+	t.Logf("usage: %.2f%%", usage)
+}
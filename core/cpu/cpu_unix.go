@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd
+
+package cpu
+
+import (
+	"syscall"
+	"time"
+)
+
+// 获取进程累计占用的CPU时间（用户态+内核态）
+func processTime() (time.Duration, error) {
+	var usage syscall.Rusage
+
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), nil
+}
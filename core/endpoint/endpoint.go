@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -23,15 +24,21 @@ func ParseEndpoint(endpoint string) (*Endpoint, error) {
 	return &Endpoint{raw: raw, isSecure: raw.Query().Get(secureField) == "true"}, nil
 }
 
+// NewEndpoint 新建端点
+// 注：地址为绝对路径（如Unix域套接字文件）时将作为路径保存
 func NewEndpoint(scheme, address string, isSecure bool) *Endpoint {
-	return &Endpoint{
-		raw: &url.URL{
-			Scheme:   scheme,
-			Host:     address,
-			RawQuery: fmt.Sprintf("%s=%s", secureField, strconv.FormatBool(isSecure)),
-		},
-		isSecure: isSecure,
+	raw := &url.URL{
+		Scheme:   scheme,
+		RawQuery: fmt.Sprintf("%s=%s", secureField, strconv.FormatBool(isSecure)),
 	}
+
+	if strings.HasPrefix(address, "/") {
+		raw.Path = address
+	} else {
+		raw.Host = address
+	}
+
+	return &Endpoint{raw: raw, isSecure: isSecure}
 }
 
 func (e *Endpoint) Scheme() string {
@@ -39,10 +46,14 @@ func (e *Endpoint) Scheme() string {
 }
 
 func (e *Endpoint) Target() string {
-	return "direct://" + e.raw.Host
+	return "direct://" + e.Address()
 }
 
 func (e *Endpoint) Address() string {
+	if e.raw.Host == "" {
+		return e.raw.Path
+	}
+
 	return e.raw.Host
 }
 
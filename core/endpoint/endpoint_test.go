@@ -17,3 +17,16 @@ func TestNewEndpoint(t *testing.T) {
 
 	t.Log(ee.Address())
 }
+
+func TestNewEndpoint_Path(t *testing.T) {
+	e := endpoint.NewEndpoint("drpc+unix", "/tmp/due.sock", false)
+
+	ee, err := endpoint.ParseEndpoint(e.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ee.Scheme() != "drpc+unix" || ee.Address() != "/tmp/due.sock" {
+		t.Fatalf("unexpected endpoint: %s", ee.String())
+	}
+}
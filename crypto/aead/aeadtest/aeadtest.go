@@ -0,0 +1,102 @@
+package aeadtest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dobyte/due/v2/utils/xrand"
+)
+
+// Encryptor 支持密钥轮换的加密器
+type Encryptor interface {
+	// Encrypt 加密
+	Encrypt(data []byte) ([]byte, error)
+	// Decrypt 解密
+	Decrypt(ciphertext []byte) ([]byte, error)
+	// Rotate 轮换密钥
+	Rotate(version uint8, key []byte) error
+}
+
+// Run 执行加密器的通用测试用例
+// newEncryptor需返回使用初始密钥版本0且保留1个历史密钥的加密器，keys为两个可用于轮换的合法密钥
+func Run(t *testing.T, newEncryptor func() Encryptor, keys [2][]byte) {
+	t.Run("EncryptDecrypt", func(t *testing.T) {
+		encryptor := newEncryptor()
+
+		for _, size := range []int{0, 1, 100, 20000} {
+			data := []byte(xrand.Letters(size))
+
+			ciphertext, err := encryptor.Encrypt(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plaintext, err := encryptor.Decrypt(ciphertext)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(plaintext, data) {
+				t.Fatalf("size %d: decrypted data mismatch", size)
+			}
+
+			ciphertext[len(ciphertext)-1] ^= 0xff
+
+			if _, err = encryptor.Decrypt(ciphertext); err == nil {
+				t.Fatalf("size %d: tampered ciphertext should not be decrypted", size)
+			}
+		}
+	})
+
+	t.Run("InvalidCiphertext", func(t *testing.T) {
+		encryptor := newEncryptor()
+
+		for _, ciphertext := range [][]byte{nil, {0}, {0, 1, 2, 3}, {9, 1, 2, 3}} {
+			if _, err := encryptor.Decrypt(ciphertext); err == nil {
+				t.Fatalf("invalid ciphertext %v should not be decrypted", ciphertext)
+			}
+		}
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		encryptor := newEncryptor()
+
+		data := []byte(xrand.Letters(100))
+
+		v0, err := encryptor.Encrypt(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = encryptor.Rotate(1, keys[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		v1, err := encryptor.Encrypt(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ciphertext := range [][]byte{v0, v1} {
+			if plaintext, err := encryptor.Decrypt(ciphertext); err != nil || !bytes.Equal(plaintext, data) {
+				t.Fatalf("decrypt failed: %v", err)
+			}
+		}
+
+		if err = encryptor.Rotate(2, keys[1]); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = encryptor.Decrypt(v0); err == nil {
+			t.Fatal("retired key should not be used")
+		}
+
+		if _, err = encryptor.Decrypt(v1); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = encryptor.Rotate(3, []byte("short")); err == nil {
+			t.Fatal("invalid key should be rejected")
+		}
+	})
+}
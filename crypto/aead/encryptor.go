@@ -0,0 +1,125 @@
+package aead
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/errors"
+)
+
+// 基于AEAD算法的加密器，支持密钥版本轮换，供各AEAD加密组件复用
+// 密文格式：version(1 byte) | nonce(n byte) | ciphertext(m byte) | tag(AEAD overhead byte)
+
+const defaultVersionBytes = 1
+
+// Builder AEAD构建器，密钥不合法时需返回错误
+type Builder func(key []byte) (cipher.AEAD, error)
+
+type keyring struct {
+	version uint8                 // 当前密钥版本
+	aeads   map[uint8]cipher.AEAD // 密钥版本 -> AEAD
+	history []uint8               // 历史密钥版本，按轮换顺序排列
+}
+
+type Encryptor struct {
+	err     error
+	builder Builder
+	retain  int
+	mu      sync.Mutex
+	keyring atomic.Pointer[keyring]
+}
+
+// NewEncryptor 创建加密器
+// version为初始密钥版本，retain为密钥轮换后保留的历史密钥数量
+func NewEncryptor(builder Builder, version uint8, key []byte, retain int) *Encryptor {
+	e := &Encryptor{builder: builder, retain: retain}
+	e.err = e.Rotate(version, key)
+
+	return e
+}
+
+// Encrypt 加密
+func (e *Encryptor) Encrypt(data []byte) ([]byte, error) {
+	kr := e.keyring.Load()
+	if kr == nil {
+		return nil, e.err
+	}
+
+	aead := kr.aeads[kr.version]
+
+	ciphertext := make([]byte, defaultVersionBytes+aead.NonceSize(), defaultVersionBytes+aead.NonceSize()+len(data)+aead.Overhead())
+	ciphertext[0] = kr.version
+
+	if _, err := rand.Read(ciphertext[defaultVersionBytes:]); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(ciphertext, ciphertext[defaultVersionBytes:], data, ciphertext[:defaultVersionBytes]), nil
+}
+
+// Decrypt 解密
+func (e *Encryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	kr := e.keyring.Load()
+	if kr == nil {
+		return nil, e.err
+	}
+
+	if len(ciphertext) < defaultVersionBytes {
+		return nil, errors.ErrInvalidMessage
+	}
+
+	aead, ok := kr.aeads[ciphertext[0]]
+	if !ok {
+		return nil, errors.ErrNotFoundKey
+	}
+
+	if len(ciphertext) < defaultVersionBytes+aead.NonceSize()+aead.Overhead() {
+		return nil, errors.ErrInvalidMessage
+	}
+
+	nonce := ciphertext[defaultVersionBytes : defaultVersionBytes+aead.NonceSize()]
+
+	return aead.Open(nil, nonce, ciphertext[defaultVersionBytes+aead.NonceSize():], ciphertext[:defaultVersionBytes])
+}
+
+// Rotate 轮换密钥
+// 轮换后使用新密钥加密，旧密钥按retain配置保留用于解密轮换前加密的数据；各端需使用相同的密钥版本
+func (e *Encryptor) Rotate(version uint8, key []byte) error {
+	aead, err := e.builder(key)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	old := e.keyring.Load()
+	if old == nil {
+		e.keyring.Store(&keyring{version: version, aeads: map[uint8]cipher.AEAD{version: aead}})
+		return nil
+	}
+
+	kr := &keyring{version: version, aeads: make(map[uint8]cipher.AEAD, len(old.aeads)+1)}
+
+	for _, v := range append(old.history, old.version) {
+		if v != version {
+			kr.history = append(kr.history, v)
+		}
+	}
+
+	if n := len(kr.history) - max(e.retain, 0); n > 0 {
+		kr.history = kr.history[n:]
+	}
+
+	for _, v := range kr.history {
+		kr.aeads[v] = old.aeads[v]
+	}
+
+	kr.aeads[version] = aead
+
+	e.keyring.Store(kr)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package aead_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/dobyte/due/v2/crypto/aead"
+	"github.com/dobyte/due/v2/crypto/aead/aeadtest"
+	"github.com/dobyte/due/v2/errors"
+)
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func TestEncryptor(t *testing.T) {
+	aeadtest.Run(t, func() aeadtest.Encryptor {
+		return aead.NewEncryptor(newGCM, 0, []byte("0123456789abcdef0123456789abcdef"), 1)
+	}, [2][]byte{
+		[]byte("fedcba9876543210fedcba9876543210"),
+		[]byte("abcdefghijklmnopqrstuvwxyz012345"),
+	})
+}
+
+func TestEncryptor_InvalidKey(t *testing.T) {
+	encryptor := aead.NewEncryptor(newGCM, 0, []byte("short"), 1)
+
+	if _, err := encryptor.Encrypt([]byte("hello")); !errors.Is(err, errors.ErrInvalidKey) {
+		t.Fatalf("encrypt with invalid key, err: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package aes_test
+
+import (
+	"testing"
+
+	"github.com/dobyte/due/crypto/aes/v2"
+	"github.com/dobyte/due/v2/crypto/aead/aeadtest"
+	"github.com/dobyte/due/v2/utils/xrand"
+)
+
+const key = "0123456789abcdef0123456789abcdef"
+
+func Test_Encryptor(t *testing.T) {
+	aeadtest.Run(t, func() aeadtest.Encryptor {
+		return aes.NewEncryptor(aes.WithEncryptorKey(key), aes.WithEncryptorRetain(1))
+	}, [2][]byte{
+		[]byte("fedcba9876543210"),
+		[]byte("fedcba9876543210fedcba98"),
+	})
+}
+
+func Benchmark_Encrypt(b *testing.B) {
+	encryptor := aes.NewEncryptor(aes.WithEncryptorKey(key))
+
+	text := []byte(xrand.Letters(1024))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := encryptor.Encrypt(text); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
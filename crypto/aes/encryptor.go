@@ -0,0 +1,50 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+
+	"github.com/dobyte/due/v2/crypto/aead"
+	"github.com/dobyte/due/v2/errors"
+)
+
+// 使用AES-GCM算法
+// 密文格式：version(1 byte) | nonce(12 byte) | ciphertext(n byte) | tag(16 byte)
+
+type Encryptor struct {
+	*aead.Encryptor
+	opts *encryptorOptions
+}
+
+func NewEncryptor(opts ...EncryptorOption) *Encryptor {
+	o := defaultEncryptorOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Encryptor{
+		Encryptor: aead.NewEncryptor(newAEAD, o.version, o.key, o.retain),
+		opts:      o,
+	}
+}
+
+// Name 名称
+func (e *Encryptor) Name() string {
+	return Name
+}
+
+// 创建AEAD
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, errors.ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,3 @@
+package chacha20
+
+const Name = "chacha20"
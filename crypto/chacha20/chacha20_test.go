@@ -0,0 +1,32 @@
+package chacha20_test
+
+import (
+	"testing"
+
+	"github.com/dobyte/due/crypto/chacha20/v2"
+	"github.com/dobyte/due/v2/crypto/aead/aeadtest"
+	"github.com/dobyte/due/v2/utils/xrand"
+)
+
+const key = "0123456789abcdef0123456789abcdef"
+
+func Test_Encryptor(t *testing.T) {
+	aeadtest.Run(t, func() aeadtest.Encryptor {
+		return chacha20.NewEncryptor(chacha20.WithEncryptorKey(key), chacha20.WithEncryptorRetain(1))
+	}, [2][]byte{
+		[]byte("fedcba9876543210fedcba9876543210"),
+		[]byte("abcdefghijklmnopqrstuvwxyz012345"),
+	})
+}
+
+func Benchmark_Encrypt(b *testing.B) {
+	encryptor := chacha20.NewEncryptor(chacha20.WithEncryptorKey(key))
+
+	text := []byte(xrand.Letters(1024))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := encryptor.Encrypt(text); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
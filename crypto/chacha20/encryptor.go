@@ -0,0 +1,43 @@
+package chacha20
+
+import (
+	"crypto/cipher"
+
+	"github.com/dobyte/due/v2/crypto/aead"
+	"github.com/dobyte/due/v2/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// 使用XChaCha20-Poly1305算法，24字节的随机数可安全地随机生成
+// 密文格式：version(1 byte) | nonce(24 byte) | ciphertext(n byte) | tag(16 byte)
+
+type Encryptor struct {
+	*aead.Encryptor
+	opts *encryptorOptions
+}
+
+func NewEncryptor(opts ...EncryptorOption) *Encryptor {
+	o := defaultEncryptorOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Encryptor{
+		Encryptor: aead.NewEncryptor(newAEAD, o.version, o.key, o.retain),
+		opts:      o,
+	}
+}
+
+// Name 名称
+func (e *Encryptor) Name() string {
+	return Name
+}
+
+// 创建AEAD
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, errors.ErrInvalidKey
+	}
+
+	return chacha20poly1305.NewX(key)
+}
@@ -0,0 +1,51 @@
+package chacha20
+
+import (
+	"github.com/dobyte/due/v2/etc"
+	"github.com/dobyte/due/v2/utils/xconv"
+)
+
+const (
+	defaultEncryptorKeyKey      = "etc.crypto.chacha20.encryptor.key"
+	defaultEncryptorVersionKey  = "etc.crypto.chacha20.encryptor.version"
+	defaultEncryptorRetainKey   = "etc.crypto.chacha20.encryptor.retain"
+	defaultEncryptorRetainValue = 1
+)
+
+type EncryptorOption func(o *encryptorOptions)
+
+type encryptorOptions struct {
+	// 密钥。长度必需为32字节
+	key []byte
+
+	// 密钥版本。密钥版本会写入密文头部，解密时根据版本选择对应的密钥
+	// 默认为0
+	version uint8
+
+	// 密钥轮换后保留的历史密钥数量，用于解密轮换前加密的数据
+	// 默认为1
+	retain int
+}
+
+func defaultEncryptorOptions() *encryptorOptions {
+	return &encryptorOptions{
+		key:     etc.Get(defaultEncryptorKeyKey).Bytes(),
+		version: uint8(etc.Get(defaultEncryptorVersionKey).Uint()),
+		retain:  etc.Get(defaultEncryptorRetainKey, defaultEncryptorRetainValue).Int(),
+	}
+}
+
+// WithEncryptorKey 设置加密密钥
+func WithEncryptorKey(key string) EncryptorOption {
+	return func(o *encryptorOptions) { o.key = xconv.StringToBytes(key) }
+}
+
+// WithEncryptorVersion 设置加密密钥版本
+func WithEncryptorVersion(version uint8) EncryptorOption {
+	return func(o *encryptorOptions) { o.version = version }
+}
+
+// WithEncryptorRetain 设置密钥轮换后保留的历史密钥数量
+func WithEncryptorRetain(retain int) EncryptorOption {
+	return func(o *encryptorOptions) { o.retain = retain }
+}
@@ -0,0 +1,73 @@
+package handshake
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+
+	"github.com/dobyte/due/v2/crypto"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/packet"
+)
+
+type Client struct {
+	verifier crypto.Signer
+	suite    Suite
+	key      *ecdh.PrivateKey
+	hello    []byte
+}
+
+// NewClient 创建握手客户端，verifier用于校验服务端的签名，suite为期望使用的加密套件，默认为AES-256-GCM
+func NewClient(verifier crypto.Signer, suite ...Suite) *Client {
+	c := &Client{verifier: verifier, suite: AES256GCM}
+
+	if len(suite) > 0 && suite[0] != 0 {
+		c.suite = suite[0]
+	}
+
+	return c
+}
+
+// Hello 生成客户端握手消息
+func (c *Client) Hello() ([]byte, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	c.key = key
+	c.hello = make([]byte, 0, helloBytes)
+	c.hello = append(c.hello, version, byte(c.suite))
+	c.hello = append(c.hello, key.PublicKey().Bytes()...)
+
+	return c.hello, nil
+}
+
+// Finish 处理服务端握手消息，校验签名后返回会话密码器
+func (c *Client) Finish(reply []byte) (*packet.Cipher, error) {
+	if c.key == nil || c.verifier == nil {
+		return nil, errors.ErrHandshakeFailed
+	}
+
+	if len(reply) <= helloBytes || reply[0] != version || Suite(reply[1]) != c.suite {
+		return nil, errors.ErrHandshakeFailed
+	}
+
+	transcript := append(append(make([]byte, 0, 2*helloBytes), c.hello...), reply[:helloBytes]...)
+
+	ok, err := c.verifier.Verify(transcript, reply[helloBytes:])
+	if err != nil || !ok {
+		return nil, errors.ErrHandshakeFailed
+	}
+
+	peer, err := ecdh.X25519().NewPublicKey(reply[2:helloBytes])
+	if err != nil {
+		return nil, errors.ErrHandshakeFailed
+	}
+
+	secret, err := c.key.ECDH(peer)
+	if err != nil {
+		return nil, errors.ErrHandshakeFailed
+	}
+
+	return deriveCipher(c.suite, secret, transcript, false)
+}
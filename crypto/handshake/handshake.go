@@ -0,0 +1,120 @@
+package handshake
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"strings"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/packet"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// client hello
+// -------------------------------------------------------------
+// | version(1 byte) | suite(1 byte) | client public key(32 byte) |
+// -------------------------------------------------------------
+
+// server hello
+// ---------------------------------------------------------------------------------
+// | version(1 byte) | suite(1 byte) | server public key(32 byte) | signature(n byte) |
+// ---------------------------------------------------------------------------------
+
+const (
+	version        = 1
+	publicKeyBytes = 32
+	helloBytes     = 2 + publicKeyBytes
+	keyBytes       = 32
+)
+
+const (
+	clientToServer = "due handshake client to server"
+	serverToClient = "due handshake server to client"
+)
+
+type Suite uint8
+
+const (
+	AES256GCM        Suite = iota + 1 // AES-256-GCM
+	ChaCha20Poly1305                  // ChaCha20-Poly1305
+)
+
+const (
+	aes256gcmName        = "aes-256-gcm"
+	chacha20poly1305Name = "chacha20-poly1305"
+)
+
+// ParseSuite 解析加密套件名称
+func ParseSuite(name string) Suite {
+	switch strings.ToLower(name) {
+	case aes256gcmName:
+		return AES256GCM
+	case chacha20poly1305Name:
+		return ChaCha20Poly1305
+	default:
+		return 0
+	}
+}
+
+// String 获取加密套件名称
+func (s Suite) String() string {
+	switch s {
+	case AES256GCM:
+		return aes256gcmName
+	case ChaCha20Poly1305:
+		return chacha20poly1305Name
+	default:
+		return ""
+	}
+}
+
+// 创建AEAD
+func (s Suite) aead(key []byte) (cipher.AEAD, error) {
+	switch s {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, errors.ErrHandshakeFailed
+	}
+}
+
+// 派生会话密码器
+// 以双方的握手消息作为盐值，按方向派生出两把独立的密钥
+func deriveCipher(suite Suite, secret, transcript []byte, isServer bool) (*packet.Cipher, error) {
+	salt := sha256.Sum256(transcript)
+
+	c2s, err := hkdf.Key(sha256.New, secret, salt[:], clientToServer, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	s2c, err := hkdf.Key(sha256.New, secret, salt[:], serverToClient, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	c2sAEAD, err := suite.aead(c2s)
+	if err != nil {
+		return nil, err
+	}
+
+	s2cAEAD, err := suite.aead(s2c)
+	if err != nil {
+		return nil, err
+	}
+
+	if isServer {
+		return packet.NewCipher(s2cAEAD, c2sAEAD), nil
+	}
+
+	return packet.NewCipher(c2sAEAD, s2cAEAD), nil
+}
@@ -0,0 +1,115 @@
+package handshake_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/dobyte/due/v2/crypto/handshake"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/packet"
+)
+
+type signer struct {
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+}
+
+func (s *signer) Name() string { return "ed25519" }
+
+func (s *signer) Sign(data []byte) ([]byte, error) { return ed25519.Sign(s.privateKey, data), nil }
+
+func (s *signer) Verify(data []byte, signature []byte) (bool, error) {
+	return ed25519.Verify(s.publicKey, data, signature), nil
+}
+
+func newSigner(t *testing.T) *signer {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &signer{publicKey: publicKey, privateKey: privateKey}
+}
+
+func TestHandshake(t *testing.T) {
+	s := newSigner(t)
+
+	for _, suite := range []handshake.Suite{handshake.AES256GCM, handshake.ChaCha20Poly1305} {
+		client := handshake.NewClient(s, suite)
+		server := handshake.NewServer(s)
+
+		hello, err := client.Hello()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reply, serverCipher, err := server.Accept(hello)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clientCipher, err := client.Finish(reply)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := packet.PackMessage(&packet.Message{Seq: 1, Route: 2, Buffer: []byte("hello world")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sealed, err := packet.Seal(clientCipher, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opened, err := packet.Open(serverCipher, sealed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		message, err := packet.UnpackMessage(opened)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(message.Buffer) != "hello world" || message.Seq != 1 || message.Route != 2 {
+			t.Fatalf("%s: unexpected message: %+v", suite, message)
+		}
+
+		if _, err = packet.Open(serverCipher, sealed); !errors.Is(err, errors.ErrReplayedMessage) {
+			t.Fatalf("%s: replayed message should be rejected, err: %v", suite, err)
+		}
+
+		sealed, err = packet.Seal(serverCipher, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sealed[len(sealed)-1] ^= 0xff
+
+		if _, err = packet.Open(clientCipher, sealed); err == nil {
+			t.Fatalf("%s: tampered message should be rejected", suite)
+		}
+	}
+}
+
+func TestHandshake_InvalidSignature(t *testing.T) {
+	client := handshake.NewClient(newSigner(t))
+	server := handshake.NewServer(newSigner(t))
+
+	hello, err := client.Hello()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply, _, err := server.Accept(hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.Finish(reply); !errors.Is(err, errors.ErrHandshakeFailed) {
+		t.Fatalf("handshake should be failed, err: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package handshake
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"slices"
+
+	"github.com/dobyte/due/v2/crypto"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/packet"
+)
+
+type Server struct {
+	signer crypto.Signer
+	suites []Suite
+}
+
+// NewServer 创建握手服务端，signer用于对握手消息进行签名，suites为允许使用的加密套件，默认允许所有加密套件
+func NewServer(signer crypto.Signer, suites ...Suite) *Server {
+	if len(suites) == 0 {
+		suites = []Suite{AES256GCM, ChaCha20Poly1305}
+	}
+
+	return &Server{signer: signer, suites: suites}
+}
+
+// Accept 处理客户端握手消息，返回服务端握手消息及会话密码器
+func (s *Server) Accept(hello []byte) ([]byte, *packet.Cipher, error) {
+	if s.signer == nil {
+		return nil, nil, errors.ErrHandshakeFailed
+	}
+
+	if len(hello) != helloBytes || hello[0] != version {
+		return nil, nil, errors.ErrHandshakeFailed
+	}
+
+	suite := Suite(hello[1])
+
+	if !slices.Contains(s.suites, suite) {
+		return nil, nil, errors.ErrHandshakeFailed
+	}
+
+	peer, err := ecdh.X25519().NewPublicKey(hello[2:])
+	if err != nil {
+		return nil, nil, errors.ErrHandshakeFailed
+	}
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := key.ECDH(peer)
+	if err != nil {
+		return nil, nil, errors.ErrHandshakeFailed
+	}
+
+	reply := make([]byte, 0, helloBytes)
+	reply = append(reply, version, byte(suite))
+	reply = append(reply, key.PublicKey().Bytes()...)
+
+	transcript := append(append(make([]byte, 0, 2*helloBytes), hello...), reply...)
+
+	signature, err := s.signer.Sign(transcript)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := deriveCipher(suite, secret, transcript, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(reply, signature...), c, nil
+}
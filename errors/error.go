@@ -32,6 +32,12 @@ var (
 	ErrSeqOverflow             = New("seq overflow")
 	ErrRouteOverflow           = New("route overflow")
 	ErrMessageTooLarge         = New("message too large")
+	ErrInvalidCompression      = New("invalid compression")
+	ErrInvalidPacker           = New("invalid packer")
+	ErrReplayedMessage         = New("replayed message")
+	ErrHandshakeFailed         = New("handshake failed")
+	ErrHandshakeTimeout        = New("handshake timeout")
+	ErrResumeFailed            = New("resume session failed")
 	ErrInvalidDecoder          = New("invalid decoder")
 	ErrInvalidScanner          = New("invalid scanner")
 	ErrNoOperationPermission   = New("no operation permission")
@@ -54,6 +60,9 @@ var (
 	ErrUnregisterRoute         = New("unregistered route")
 	ErrNotBindActor            = New("not bind actor")
 	ErrNotFoundActor           = New("not found actor")
+	ErrNotFoundActorLocation   = New("not found actor's location")
+	ErrActorDestroyed          = New("actor destroyed")
+	ErrMissingMessageHandler   = New("missing message handler")
 	ErrSyncerClosed            = New("syncer is closed")
 	ErrDeadlineExceeded        = New("deadline exceeded")
 	ErrMissingResolver         = New("missing resolver")
@@ -62,11 +71,15 @@ var (
 	ErrInvalidPublicKey        = New("invalid public key")
 	ErrInvalidPrivateKey       = New("invalid private key")
 	ErrInvalidSignature        = New("invalid signature")
+	ErrInvalidKey              = New("invalid key")
+	ErrNotFoundKey             = New("not found key")
 	ErrNotFoundIPAddress       = New("not found ip address")
 	ErrInvalidServiceDesc      = New("invalid service desc")
 	ErrInvalidCertFile         = New("invalid cert file")
 	ErrMissingCacheInstance    = New("missing cache instance")
 	ErrMissingEventbusInstance = New("missing eventbus instance")
+	ErrUnsupportedScheme       = New("unsupported scheme")
+	ErrCircuitOpen             = New("circuit breaker is open")
 )
 
 // NewError 新建一个错误
@@ -9,8 +9,10 @@ require (
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/google/uuid v1.6.0
 	github.com/jinzhu/copier v0.4.0
+	github.com/klauspost/compress v1.17.11
 	github.com/panjf2000/ants/v2 v2.11.4
 	github.com/shamaton/msgpack/v2 v2.4.0
+	golang.org/x/crypto v0.31.0
 	golang.org/x/sync v0.20.0
 	google.golang.org/protobuf v1.36.6
 	gopkg.in/yaml.v3 v3.0.1
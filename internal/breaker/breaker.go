@@ -0,0 +1,201 @@
+package breaker
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+)
+
+const (
+	defaultPercentile = 0.99             // 默认延迟分位
+	defaultWindow     = 100              // 默认延迟统计窗口的样本数
+	defaultCooldown   = 10 * time.Second // 默认熔断冷却时间
+	defaultProbes     = 1                // 默认半开状态下的探测请求数
+)
+
+// State 熔断器状态
+type State int32
+
+const (
+	Closed   State = iota // 关闭，正常放行
+	Open                  // 打开，拒绝请求
+	HalfOpen              // 半开，放行探测请求
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type transition struct {
+	from   State
+	to     State
+	reason string
+}
+
+type Breaker struct {
+	opts      *cluster.BreakerOptions
+	mu        sync.Mutex
+	state     State
+	failures  int             // 连续失败次数
+	samples   []time.Duration // 延迟样本
+	openedAt  time.Time       // 熔断时间
+	probing   int             // 进行中的探测请求数
+	succeeded int             // 成功的探测请求数
+	probedAt  time.Time       // 最近一次放行探测请求的时间
+}
+
+func newBreaker(opts *cluster.BreakerOptions) *Breaker {
+	return &Breaker{opts: opts}
+}
+
+// State 获取状态
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Ready 检测是否可放行请求，不占用探测名额
+func (b *Breaker) Ready() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		return time.Since(b.openedAt) >= b.opts.Cooldown
+	case HalfOpen:
+		return b.probing < b.opts.Probes || time.Since(b.probedAt) >= b.opts.Cooldown
+	default:
+		return true
+	}
+}
+
+// 放行请求
+// 放行成功后必需调用done上报请求结果
+func (b *Breaker) allow() (bool, *transition) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if time.Since(b.openedAt) < b.opts.Cooldown {
+			return false, nil
+		}
+
+		b.state = HalfOpen
+		b.probing = 1
+		b.succeeded = 0
+		b.probedAt = time.Now()
+
+		return true, &transition{from: Open, to: HalfOpen, reason: "cooldown elapsed"}
+	case HalfOpen:
+		switch {
+		case b.probing < b.opts.Probes:
+			b.probing++
+		case time.Since(b.probedAt) >= b.opts.Cooldown:
+			// 探测请求长时间未上报结果，重新放行探测
+			b.probing = 1
+			b.succeeded = 0
+		default:
+			return false, nil
+		}
+
+		b.probedAt = time.Now()
+
+		return true, nil
+	default:
+		return true, nil
+	}
+}
+
+// 上报请求结果
+func (b *Breaker) done(failed bool, latency time.Duration) *transition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Closed:
+		if failed {
+			if b.failures++; b.opts.Errors > 0 && b.failures >= b.opts.Errors {
+				return b.trip(fmt.Sprintf("%d consecutive errors", b.failures))
+			}
+
+			return nil
+		}
+
+		b.failures = 0
+
+		if b.opts.Latency <= 0 {
+			return nil
+		}
+
+		if b.samples = append(b.samples, latency); len(b.samples) < b.opts.Window {
+			return nil
+		}
+
+		p := percentile(b.samples, b.opts.Percentile)
+		b.samples = b.samples[:0]
+
+		if p >= b.opts.Latency {
+			return b.trip(fmt.Sprintf("p%g latency %v exceeds %v", b.opts.Percentile*100, p, b.opts.Latency))
+		}
+
+		return nil
+	case HalfOpen:
+		if failed {
+			return b.trip("probe failed")
+		}
+
+		if b.opts.Latency > 0 && latency >= b.opts.Latency {
+			return b.trip(fmt.Sprintf("probe latency %v exceeds %v", latency, b.opts.Latency))
+		}
+
+		if b.succeeded++; b.succeeded < b.opts.Probes {
+			return nil
+		}
+
+		b.state = Closed
+		b.failures = 0
+		b.probing = 0
+		b.samples = b.samples[:0]
+
+		return &transition{from: HalfOpen, to: Closed, reason: "probe succeeded"}
+	default:
+		return nil
+	}
+}
+
+// 熔断
+func (b *Breaker) trip(reason string) *transition {
+	from := b.state
+
+	b.state = Open
+	b.openedAt = time.Now()
+	b.failures = 0
+	b.probing = 0
+	b.samples = b.samples[:0]
+
+	return &transition{from: from, to: Open, reason: reason}
+}
+
+// 计算延迟分位
+func percentile(samples []time.Duration, p float64) time.Duration {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	return sorted[max(min(i, len(sorted)-1), 0)]
+}
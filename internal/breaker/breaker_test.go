@@ -0,0 +1,98 @@
+package breaker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/internal/breaker"
+)
+
+func TestGroup_Errors(t *testing.T) {
+	var states []breaker.State
+
+	g := breaker.NewGroup(cluster.BreakerOptions{
+		Errors:   3,
+		Cooldown: 50 * time.Millisecond,
+	}, func(key string, from, to breaker.State, reason string) {
+		t.Logf("%s: %s -> %s (%s)", key, from, to, reason)
+		states = append(states, to)
+	})
+
+	for range 3 {
+		if !g.Allow("node-1") {
+			t.Fatal("breaker should be closed")
+		}
+
+		g.Done("node-1", true, time.Millisecond)
+	}
+
+	if g.Ready("node-1") || g.Allow("node-1") {
+		t.Fatal("breaker should be open")
+	}
+
+	if !g.Ready("node-2") {
+		t.Fatal("other instances should not be affected")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if !g.Ready("node-1") || !g.Allow("node-1") {
+		t.Fatal("breaker should allow a probe after cooldown")
+	}
+
+	if g.Allow("node-1") {
+		t.Fatal("breaker should allow only one probe")
+	}
+
+	g.Done("node-1", false, time.Millisecond)
+
+	if !g.Allow("node-1") {
+		t.Fatal("breaker should be closed after a successful probe")
+	}
+
+	expected := []breaker.State{breaker.Open, breaker.HalfOpen, breaker.Closed}
+	if len(states) != len(expected) {
+		t.Fatalf("unexpected transitions: %v", states)
+	}
+
+	for i := range expected {
+		if states[i] != expected[i] {
+			t.Fatalf("unexpected transitions: %v", states)
+		}
+	}
+}
+
+func TestGroup_Latency(t *testing.T) {
+	g := breaker.NewGroup(cluster.BreakerOptions{
+		Latency:    100 * time.Millisecond,
+		Percentile: 0.9,
+		Window:     10,
+	}, nil)
+
+	for i := range 10 {
+		latency := 10 * time.Millisecond
+		if i >= 8 {
+			latency = 200 * time.Millisecond
+		}
+
+		g.Allow("node-1")
+		g.Done("node-1", false, latency)
+	}
+
+	if g.Ready("node-1") {
+		t.Fatal("breaker should be open when the p90 latency exceeds the threshold")
+	}
+}
+
+func TestGroup_Disabled(t *testing.T) {
+	g := breaker.NewGroup(cluster.BreakerOptions{}, nil)
+
+	for range 10 {
+		g.Done("node-1", true, time.Second)
+	}
+
+	if !g.Allow("node-1") {
+		t.Fatal("disabled breaker should always allow requests")
+	}
+}
@@ -0,0 +1,126 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/etc"
+)
+
+// StateChangeHandler 熔断器状态变更处理器
+type StateChangeHandler func(key string, from, to State, reason string)
+
+// Group 熔断器组，按实例分别维护熔断器
+// 未开启熔断时返回nil，nil熔断器组始终放行请求
+type Group struct {
+	opts     cluster.BreakerOptions
+	handler  StateChangeHandler
+	breakers sync.Map
+}
+
+// NewGroup 新建熔断器组
+func NewGroup(opts cluster.BreakerOptions, handler StateChangeHandler) *Group {
+	if opts.Errors <= 0 && opts.Latency <= 0 {
+		return nil
+	}
+
+	if opts.Percentile <= 0 || opts.Percentile > 1 {
+		opts.Percentile = defaultPercentile
+	}
+
+	if opts.Window <= 0 {
+		opts.Window = defaultWindow
+	}
+
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = defaultCooldown
+	}
+
+	if opts.Probes <= 0 {
+		opts.Probes = defaultProbes
+	}
+
+	return &Group{opts: opts, handler: handler}
+}
+
+// LoadOptions 加载熔断配置
+func LoadOptions(key string) cluster.BreakerOptions {
+	return cluster.BreakerOptions{
+		Errors:     etc.Get(key + ".errors").Int(),
+		Latency:    etc.Get(key + ".latency").Duration(),
+		Percentile: etc.Get(key + ".percentile").Float64(),
+		Window:     etc.Get(key + ".window").Int(),
+		Cooldown:   etc.Get(key + ".cooldown").Duration(),
+		Probes:     etc.Get(key + ".probes").Int(),
+	}
+}
+
+// Ready 检测实例是否可放行请求
+func (g *Group) Ready(key string) bool {
+	if g == nil {
+		return true
+	}
+
+	if b, ok := g.breakers.Load(key); ok {
+		return b.(*Breaker).Ready()
+	}
+
+	return true
+}
+
+// Allow 放行实例请求
+// 放行成功后必需调用Done上报请求结果
+func (g *Group) Allow(key string) bool {
+	if g == nil {
+		return true
+	}
+
+	ok, t := g.load(key).allow()
+
+	g.notify(key, t)
+
+	return ok
+}
+
+// Done 上报实例请求结果
+func (g *Group) Done(key string, failed bool, latency time.Duration) {
+	if g == nil {
+		return
+	}
+
+	g.notify(key, g.load(key).done(failed, latency))
+}
+
+// Retain 仅保留满足条件的实例熔断器
+func (g *Group) Retain(fn func(key string) bool) {
+	if g == nil {
+		return
+	}
+
+	g.breakers.Range(func(key, _ any) bool {
+		if !fn(key.(string)) {
+			g.breakers.Delete(key)
+		}
+
+		return true
+	})
+}
+
+// 加载熔断器
+func (g *Group) load(key string) *Breaker {
+	if b, ok := g.breakers.Load(key); ok {
+		return b.(*Breaker)
+	}
+
+	b, _ := g.breakers.LoadOrStore(key, newBreaker(&g.opts))
+
+	return b.(*Breaker)
+}
+
+// 通知状态变更
+func (g *Group) notify(key string, t *transition) {
+	if t != nil && g.handler != nil {
+		g.handler(key, t.from, t.to, t.reason)
+	}
+}
@@ -1,6 +1,10 @@
 package dispatcher
 
 import (
+	"math"
+	"strconv"
+	"sync/atomic"
+
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/core/endpoint"
 )
@@ -11,6 +15,40 @@ type serviceEndpoint struct {
 	endpoint   *endpoint.Endpoint
 	weight     int
 	currWeight int
+	load       *serviceLoad
+	metadata   map[string]string
+}
+
+type serviceLoad struct {
+	inflight   atomic.Int64  // 上报的处理中请求数
+	actors     atomic.Int64  // 上报的Actor数量
+	cpu        atomic.Uint64 // 上报的CPU使用率（float64的位表示）
+	dispatched atomic.Int64  // 本次上报后本地已分配的请求数
+}
+
+func newServiceLoad(metadata map[string]string) *serviceLoad {
+	l := &serviceLoad{}
+	l.update(metadata)
+
+	return l
+}
+
+// 更新上报的负载，并清空本地已分配的请求数
+func (l *serviceLoad) update(metadata map[string]string) {
+	inflight, _ := strconv.ParseInt(metadata[cluster.LoadInflightKey], 10, 64)
+	actors, _ := strconv.ParseInt(metadata[cluster.LoadActorsKey], 10, 64)
+	cpu, _ := strconv.ParseFloat(metadata[cluster.LoadCPUKey], 64)
+
+	l.inflight.Store(inflight)
+	l.actors.Store(actors)
+	l.cpu.Store(math.Float64bits(cpu))
+	l.dispatched.Store(0)
+}
+
+// 负载评分，评分越低负载越小
+// 本地已分配的请求数用于弥补上报间隔内的负载变化，避免请求集中涌向同一实例
+func (l *serviceLoad) score() float64 {
+	return float64(l.inflight.Load()+l.dispatched.Load()+1) * (1 + math.Float64frombits(l.cpu.Load())/100)
 }
 
 type abstract struct {
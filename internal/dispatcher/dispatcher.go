@@ -1,7 +1,10 @@
 package dispatcher
 
 import (
+	"maps"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/core/endpoint"
@@ -12,17 +15,32 @@ import (
 
 type Dispatcher struct {
 	dispatch  cluster.Dispatch
+	filter    atomic.Value
 	rw        sync.RWMutex
 	routes    map[int32]*Route
 	events    map[int]*Event
 	endpoints map[string]*endpoint.Endpoint
 	instances map[string]*registry.ServiceInstance
+	loads     map[string]*serviceLoad
 }
 
 func NewDispatcher(dispatch cluster.Dispatch) *Dispatcher {
 	return &Dispatcher{dispatch: dispatch}
 }
 
+// SetFilter 设置实例过滤器
+// 过滤器返回false的实例将暂时不参与无状态路由的分配
+func (d *Dispatcher) SetFilter(filter func(insID string) bool) {
+	d.filter.Store(filter)
+}
+
+// 加载实例过滤器
+func (d *Dispatcher) loadFilter() func(insID string) bool {
+	filter, _ := d.filter.Load().(func(insID string) bool)
+
+	return filter
+}
+
 // FindEndpoint 查找服务端口
 func (d *Dispatcher) FindEndpoint(insID string) (*endpoint.Endpoint, error) {
 	d.rw.RLock()
@@ -83,11 +101,17 @@ func (d *Dispatcher) FindEvent(event int) (*Event, error) {
 }
 
 // ReplaceServices 替换服务
+// 仅负载信息发生变化时原地更新实例负载，不重建路由，避免频繁的负载上报导致哈希环等分发状态被重建
 func (d *Dispatcher) ReplaceServices(services ...*registry.ServiceInstance) {
+	if d.updateLoads(services) {
+		return
+	}
+
 	routes := make(map[int32]*Route, len(services))
 	events := make(map[int]*Event, len(services))
 	endpoints := make(map[string]*endpoint.Endpoint)
 	instances := make(map[string]*registry.ServiceInstance, len(services))
+	loads := make(map[string]*serviceLoad, len(services))
 
 	for _, service := range services {
 		ep, err := endpoint.ParseEndpoint(service.Endpoint)
@@ -99,6 +123,8 @@ func (d *Dispatcher) ReplaceServices(services ...*registry.ServiceInstance) {
 
 		endpoints[service.ID] = ep
 		instances[service.ID] = service
+		load := newServiceLoad(service.Metadata)
+		loads[service.ID] = load
 
 		for _, item := range service.Routes {
 			route, ok := routes[item.ID]
@@ -111,6 +137,8 @@ func (d *Dispatcher) ReplaceServices(services ...*registry.ServiceInstance) {
 				state:    service.State,
 				endpoint: ep,
 				weight:   service.Weight,
+				load:     load,
+				metadata: service.Metadata,
 			})
 		}
 
@@ -125,6 +153,8 @@ func (d *Dispatcher) ReplaceServices(services ...*registry.ServiceInstance) {
 				state:    service.State,
 				endpoint: ep,
 				weight:   service.Weight,
+				load:     load,
+				metadata: service.Metadata,
 			})
 		}
 	}
@@ -134,5 +164,52 @@ func (d *Dispatcher) ReplaceServices(services ...*registry.ServiceInstance) {
 	d.events = events
 	d.endpoints = endpoints
 	d.instances = instances
+	d.loads = loads
 	d.rw.Unlock()
 }
+
+// 更新实例负载，实例的路由信息发生变化时返回false
+func (d *Dispatcher) updateLoads(services []*registry.ServiceInstance) bool {
+	d.rw.Lock()
+	defer d.rw.Unlock()
+
+	if len(services) == 0 || len(services) != len(d.instances) {
+		return false
+	}
+
+	for _, service := range services {
+		if ins, ok := d.instances[service.ID]; !ok || !equalService(ins, service) {
+			return false
+		}
+	}
+
+	for _, service := range services {
+		d.instances[service.ID] = service
+		d.loads[service.ID].update(service.Metadata)
+	}
+
+	return true
+}
+
+// 比对实例除负载信息外的路由信息是否一致
+func equalService(a, b *registry.ServiceInstance) bool {
+	if a.ID != b.ID || a.Name != b.Name || a.Kind != b.Kind || a.Alias != b.Alias || a.State != b.State || a.Endpoint != b.Endpoint || a.Weight != b.Weight {
+		return false
+	}
+
+	if !slices.Equal(a.Events, b.Events) || !slices.Equal(a.Routes, b.Routes) || !slices.Equal(a.Services, b.Services) {
+		return false
+	}
+
+	return maps.Equal(withoutLoad(a.Metadata), withoutLoad(b.Metadata))
+}
+
+// 去除元数据中的负载信息
+func withoutLoad(metadata map[string]string) map[string]string {
+	m := maps.Clone(metadata)
+	delete(m, cluster.LoadInflightKey)
+	delete(m, cluster.LoadActorsKey)
+	delete(m, cluster.LoadCPUKey)
+
+	return m
+}
@@ -196,6 +196,353 @@ func TestDispatcher_WeightRoundRobin(t *testing.T) {
 	}
 }
 
+func TestDispatcher_ConsistentHash(t *testing.T) {
+	instances := make([]*registry.ServiceInstance, 0, 5)
+	for i := range 5 {
+		instances = append(instances, &registry.ServiceInstance{
+			ID:       fmt.Sprintf("node-%d", i+1),
+			Name:     fmt.Sprintf("node-%d", i+1),
+			Kind:     cluster.Node.String(),
+			Alias:    "node",
+			State:    cluster.Work.String(),
+			Endpoint: endpoint.NewEndpoint("grpc", fmt.Sprintf("127.0.0.1:%d", 8001+i), false).String(),
+			Routes: []registry.Route{{
+				ID:       1,
+				Stateful: false,
+			}},
+		})
+	}
+
+	// 统计每个分发键分配到的实例地址
+	dispatch := func(instances ...*registry.ServiceInstance) map[string]string {
+		d := dispatcher.NewDispatcher(cluster.ConsistentHash)
+		d.ReplaceServices(instances...)
+
+		route, err := d.FindRoute(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := make(map[string]string)
+		for i := range 10000 {
+			key := fmt.Sprintf("%d", i)
+
+			_, ep1, err := route.DispatchEndpoint(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, ep2, err := route.DispatchEndpoint(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ep1.Address() != ep2.Address() {
+				t.Fatalf("key %s dispatched to different endpoints: %s %s", key, ep1.Address(), ep2.Address())
+			}
+
+			result[key] = ep1.Address()
+		}
+
+		return result
+	}
+
+	before := dispatch(instances...)
+	after := dispatch(instances[:4]...)
+
+	counts := make(map[string]int)
+	moved := 0
+	for key, addr := range before {
+		counts[addr]++
+
+		if addr != "127.0.0.1:8005" && after[key] != addr {
+			moved++
+		}
+	}
+
+	for addr, count := range counts {
+		if ratio := float64(count) / float64(len(before)); math.Abs(ratio-0.2) > 0.08 {
+			t.Errorf("distribution ratio for %s is %.3f, want 0.2 (±0.08)", addr, ratio)
+		}
+	}
+
+	// 移除实例后，仅该实例上的分发键发生迁移
+	if moved != 0 {
+		t.Errorf("%d keys moved between remaining instances, want 0", moved)
+	}
+}
+
+func TestDispatcher_LeastLoad(t *testing.T) {
+	inflights := []int{10, 0, 5}
+	instances := make([]*registry.ServiceInstance, 0, len(inflights))
+	for i, inflight := range inflights {
+		instances = append(instances, &registry.ServiceInstance{
+			ID:       fmt.Sprintf("node-%d", i+1),
+			Name:     fmt.Sprintf("node-%d", i+1),
+			Kind:     cluster.Node.String(),
+			Alias:    "node",
+			State:    cluster.Work.String(),
+			Endpoint: endpoint.NewEndpoint("grpc", fmt.Sprintf("127.0.0.1:%d", 8001+i), false).String(),
+			Metadata: map[string]string{cluster.LoadInflightKey: fmt.Sprintf("%d", inflight)},
+			Routes: []registry.Route{{
+				ID:       1,
+				Stateful: false,
+			}},
+		})
+	}
+
+	d := dispatcher.NewDispatcher(cluster.LeastLoad)
+	d.ReplaceServices(instances...)
+
+	route, err := d.FindRoute(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	for range 30 {
+		ep, err := route.FindEndpoint()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts[ep.Address()]++
+	}
+
+	// 分配完成后各实例负载持平
+	expected := map[string]int{
+		"127.0.0.1:8001": 5,
+		"127.0.0.1:8002": 15,
+		"127.0.0.1:8003": 10,
+	}
+
+	for addr, count := range expected {
+		if counts[addr] != count {
+			t.Errorf("%s selected %d times, want %d", addr, counts[addr], count)
+		}
+	}
+}
+
+func TestDispatcher_UpdateLoads(t *testing.T) {
+	newInstances := func(inflights []int, states ...cluster.State) []*registry.ServiceInstance {
+		instances := make([]*registry.ServiceInstance, 0, len(inflights))
+		for i, inflight := range inflights {
+			state := cluster.Work
+			if i < len(states) {
+				state = states[i]
+			}
+
+			instances = append(instances, &registry.ServiceInstance{
+				ID:       fmt.Sprintf("node-%d", i+1),
+				Name:     fmt.Sprintf("node-%d", i+1),
+				Kind:     cluster.Node.String(),
+				Alias:    "node",
+				State:    state.String(),
+				Endpoint: endpoint.NewEndpoint("grpc", fmt.Sprintf("127.0.0.1:%d", 8001+i), false).String(),
+				Metadata: map[string]string{cluster.LoadInflightKey: fmt.Sprintf("%d", inflight)},
+				Routes: []registry.Route{{
+					ID:       1,
+					Stateful: false,
+				}},
+			})
+		}
+
+		return instances
+	}
+
+	d := dispatcher.NewDispatcher(cluster.LeastLoad)
+	d.ReplaceServices(newInstances([]int{0, 10})...)
+
+	before, err := d.FindRoute(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 仅负载信息变化时不重建路由
+	d.ReplaceServices(newInstances([]int{10, 0})...)
+
+	after, err := d.FindRoute(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if before != after {
+		t.Fatal("route is rebuilt on load update")
+	}
+
+	if insID, _, err := after.DispatchEndpoint(""); err != nil || insID != "node-2" {
+		t.Fatalf("insID = %s, err = %v, want node-2", insID, err)
+	}
+
+	// 实例状态变化时重建路由
+	d.ReplaceServices(newInstances([]int{10, 0}, cluster.Work, cluster.Busy)...)
+
+	if after, err = d.FindRoute(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if before == after {
+		t.Fatal("route is not rebuilt on state change")
+	}
+}
+
+func TestDispatcher_ConsistentHashStateChange(t *testing.T) {
+	dispatch := func(states ...cluster.State) map[string]string {
+		instances := make([]*registry.ServiceInstance, 0, len(states))
+		for i, state := range states {
+			instances = append(instances, &registry.ServiceInstance{
+				ID:       fmt.Sprintf("node-%d", i+1),
+				Name:     fmt.Sprintf("node-%d", i+1),
+				Kind:     cluster.Node.String(),
+				Alias:    "node",
+				State:    state.String(),
+				Endpoint: endpoint.NewEndpoint("grpc", fmt.Sprintf("127.0.0.1:%d", 8001+i), false).String(),
+				Routes: []registry.Route{{
+					ID:       1,
+					Stateful: false,
+				}},
+			})
+		}
+
+		d := dispatcher.NewDispatcher(cluster.ConsistentHash)
+		d.ReplaceServices(instances...)
+
+		route, err := d.FindRoute(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := make(map[string]string)
+		for i := range 1000 {
+			insID, _, err := route.DispatchEndpoint(fmt.Sprintf("%d", i))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result[fmt.Sprintf("%d", i)] = insID
+		}
+
+		return result
+	}
+
+	before := dispatch(cluster.Work, cluster.Work, cluster.Work)
+	after := dispatch(cluster.Work, cluster.Busy, cluster.Work)
+
+	// 实例在work与busy状态间切换时分发键不发生迁移
+	for key, insID := range before {
+		if after[key] != insID {
+			t.Fatalf("key %s moved from %s to %s", key, insID, after[key])
+		}
+	}
+}
+
+func TestDispatcher_SetFilter(t *testing.T) {
+	instances := make([]*registry.ServiceInstance, 0, 3)
+	for i := range 3 {
+		instances = append(instances, &registry.ServiceInstance{
+			ID:       fmt.Sprintf("node-%d", i+1),
+			Name:     fmt.Sprintf("node-%d", i+1),
+			Kind:     cluster.Node.String(),
+			Alias:    "node",
+			State:    cluster.Work.String(),
+			Endpoint: endpoint.NewEndpoint("grpc", fmt.Sprintf("127.0.0.1:%d", 8001+i), false).String(),
+			Routes: []registry.Route{{
+				ID:       1,
+				Stateful: false,
+			}},
+		})
+	}
+
+	for _, dispatch := range []cluster.Dispatch{cluster.Random, cluster.RoundRobin, cluster.WeightRoundRobin, cluster.ConsistentHash, cluster.LeastLoad} {
+		d := dispatcher.NewDispatcher(dispatch)
+		d.ReplaceServices(instances...)
+
+		route, err := d.FindRoute(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d.SetFilter(func(insID string) bool { return insID != "node-1" })
+
+		for i := range 100 {
+			insID, _, err := route.DispatchEndpoint(fmt.Sprintf("%d", i))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if insID == "node-1" {
+				t.Fatalf("%s: ejected instance is dispatched", dispatch)
+			}
+		}
+
+		// 所有实例均被过滤时忽略过滤器
+		d.SetFilter(func(insID string) bool { return false })
+
+		if _, _, err = route.DispatchEndpoint("1"); err != nil {
+			t.Fatalf("%s: %v", dispatch, err)
+		}
+	}
+}
+
+func TestDispatcher_Selector(t *testing.T) {
+	instances := make([]*registry.ServiceInstance, 0, 3)
+	for i := range 3 {
+		version := "v1"
+		if i == 2 {
+			version = "v2"
+		}
+
+		instances = append(instances, &registry.ServiceInstance{
+			ID:       fmt.Sprintf("node-%d", i+1),
+			Name:     fmt.Sprintf("node-%d", i+1),
+			Kind:     cluster.Node.String(),
+			Alias:    "node",
+			State:    cluster.Work.String(),
+			Endpoint: endpoint.NewEndpoint("grpc", fmt.Sprintf("127.0.0.1:%d", 8001+i), false).String(),
+			Metadata: map[string]string{"version": version},
+			Routes: []registry.Route{{
+				ID:       1,
+				Stateful: false,
+			}},
+		})
+	}
+
+	canary := func(metadata map[string]string) bool { return metadata["version"] == "v2" }
+
+	for _, dispatch := range []cluster.Dispatch{cluster.Random, cluster.RoundRobin, cluster.WeightRoundRobin, cluster.ConsistentHash, cluster.LeastLoad} {
+		d := dispatcher.NewDispatcher(dispatch)
+		d.ReplaceServices(instances...)
+
+		route, err := d.FindRoute(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range 100 {
+			insID, _, err := route.DispatchEndpoint(fmt.Sprintf("%d", i), canary)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if insID != "node-3" {
+				t.Fatalf("%s: unmatched instance %s is dispatched", dispatch, insID)
+			}
+		}
+
+		// 匹配的实例被过滤时优先忽略过滤器
+		d.SetFilter(func(insID string) bool { return insID != "node-3" })
+
+		if insID, _, err := route.DispatchEndpoint("1", canary); err != nil || insID != "node-3" {
+			t.Fatalf("%s: insID = %s, err = %v", dispatch, insID, err)
+		}
+
+		// 无匹配的实例时忽略选择器
+		if _, _, err = route.DispatchEndpoint("1", func(map[string]string) bool { return false }); err != nil {
+			t.Fatalf("%s: %v", dispatch, err)
+		}
+	}
+}
+
 func BenchmarkDispatcher_WeightRoundRobin(b *testing.B) {
 	var (
 		// 创建测试服务实例
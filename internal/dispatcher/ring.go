@@ -0,0 +1,74 @@
+package dispatcher
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+const defaultVirtualNodes = 160 // 每个实例的虚拟节点数
+
+type ring struct {
+	hashes []uint64                    // 有序的虚拟节点哈希值
+	nodes  map[uint64]*serviceEndpoint // 虚拟节点与服务端点的映射
+}
+
+func newRing(endpoints []*serviceEndpoint) *ring {
+	r := &ring{
+		hashes: make([]uint64, 0, len(endpoints)*defaultVirtualNodes),
+		nodes:  make(map[uint64]*serviceEndpoint, len(endpoints)*defaultVirtualNodes),
+	}
+
+	for _, se := range endpoints {
+		for i := range defaultVirtualNodes {
+			h := hashKey(se.insID + "#" + strconv.Itoa(i))
+
+			if _, ok := r.nodes[h]; ok {
+				continue
+			}
+
+			r.nodes[h] = se
+			r.hashes = append(r.hashes, h)
+		}
+	}
+
+	slices.Sort(r.hashes)
+
+	return r
+}
+
+// 查找分发键所属的服务端点
+// 所属端点被过滤时沿哈希环顺时针查找下一个未被过滤的端点
+func (r *ring) get(key string, filter func(insID string) bool) *serviceEndpoint {
+	if len(r.hashes) == 0 {
+		return nil
+	}
+
+	i, _ := slices.BinarySearch(r.hashes, hashKey(key))
+
+	for n := range len(r.hashes) {
+		se := r.nodes[r.hashes[(i+n)%len(r.hashes)]]
+
+		if filter == nil || filter(se.insID) {
+			return se
+		}
+	}
+
+	return nil
+}
+
+// 计算哈希值
+// 对FNV-1a结果做一次混淆，避免相近的键在哈希环上聚集
+func hashKey(key string) uint64 {
+	f := fnv.New64a()
+	_, _ = f.Write([]byte(key))
+
+	h := f.Sum64()
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+
+	return h
+}
@@ -2,6 +2,8 @@ package dispatcher
 
 import (
 	"math/rand/v2"
+	"slices"
+	"sync"
 	"sync/atomic"
 
 	"github.com/dobyte/due/v2/cluster"
@@ -16,6 +18,8 @@ type Route struct {
 	group      string         // 路由所属组
 	counter    atomic.Uint64  // 轮询计数器
 	dispatcher *Dispatcher    // 分发器
+	once       sync.Once      // 哈希环构建控制
+	ring       *ring          // 哈希环（包含work及busy状态的实例）
 }
 
 func newRoute(dispatcher *Dispatcher, group string, route registry.Route) *Route {
@@ -54,18 +58,85 @@ func (r *Route) Authorized() bool {
 
 // FindEndpoint 查询路由服务端点
 func (r *Route) FindEndpoint(insID ...string) (*endpoint.Endpoint, error) {
-	if len(insID) == 0 || insID[0] == "" {
+	if len(insID) > 0 && insID[0] != "" {
+		return r.directDispatch(insID[0])
+	}
+
+	_, ep, err := r.DispatchEndpoint("")
+
+	return ep, err
+}
+
+// DispatchEndpoint 按分发策略分配路由服务端点，返回所分配的实例ID及端点
+// 分发键仅在一致性哈希分发策略下生效，为空时随机分配
+// 设置了实例过滤器时优先分配未被过滤的实例，所有实例均被过滤时忽略过滤器
+// 指定了元数据选择器时优先分配元数据匹配的实例，无匹配实例时忽略选择器
+func (r *Route) DispatchEndpoint(key string, selector ...func(metadata map[string]string) bool) (string, *endpoint.Endpoint, error) {
+	var (
+		se     *serviceEndpoint
+		filter = r.dispatcher.loadFilter()
+	)
+
+	if len(selector) > 0 && selector[0] != nil {
+		match := func(insID string) bool {
+			return selector[0](r.endpoints5[insID].metadata)
+		}
+
+		if filter != nil {
+			se = r.dispatch(key, func(insID string) bool { return match(insID) && filter(insID) })
+		}
+
+		if se == nil {
+			se = r.dispatch(key, match)
+		}
+	}
+
+	if se == nil && filter != nil {
+		se = r.dispatch(key, filter)
+	}
+
+	if se == nil {
+		se = r.dispatch(key, nil)
+	}
+
+	if se == nil {
+		return "", nil, errors.ErrNotFoundEndpoint
+	}
+
+	return se.insID, se.endpoint, nil
+}
+
+// 按分发策略分配，优先分配work状态的实例，其次为busy状态的实例
+// 一致性哈希分配时work及busy状态的实例处于同一哈希环中，实例在两种状态间切换时分发键的归属保持不变
+func (r *Route) dispatch(key string, filter func(insID string) bool) *serviceEndpoint {
+	if r.dispatcher.dispatch == cluster.ConsistentHash && key != "" {
+		return r.consistentHashDispatch(key, filter)
+	}
+
+	for _, endpoints := range [2][]*serviceEndpoint{r.endpoints1, r.endpoints2} {
+		if len(endpoints) == 0 {
+			continue
+		}
+
+		var se *serviceEndpoint
+
 		switch r.dispatcher.dispatch {
 		case cluster.RoundRobin:
-			return r.roundRobinDispatch()
+			se = r.roundRobinDispatch(endpoints, filter)
 		case cluster.WeightRoundRobin:
-			return r.weightRoundRobinDispatch()
+			se = r.weightRoundRobinDispatch(endpoints, filter)
+		case cluster.LeastLoad:
+			se = r.leastLoadDispatch(endpoints, filter)
 		default:
-			return r.randomDispatch()
+			se = r.randomDispatch(endpoints, filter)
+		}
+
+		if se != nil {
+			return se
 		}
 	}
 
-	return r.directDispatch(insID[0])
+	return nil
 }
 
 // 直接分配
@@ -79,75 +150,85 @@ func (r *Route) directDispatch(insID string) (*endpoint.Endpoint, error) {
 }
 
 // 随机分配
-func (r *Route) randomDispatch() (*endpoint.Endpoint, error) {
-	if n := len(r.endpoints1); n > 0 {
-		return r.endpoints1[rand.IntN(n)].endpoint, nil
-	}
-
-	if n := len(r.endpoints2); n > 0 {
-		return r.endpoints2[rand.IntN(n)].endpoint, nil
-	}
-
-	return nil, errors.ErrNotFoundEndpoint
+func (r *Route) randomDispatch(endpoints []*serviceEndpoint, filter func(insID string) bool) *serviceEndpoint {
+	return pick(endpoints, rand.IntN(len(endpoints)), filter)
 }
 
 // 轮询分配
-func (r *Route) roundRobinDispatch() (*endpoint.Endpoint, error) {
-	if n := len(r.endpoints1); n > 0 {
-		index := int(r.counter.Add(1) % uint64(n))
-
-		return r.endpoints1[index].endpoint, nil
-	}
-
-	if n := len(r.endpoints2); n > 0 {
-		index := int(r.counter.Add(1) % uint64(n))
-
-		return r.endpoints2[index].endpoint, nil
-	}
-
-	return nil, errors.ErrNotFoundEndpoint
+func (r *Route) roundRobinDispatch(endpoints []*serviceEndpoint, filter func(insID string) bool) *serviceEndpoint {
+	return pick(endpoints, int(r.counter.Add(1)%uint64(len(endpoints))), filter)
 }
 
 // 加权轮询分配
-func (r *Route) weightRoundRobinDispatch() (*endpoint.Endpoint, error) {
+func (r *Route) weightRoundRobinDispatch(endpoints []*serviceEndpoint, filter func(insID string) bool) *serviceEndpoint {
 	var (
 		selected    *serviceEndpoint
 		totalWeight int
 	)
 
-	if len(r.endpoints1) > 0 {
-		for i := range r.endpoints1 {
-			se := r.endpoints1[i]
-			se.currWeight += se.weight
+	for _, se := range endpoints {
+		if filter != nil && !filter(se.insID) {
+			continue
+		}
+
+		se.currWeight += se.weight
 
-			totalWeight += se.weight
+		totalWeight += se.weight
 
-			if selected == nil || se.currWeight > selected.currWeight {
-				selected = se
-			}
+		if selected == nil || se.currWeight > selected.currWeight {
+			selected = se
 		}
+	}
 
+	if selected != nil {
 		selected.currWeight -= totalWeight
-
-		return selected.endpoint, nil
 	}
 
-	if len(r.endpoints2) > 0 {
-		for i := range r.endpoints2 {
-			se := r.endpoints2[i]
-			se.currWeight += se.weight
+	return selected
+}
 
-			totalWeight += se.weight
+// 最小负载分配
+func (r *Route) leastLoadDispatch(endpoints []*serviceEndpoint, filter func(insID string) bool) *serviceEndpoint {
+	var (
+		selected *serviceEndpoint
+		minScore float64
+	)
 
-			if selected == nil || se.currWeight > selected.currWeight {
-				selected = se
-			}
+	for _, se := range endpoints {
+		if filter != nil && !filter(se.insID) {
+			continue
 		}
 
-		selected.currWeight -= totalWeight
+		if score := se.load.score(); selected == nil || score < minScore || (score == minScore && se.load.actors.Load() < selected.load.actors.Load()) {
+			selected, minScore = se, score
+		}
+	}
+
+	if selected != nil {
+		selected.load.dispatched.Add(1)
+	}
 
-		return selected.endpoint, nil
+	return selected
+}
+
+// 一致性哈希分配
+func (r *Route) consistentHashDispatch(key string, filter func(insID string) bool) *serviceEndpoint {
+	r.once.Do(func() {
+		r.ring = newRing(slices.Concat(r.endpoints1, r.endpoints2))
+	})
+
+	return r.ring.get(key, filter)
+}
+
+// 从指定位置开始依次选取首个未被过滤的端点
+func pick(endpoints []*serviceEndpoint, start int, filter func(insID string) bool) *serviceEndpoint {
+	for i := range endpoints {
+		se := endpoints[(start+i)%len(endpoints)]
+
+		if filter == nil || filter(se.insID) {
+			return se
+		}
 	}
 
-	return nil, errors.ErrNotFoundEndpoint
+	return nil
 }
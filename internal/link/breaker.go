@@ -0,0 +1,51 @@
+package link
+
+import (
+	"context"
+	"net"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/internal/breaker"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+)
+
+// 新建实例熔断器组
+func newBreakers(kind cluster.Kind, opts cluster.BreakerOptions) *breaker.Group {
+	return breaker.NewGroup(opts, func(insID string, from, to breaker.State, reason string) {
+		metrics.LinkBreakerTransitions.Add(1, kind.String(), to.String())
+
+		if to == breaker.Open {
+			log.Warnf("the %s instance is ejected by circuit breaker, insID: %s reason: %s", kind, insID, reason)
+		} else {
+			log.Infof("the %s instance circuit breaker changed from %s to %s, insID: %s reason: %s", kind, from, to, insID, reason)
+		}
+	})
+}
+
+// 检测错误是否由实例故障引起
+// 调用方主动取消或超时不视为实例故障，业务错误亦不计入
+func isFault(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ctx.Err() == nil
+	}
+
+	switch {
+	case errors.Is(err, errors.ErrConnectionHanged),
+		errors.Is(err, errors.ErrConnectionClosed),
+		errors.Is(err, errors.ErrConnectionNotOpened),
+		errors.Is(err, errors.ErrClientClosed),
+		errors.Is(err, errors.ErrClientShut),
+		errors.Is(err, errors.ErrServerClosed):
+		return true
+	}
+
+	var e net.Error
+
+	return errors.As(err, &e)
+}
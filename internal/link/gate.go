@@ -10,10 +10,12 @@ import (
 	"github.com/dobyte/due/v2/core/buffer"
 	"github.com/dobyte/due/v2/core/endpoint"
 	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/internal/breaker"
 	"github.com/dobyte/due/v2/internal/dispatcher"
 	"github.com/dobyte/due/v2/internal/transporter/gate"
 	"github.com/dobyte/due/v2/locate"
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
 	"github.com/dobyte/due/v2/packet"
 	"github.com/dobyte/due/v2/registry"
 	"github.com/dobyte/due/v2/session"
@@ -26,6 +28,7 @@ type GateLinker struct {
 	sources    sync.Map               // 用户源
 	builder    *gate.Builder          // 构建器
 	dispatcher *dispatcher.Dispatcher // 分发器
+	breakers   *breaker.Group         // 熔断器
 }
 
 func NewGateLinker(ctx context.Context, opts *Options) *GateLinker {
@@ -33,6 +36,7 @@ func NewGateLinker(ctx context.Context, opts *Options) *GateLinker {
 		ctx:        ctx,
 		opts:       opts,
 		dispatcher: dispatcher.NewDispatcher(opts.Dispatch),
+		breakers:   newBreakers(cluster.Gate, opts.Breaker),
 		builder: gate.NewBuilder(&gate.ClientOptions{
 			ID:                opts.ID,
 			Kind:              opts.Kind,
@@ -43,12 +47,19 @@ func NewGateLinker(ctx context.Context, opts *Options) *GateLinker {
 			WriteTimeout:      opts.WriteTimeout,
 			WriteQueueSize:    opts.WriteQueueSize,
 			FaultRecoveryTime: opts.FaultRecoveryTime,
+			BatchWindow:       opts.BatchWindow,
+			BatchSize:         opts.BatchSize,
 		}),
 	}
 
 	return l
 }
 
+// Close 关闭链接器，发送尚未发送的批量推送消息
+func (l *GateLinker) Close() {
+	l.builder.Close()
+}
+
 // HasGate 检测是否存在某个网关
 func (l *GateLinker) HasGate(gid string) bool {
 	_, err := l.dispatcher.FindEndpoint(gid)
@@ -218,7 +229,7 @@ func (l *GateLinker) Stat(ctx context.Context, kind session.Kind) (total int64,
 
 	l.dispatcher.VisitEndpoints(func(_ string, ep *endpoint.Endpoint) bool {
 		eg.Go(func() error {
-			client, err := l.builder.Build(ep.Address())
+			client, err := l.builder.Build(ep)
 			if err != nil {
 				return err
 			}
@@ -244,6 +255,16 @@ func (l *GateLinker) Stat(ctx context.Context, kind session.Kind) (total int64,
 	return total, err
 }
 
+// StatGate 统计指定网关的会话总数
+func (l *GateLinker) StatGate(ctx context.Context, gid string, kind session.Kind) (int64, error) {
+	client, err := l.doBuildClient(gid)
+	if err != nil {
+		return 0, err
+	}
+
+	return client.Stat(ctx, kind)
+}
+
 // IsOnline 检测是否在线
 func (l *GateLinker) IsOnline(ctx context.Context, args *IsOnlineArgs) (bool, error) {
 	switch args.Kind {
@@ -496,7 +517,7 @@ func (l *GateLinker) Broadcast(ctx context.Context, args *BroadcastArgs) (int64,
 
 	if n == 1 {
 		for _, ep := range endpoints {
-			return l.doBroadcast(ctx, ep.Address(), args.Kind, args.Disconnect, message, args.Ack)
+			return l.doBroadcast(ctx, ep, args.Kind, args.Disconnect, message, args.Ack)
 		}
 
 		return 0, nil
@@ -509,10 +530,8 @@ func (l *GateLinker) Broadcast(ctx context.Context, args *BroadcastArgs) (int64,
 		message.Delay(int32(n))
 
 		for _, ep := range endpoints {
-			addr := ep.Address()
-
 			eg.Go(func() error {
-				if v, err := l.doBroadcast(ctx, addr, args.Kind, args.Disconnect, message, args.Ack); err != nil {
+				if v, err := l.doBroadcast(ctx, ep, args.Kind, args.Disconnect, message, args.Ack); err != nil {
 					return err
 				} else {
 					atomic.AddInt64(&total, v)
@@ -531,8 +550,8 @@ func (l *GateLinker) Broadcast(ctx context.Context, args *BroadcastArgs) (int64,
 }
 
 // 执行广播消息
-func (l *GateLinker) doBroadcast(ctx context.Context, addr string, kind session.Kind, disconnect bool, message buffer.Buffer, ack bool) (int64, error) {
-	if client, err := l.builder.Build(addr); err != nil {
+func (l *GateLinker) doBroadcast(ctx context.Context, ep *endpoint.Endpoint, kind session.Kind, disconnect bool, message buffer.Buffer, ack bool) (int64, error) {
+	if client, err := l.builder.Build(ep); err != nil {
 		message.Release()
 
 		return 0, err
@@ -559,7 +578,7 @@ func (l *GateLinker) Publish(ctx context.Context, args *PublishArgs) (int64, err
 
 	if n == 1 {
 		for _, ep := range endpoints {
-			return l.doPublish(ctx, ep.Address(), args.Channel, args.Disconnect, message, args.Ack)
+			return l.doPublish(ctx, ep, args.Channel, args.Disconnect, message, args.Ack)
 		}
 
 		return 0, nil
@@ -572,10 +591,8 @@ func (l *GateLinker) Publish(ctx context.Context, args *PublishArgs) (int64, err
 		message.Delay(int32(n))
 
 		for _, ep := range endpoints {
-			addr := ep.Address()
-
 			eg.Go(func() error {
-				if v, err := l.doPublish(ctx, addr, args.Channel, args.Disconnect, message, args.Ack); err != nil {
+				if v, err := l.doPublish(ctx, ep, args.Channel, args.Disconnect, message, args.Ack); err != nil {
 					return err
 				} else {
 					atomic.AddInt64(&total, v)
@@ -594,8 +611,8 @@ func (l *GateLinker) Publish(ctx context.Context, args *PublishArgs) (int64, err
 }
 
 // 执行发布频道消息
-func (l *GateLinker) doPublish(ctx context.Context, addr string, channel string, disconnect bool, message buffer.Buffer, ack bool) (int64, error) {
-	if client, err := l.builder.Build(addr); err != nil {
+func (l *GateLinker) doPublish(ctx context.Context, ep *endpoint.Endpoint, channel string, disconnect bool, message buffer.Buffer, ack bool) (int64, error) {
+	if client, err := l.builder.Build(ep); err != nil {
 		message.Release()
 
 		return 0, err
@@ -737,18 +754,37 @@ func (l *GateLinker) doRPC(ctx context.Context, uid int64, successHandler func(c
 
 		prev = gid
 
+		if !l.breakers.Allow(gid) {
+			if len(failedHandler) > 0 {
+				failedHandler[0](i+1, total)
+			}
+			return nil, errors.ErrCircuitOpen
+		}
+
+		start := time.Now()
+
 		if client, err = l.doBuildClient(gid); err != nil {
+			l.breakers.Done(gid, isFault(ctx, err), time.Since(start))
+
 			if len(failedHandler) > 0 {
 				failedHandler[0](i+1, total)
 			}
 			return nil, err
 		}
 
-		if continued, reply, err = successHandler(client, i+1, total); !continued {
+		continued, reply, err = successHandler(client, i+1, total)
+
+		l.breakers.Done(gid, isFault(ctx, err), time.Since(start))
+
+		if !continued {
 			break
 		}
 
 		l.sources.Delete(uid)
+
+		if i+1 < total {
+			metrics.LinkRPCRetries.Add(1, cluster.Gate.String())
+		}
 	}
 
 	return reply, err
@@ -765,7 +801,7 @@ func (l *GateLinker) doBuildClient(gid string) (*gate.Client, error) {
 		return nil, err
 	}
 
-	return l.builder.Build(ep.Address())
+	return l.builder.Build(ep)
 }
 
 // PackMessage 打包消息
@@ -874,6 +910,8 @@ func (l *GateLinker) WatchClusterInstance() {
 				}
 
 				l.dispatcher.ReplaceServices(services...)
+
+				l.breakers.Retain(l.HasGate)
 			}
 		}
 	}()
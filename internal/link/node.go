@@ -1,18 +1,23 @@
 package link
 
 import (
+	"bytes"
 	"context"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/core/buffer"
 	"github.com/dobyte/due/v2/core/endpoint"
 	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/internal/breaker"
 	"github.com/dobyte/due/v2/internal/dispatcher"
 	"github.com/dobyte/due/v2/internal/transporter/node"
 	"github.com/dobyte/due/v2/locate"
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
 	"github.com/dobyte/due/v2/packet"
 	"github.com/dobyte/due/v2/registry"
 	"golang.org/x/sync/errgroup"
@@ -23,16 +28,20 @@ type NodeLinker struct {
 	opts       *Options                    // 参数项
 	builder    *node.Builder               // 构建器
 	dispatcher *dispatcher.Dispatcher      // 分发器
+	breakers   *breaker.Group              // 熔断器
+	routing    atomic.Pointer[routing]     // 灰度路由
 	rw         sync.RWMutex                // 锁
 	sources    map[int64]map[string]string // 用户来源节点
+	actors     sync.Map                    // Actor所在节点
 }
 
 func NewNodeLinker(ctx context.Context, opts *Options) *NodeLinker {
-	return &NodeLinker{
+	l := &NodeLinker{
 		ctx:        ctx,
 		opts:       opts,
 		sources:    make(map[int64]map[string]string),
 		dispatcher: dispatcher.NewDispatcher(opts.Dispatch),
+		breakers:   newBreakers(cluster.Node, opts.Breaker),
 		builder: node.NewBuilder(&node.ClientOptions{
 			ID:                opts.ID,
 			Kind:              opts.Kind,
@@ -45,6 +54,19 @@ func NewNodeLinker(ctx context.Context, opts *Options) *NodeLinker {
 			FaultRecoveryTime: opts.FaultRecoveryTime,
 		}),
 	}
+
+	if l.breakers != nil {
+		l.dispatcher.SetFilter(l.breakers.Ready)
+	}
+
+	l.routing.Store(newRouting(opts.RoutingRules, opts.RoutingTagger))
+
+	return l
+}
+
+// SetRoutingRules 设置灰度路由规则，规则为空时清除灰度路由
+func (l *NodeLinker) SetRoutingRules(rules ...cluster.RoutingRule) {
+	l.routing.Store(newRouting(rules, l.opts.RoutingTagger))
 }
 
 // HasNode 检测是否存在某个节点
@@ -53,6 +75,12 @@ func (l *NodeLinker) HasNode(nid string) bool {
 	return err == nil
 }
 
+// HasRoute 检测是否存在某个路由
+func (l *NodeLinker) HasRoute(route int32) bool {
+	_, err := l.dispatcher.FindRoute(route)
+	return err == nil
+}
+
 // AskNode 检测用户是否在给定的节点上
 func (l *NodeLinker) AskNode(ctx context.Context, uid int64, name, nid string) (string, bool, error) {
 	if l.opts.Locator == nil {
@@ -202,7 +230,12 @@ func (l *NodeLinker) Deliver(ctx context.Context, args *DeliverArgs) error {
 			return client.Deliver(ctx, args.CID, args.UID, buf)
 		}
 	} else {
-		if _, err = l.doRPC(ctx, args.Route, args.UID, func(ctx context.Context, client *node.Client) (bool, any, error) {
+		key := args.Key
+		if key == "" && args.UID != 0 {
+			key = strconv.FormatInt(args.UID, 10)
+		}
+
+		if _, err = l.doRPC(ctx, args.Route, args.UID, key, func(ctx context.Context, client *node.Client) (bool, any, error) {
 			isDeliver = true
 
 			return false, nil, client.Deliver(ctx, args.CID, args.UID, buf)
@@ -220,6 +253,163 @@ func (l *NodeLinker) Deliver(ctx context.Context, args *DeliverArgs) error {
 	}
 }
 
+// DeliverActor 投递消息给Actor处理
+func (l *NodeLinker) DeliverActor(ctx context.Context, args *DeliverActorArgs) error {
+	var (
+		err error
+		buf buffer.Buffer
+		nid = args.NID
+	)
+
+	switch b := args.Buffer.(type) {
+	case []byte:
+		buf = buffer.NewNocopyBuffer(b)
+	case buffer.Buffer:
+		buf = b
+	case *Message:
+		if buf, err = l.PackMessage(b, false); err != nil {
+			return err
+		}
+	default:
+		return errors.ErrInvalidMessage
+	}
+
+	if nid != "" {
+		_, err = l.doDeliverActor(ctx, nid, args, buf)
+		return err
+	}
+
+	nid, cached, err := l.doLocateActor(ctx, args.PID)
+	if err != nil {
+		buf.Release()
+		return err
+	}
+
+	if !cached {
+		_, err = l.doDeliverActor(ctx, nid, args, buf)
+		return err
+	}
+
+	// 缓存的Actor位置可能已失效，保留消息副本以便清除缓存后重新定位并重试一次
+	data := bytes.Clone(buf.Bytes())
+	buf.Release()
+
+	stale, err := l.doDeliverActor(ctx, nid, args, buffer.NewNocopyBuffer(data))
+	if !stale {
+		return err
+	}
+
+	if nid, _, err = l.doLocateActor(ctx, args.PID); err != nil {
+		return err
+	}
+
+	_, err = l.doDeliverActor(ctx, nid, args, buffer.NewNocopyBuffer(data))
+
+	return err
+}
+
+// 投递消息给指定节点上的Actor，节点或Actor不存在时清除Actor位置缓存并返回stale为true
+func (l *NodeLinker) doDeliverActor(ctx context.Context, nid string, args *DeliverActorArgs, buf buffer.Buffer) (stale bool, err error) {
+	client, err := l.doBuildClient(nid)
+	if err != nil {
+		buf.Release()
+		return l.actors.CompareAndDelete(args.PID, nid), err
+	}
+
+	if err = client.DeliverActor(ctx, args.UID, args.Src, args.PID, buf); errors.Is(err, errors.ErrNotFoundActor) {
+		return l.actors.CompareAndDelete(args.PID, nid), err
+	}
+
+	return false, err
+}
+
+// LocateActor 定位Actor所在节点
+func (l *NodeLinker) LocateActor(ctx context.Context, pid string) (string, error) {
+	nid, _, err := l.doLocateActor(ctx, pid)
+	return nid, err
+}
+
+// 定位Actor所在节点，cached标识是否命中本地缓存
+func (l *NodeLinker) doLocateActor(ctx context.Context, pid string) (nid string, cached bool, err error) {
+	if v, ok := l.actors.Load(pid); ok {
+		return v.(string), true, nil
+	}
+
+	locator, err := l.doLoadActorLocator()
+	if err != nil {
+		return "", false, err
+	}
+
+	if nid, err = locator.LocateActor(ctx, pid); err != nil {
+		return "", false, err
+	}
+
+	if nid == "" {
+		return "", false, errors.ErrNotFoundActorLocation
+	}
+
+	l.actors.Store(pid, nid)
+
+	return nid, false, nil
+}
+
+// BindActor 绑定Actor所在节点
+func (l *NodeLinker) BindActor(ctx context.Context, pid, nid string) error {
+	locator, err := l.doLoadActorLocator()
+	if err != nil {
+		return err
+	}
+
+	if err = locator.BindActor(ctx, pid, nid); err != nil {
+		return err
+	}
+
+	l.actors.Store(pid, nid)
+
+	return nil
+}
+
+// UnbindActor 解绑Actor所在节点
+func (l *NodeLinker) UnbindActor(ctx context.Context, pid, nid string) error {
+	locator, err := l.doLoadActorLocator()
+	if err != nil {
+		return err
+	}
+
+	if err = locator.UnbindActor(ctx, pid, nid); err != nil {
+		return err
+	}
+
+	l.actors.CompareAndDelete(pid, nid)
+
+	return nil
+}
+
+// 清除已下线节点上的Actor位置缓存
+func (l *NodeLinker) doRetainActors() {
+	l.actors.Range(func(pid, nid any) bool {
+		if !l.HasNode(nid.(string)) {
+			l.actors.CompareAndDelete(pid, nid)
+		}
+
+		return true
+	})
+}
+
+// 加载Actor定位器
+func (l *NodeLinker) doLoadActorLocator() (locate.ActorLocator, error) {
+	if l.opts.Locator == nil {
+		return nil, errors.ErrNotFoundLocator
+	}
+
+	locator, ok := l.opts.Locator.(locate.ActorLocator)
+	if !ok {
+		return nil, errors.ErrNotFoundLocator
+	}
+
+	return locator, nil
+}
+
 // Trigger 触发事件
 func (l *NodeLinker) Trigger(ctx context.Context, args *TriggerArgs) error {
 	event, err := l.dispatcher.FindEvent(int(args.Event))
@@ -231,7 +421,7 @@ func (l *NodeLinker) Trigger(ctx context.Context, args *TriggerArgs) error {
 
 	event.VisitEndpoints(func(_ string, ep *endpoint.Endpoint) bool {
 		eg.Go(func() error {
-			client, err := l.builder.Build(ep.Address())
+			client, err := l.builder.Build(ep)
 			if err != nil {
 				return err
 			}
@@ -270,7 +460,7 @@ func (l *NodeLinker) SetState(ctx context.Context, nid string, state cluster.Sta
 }
 
 // 执行节点RPC调用
-func (l *NodeLinker) doRPC(ctx context.Context, routeID int32, uid int64, fn func(ctx context.Context, client *node.Client) (bool, any, error)) (any, error) {
+func (l *NodeLinker) doRPC(ctx context.Context, routeID int32, uid int64, key string, fn func(ctx context.Context, client *node.Client) (bool, any, error)) (any, error) {
 	var (
 		err       error
 		nid       string
@@ -294,7 +484,7 @@ func (l *NodeLinker) doRPC(ctx context.Context, routeID int32, uid int64, fn fun
 		return nil, errors.ErrIllegalRequest
 	}
 
-	for range 2 {
+	for i := range 2 {
 		if route.Stateful() {
 			if nid, err = l.LocateNode(ctx, uid, route.Group()); err != nil {
 				return nil, err
@@ -307,18 +497,39 @@ func (l *NodeLinker) doRPC(ctx context.Context, routeID int32, uid int64, fn fun
 			prev = nid
 		}
 
-		if ep, err = route.FindEndpoint(nid); err != nil {
+		if route.Stateful() {
+			ep, err = route.FindEndpoint(nid)
+		} else {
+			nid, ep, err = route.DispatchEndpoint(key, l.routing.Load().selector(uid))
+		}
+		if err != nil {
 			return nil, err
 		}
 
-		if client, err = l.builder.Build(ep.Address()); err != nil {
+		if !l.breakers.Allow(nid) {
+			return nil, errors.ErrCircuitOpen
+		}
+
+		start := time.Now()
+
+		if client, err = l.builder.Build(ep); err != nil {
+			l.breakers.Done(nid, isFault(ctx, err), time.Since(start))
 			return nil, err
 		}
 
-		if continued, reply, err = fn(ctx, client); continued {
+		continued, reply, err = fn(ctx, client)
+
+		l.breakers.Done(nid, isFault(ctx, err), time.Since(start))
+
+		if continued {
 			if route.Stateful() {
 				l.doDeleteSource(uid, route.Group(), prev)
 			}
+
+			if i == 0 {
+				metrics.LinkRPCRetries.Add(1, cluster.Node.String())
+			}
+
 			continue
 		}
 
@@ -339,7 +550,7 @@ func (l *NodeLinker) doBuildClient(nid string) (*node.Client, error) {
 		return nil, err
 	}
 
-	return l.builder.Build(ep.Address())
+	return l.builder.Build(ep)
 }
 
 // 打包消息
@@ -540,6 +751,10 @@ func (l *NodeLinker) WatchClusterInstance() {
 				}
 
 				l.dispatcher.ReplaceServices(services...)
+
+				l.breakers.Retain(l.HasNode)
+
+				l.doRetainActors()
 			}
 		}
 	}()
@@ -11,20 +11,25 @@ import (
 )
 
 type Options struct {
-	ID                string            // 实例ID
-	Kind              cluster.Kind      // 实例类型
-	Codec             encoding.Codec    // 编解码器
-	Locator           locate.Locator    // 定位器
-	Registry          registry.Registry // 注册器
-	Encryptor         crypto.Encryptor  // 加密器
-	Dispatch          cluster.Dispatch  // 无状态路由消息分发策略
-	ConnNum           int               // 连接数
-	CallTimeout       time.Duration     // 调用超时时间
-	DialTimeout       time.Duration     // 拨号超时时间
-	DialRetryTimes    int               // 拨号重试次数
-	WriteTimeout      time.Duration     // 写入超时时间
-	WriteQueueSize    int32             // 写入队列大小
-	FaultRecoveryTime time.Duration     // 故障恢复时间
-	WaitHandler       func()            // 等待处理
-	DoneHandler       func()            // 完成处理
+	ID                string                 // 实例ID
+	Kind              cluster.Kind           // 实例类型
+	Codec             encoding.Codec         // 编解码器
+	Locator           locate.Locator         // 定位器
+	Registry          registry.Registry      // 注册器
+	Encryptor         crypto.Encryptor       // 加密器
+	Dispatch          cluster.Dispatch       // 无状态路由消息分发策略
+	ConnNum           int                    // 连接数
+	CallTimeout       time.Duration          // 调用超时时间
+	DialTimeout       time.Duration          // 拨号超时时间
+	DialRetryTimes    int                    // 拨号重试次数
+	WriteTimeout      time.Duration          // 写入超时时间
+	WriteQueueSize    int32                  // 写入队列大小
+	FaultRecoveryTime time.Duration          // 故障恢复时间
+	Breaker           cluster.BreakerOptions // 熔断配置
+	BatchWindow       time.Duration          // 批量推送合并窗口，为0时不开启批量推送
+	BatchSize         int                    // 批量推送合并字节数上限
+	RoutingRules      []cluster.RoutingRule  // 灰度路由规则
+	RoutingTagger     cluster.RoutingTagger  // 灰度路由用户标签获取器
+	WaitHandler       func()                 // 等待处理
+	DoneHandler       func()                 // 完成处理
 }
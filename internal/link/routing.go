@@ -0,0 +1,137 @@
+package link
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/dobyte/due/v2/cluster"
+)
+
+const routingBuckets = 10000 // 按用户分流的桶数，百分比精度为0.01%
+
+// 灰度路由
+type routing struct {
+	rules    []*routingRule               // 规则列表，按顺序匹配
+	baseline func(map[string]string) bool // 未命中规则的用户所使用的选择器
+	tagger   cluster.RoutingTagger        // 用户标签获取器
+}
+
+type routingRule struct {
+	metadata map[string]string            // 目标实例元数据
+	bucket   uint64                       // 分流桶上限
+	uids     map[int64]struct{}           // 指定用户
+	tags     map[string]struct{}          // 标签白名单
+	selector func(map[string]string) bool // 目标实例选择器
+}
+
+func newRouting(rules []cluster.RoutingRule, tagger cluster.RoutingTagger) *routing {
+	r := &routing{tagger: tagger}
+
+	for _, rule := range rules {
+		if len(rule.Metadata) == 0 {
+			continue
+		}
+
+		item := &routingRule{
+			metadata: rule.Metadata,
+			bucket:   uint64(min(max(rule.Percent, 0), 100) * routingBuckets / 100),
+			uids:     make(map[int64]struct{}, len(rule.UIDs)),
+			tags:     make(map[string]struct{}, len(rule.Tags)),
+		}
+		item.selector = item.match
+
+		for _, uid := range rule.UIDs {
+			item.uids[uid] = struct{}{}
+		}
+
+		for _, tag := range rule.Tags {
+			item.tags[tag] = struct{}{}
+		}
+
+		r.rules = append(r.rules, item)
+	}
+
+	if len(r.rules) == 0 {
+		return nil
+	}
+
+	r.baseline = func(metadata map[string]string) bool {
+		for _, rule := range r.rules {
+			if rule.match(metadata) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return r
+}
+
+// 获取用户的实例选择器
+// 按顺序匹配规则，命中时选择规则的目标实例，均未命中时避开所有规则的目标实例；未授权用户始终视为未命中
+func (r *routing) selector(uid int64) func(map[string]string) bool {
+	if r == nil {
+		return nil
+	}
+
+	if uid == 0 {
+		return r.baseline
+	}
+
+	var (
+		tags   map[string]struct{}
+		loaded bool
+	)
+
+	for _, rule := range r.rules {
+		if _, ok := rule.uids[uid]; ok {
+			return rule.selector
+		}
+
+		if len(rule.tags) > 0 && r.tagger != nil {
+			if !loaded {
+				tags, loaded = make(map[string]struct{}), true
+
+				for _, tag := range r.tagger(uid) {
+					tags[tag] = struct{}{}
+				}
+			}
+
+			for tag := range tags {
+				if _, ok := rule.tags[tag]; ok {
+					return rule.selector
+				}
+			}
+		}
+
+		if rule.bucket > 0 && bucket(uid) < rule.bucket {
+			return rule.selector
+		}
+	}
+
+	return r.baseline
+}
+
+// 检测实例元数据是否匹配规则
+func (r *routingRule) match(metadata map[string]string) bool {
+	for key, value := range r.metadata {
+		if v, ok := metadata[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 计算用户所在的分流桶，同一用户在所有网关上的分流结果一致
+func bucket(uid int64) uint64 {
+	var b [8]byte
+
+	binary.BigEndian.PutUint64(b[:], uint64(uid))
+
+	h := fnv.New64a()
+	_, _ = h.Write(b[:])
+
+	return h.Sum64() % routingBuckets
+}
@@ -0,0 +1,52 @@
+package link
+
+import (
+	"testing"
+
+	"github.com/dobyte/due/v2/cluster"
+)
+
+func TestRouting_Selector(t *testing.T) {
+	var (
+		v1 = map[string]string{"version": "v1"}
+		v2 = map[string]string{"version": "v2"}
+	)
+
+	r := newRouting([]cluster.RoutingRule{{
+		Metadata: v2,
+		Percent:  10,
+		UIDs:     []int64{1},
+		Tags:     []string{"tester"},
+	}}, func(uid int64) []string {
+		if uid == 2 {
+			return []string{"tester"}
+		}
+
+		return nil
+	})
+
+	for _, uid := range []int64{1, 2} {
+		if sel := r.selector(uid); !sel(v2) || sel(v1) {
+			t.Fatalf("uid %d should be routed to v2", uid)
+		}
+	}
+
+	if sel := r.selector(0); sel(v2) || !sel(v1) {
+		t.Fatal("unauthorized user should be routed to baseline")
+	}
+
+	hits := 0
+	for uid := int64(100); uid < 10100; uid++ {
+		if r.selector(uid)(v2) {
+			hits++
+		}
+	}
+
+	if hits < 800 || hits > 1200 {
+		t.Fatalf("unexpected canary hits: %d", hits)
+	}
+
+	if newRouting(nil, nil).selector(1) != nil {
+		t.Fatal("empty routing should not select")
+	}
+}
@@ -21,10 +21,19 @@ type DeliverArgs struct {
 	NID    string // 接收节点。存在接收节点时，消息会直接投递给接收节点；不存在接收节点时，系统定位用户所在节点，然后投递。
 	CID    int64  // 连接ID
 	UID    int64  // 用户ID
+	Key    string // 分发键。一致性哈希分发策略下，无状态路由按分发键分配节点；为空时使用用户ID
 	Route  int32  // 消息路由
 	Buffer any    // 投递消息
 }
 
+type DeliverActorArgs struct {
+	NID    string // 接收节点。存在接收节点时，消息会直接投递给接收节点；不存在接收节点时，系统通过Actor目录定位Actor所在节点，然后投递。
+	UID    int64  // 用户ID
+	Src    string // 来源Actor
+	PID    string // 接收Actor
+	Buffer any    // 投递消息
+}
+
 type TriggerArgs struct {
 	Event cluster.Event // 事件
 	CID   int64         // 连接ID
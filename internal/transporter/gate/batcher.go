@@ -0,0 +1,146 @@
+package gate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/internal/transporter/internal/client"
+	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+)
+
+const batchConnIndex = 0 // 批量推送使用的连接索引
+
+const (
+	flushByWindow = "window" // 合并窗口到期触发发送
+	flushByClose  = "close"  // 客户端关闭触发发送
+)
+
+// 批量推送器
+// 将窗口期内的推送类请求合并为一个批量包发送，所有推送类请求（包括需要回执的请求）均经由同一连接按序写出，以保证同一目标的消息顺序
+// 合并字节数达到上限时由当前请求同步发送，发送错误返回给调用方；合并窗口到期或客户端关闭时异步发送，发送错误仅记录日志并计入metrics.LinkBatchFlushFailures指标
+type batcher struct {
+	mu     sync.Mutex
+	cli    *client.Client
+	window time.Duration          // 合并窗口
+	size   int                    // 合并字节数上限
+	bytes  int                    // 当前批次字节数
+	items  []*buffer.NocopyBuffer // 当前批次请求
+	timer  *time.Timer            // 窗口定时器
+	closed bool                   // 是否已关闭
+}
+
+func newBatcher(cli *client.Client, window time.Duration, size int) *batcher {
+	return &batcher{
+		cli:    cli,
+		window: window,
+		size:   size,
+	}
+}
+
+// 添加请求到当前批次，关闭后直接发送
+func (b *batcher) add(item *buffer.NocopyBuffer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return b.cli.Send(context.Background(), item, batchConnIndex)
+	}
+
+	b.items = append(b.items, item)
+	b.bytes += item.Len()
+
+	if (b.size > 0 && b.bytes >= b.size) || len(b.items) >= protocol.MaxBatchItems {
+		return b.doFlush()
+	}
+
+	if len(b.items) == 1 {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.window, b.flush)
+		} else {
+			b.timer.Reset(b.window)
+		}
+	}
+
+	return nil
+}
+
+// 调用需要回执的请求
+// 先发送当前批次，再经由批量推送的连接写出请求，以保证与之前的推送保持顺序
+func (b *batcher) call(ctx context.Context, seq uint64, buf *buffer.NocopyBuffer) (buffer.Buffer, error) {
+	b.mu.Lock()
+
+	if err := b.doFlush(); err != nil {
+		b.mu.Unlock()
+		buf.Release()
+		return nil, err
+	}
+
+	wait, err := b.cli.Go(seq, buf, batchConnIndex)
+
+	b.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return wait(ctx)
+}
+
+// 窗口到期后发送当前批次
+func (b *batcher) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.doAsyncFlush(flushByWindow)
+}
+
+// 关闭批量推送器，发送当前批次，之后的请求不再合并
+func (b *batcher) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.closed = true
+
+	b.doAsyncFlush(flushByClose)
+}
+
+// 执行异步发送，发送错误无法返回给调用方
+func (b *batcher) doAsyncFlush(trigger string) {
+	if err := b.doFlush(); err != nil {
+		metrics.LinkBatchFlushFailures.Add(1, trigger)
+		log.Warnf("flush batch push failed, trigger: %s err: %v", trigger, err)
+	}
+}
+
+// 发送当前批次，仅有一个请求时无需打包
+func (b *batcher) doFlush() error {
+	if len(b.items) == 0 {
+		return nil
+	}
+
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+
+	var buf *buffer.NocopyBuffer
+
+	if len(b.items) == 1 {
+		buf = b.items[0]
+	} else {
+		buf = protocol.EncodeBatchReq(0, b.items...)
+	}
+
+	clear(b.items)
+	b.items = b.items[:0]
+	b.bytes = 0
+
+	return b.cli.Send(context.Background(), buf, batchConnIndex)
+}
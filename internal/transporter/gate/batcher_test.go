@@ -0,0 +1,155 @@
+package gate_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/internal/transporter/gate"
+	"github.com/dobyte/due/v2/session"
+	"github.com/dobyte/due/v2/utils/xuuid"
+)
+
+func TestClient_Batch(t *testing.T) {
+	const total = 200
+
+	p := &recorder{done: make(chan struct{})}
+	p.total = total
+
+	server, err := gate.NewServer(p, &gate.ServerOptions{Addr: "inproc://"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	go server.Start()
+
+	builder := gate.NewBuilder(&gate.ClientOptions{
+		ID:          xuuid.UUID(),
+		Kind:        cluster.Node,
+		ConnNum:     3,
+		DialTimeout: time.Second,
+		BatchWindow: 5 * time.Millisecond,
+		BatchSize:   1024,
+	})
+
+	client, err := builder.Build(server.Endpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range total {
+		message := buffer.NewNocopyBuffer([]byte(strconv.Itoa(i)))
+
+		// 需要回执的推送与广播需与合并发送的推送保持顺序
+		switch i % 5 {
+		case 0, 1:
+			err = client.Push(context.Background(), session.User, 1, false, message, false)
+		case 2:
+			_, err = client.Multicast(context.Background(), session.User, []int64{1, 2}, false, message, false)
+		case 3:
+			err = client.Push(context.Background(), session.User, 1, false, message, true)
+		case 4:
+			_, err = client.Broadcast(context.Background(), session.User, false, message, i%2 == 0)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-p.done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("wait for batch push timeout")
+	}
+
+	for i, message := range p.messages {
+		if message != strconv.Itoa(i) {
+			t.Fatalf("message %d out of order: %s", i, message)
+		}
+	}
+}
+
+func TestClient_BatchClose(t *testing.T) {
+	const total = 3
+
+	p := &recorder{done: make(chan struct{})}
+	p.total = total
+
+	server, err := gate.NewServer(p, &gate.ServerOptions{Addr: "inproc://"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	go server.Start()
+
+	builder := gate.NewBuilder(&gate.ClientOptions{
+		ID:          xuuid.UUID(),
+		Kind:        cluster.Node,
+		ConnNum:        1,
+		DialTimeout:    time.Second,
+		DialRetryTimes: 10,
+		BatchWindow:    time.Hour,
+		BatchSize:      1024,
+	})
+
+	client, err := builder.Build(server.Endpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range total {
+		if err = client.Push(context.Background(), session.User, 1, false, buffer.NewNocopyBuffer([]byte(strconv.Itoa(i))), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 关闭时发送窗口期内尚未发送的消息
+	builder.Close()
+
+	select {
+	case <-p.done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("wait for batch push timeout")
+	}
+}
+
+type recorder struct {
+	gate.Provider
+	mu       sync.Mutex
+	total    int
+	messages []string
+	done     chan struct{}
+}
+
+func (r *recorder) Push(ctx context.Context, kind session.Kind, target int64, disconnect bool, message []byte) error {
+	r.record(message)
+	return nil
+}
+
+func (r *recorder) Multicast(ctx context.Context, kind session.Kind, targets []int64, disconnect bool, message []byte) (int64, error) {
+	r.record(message)
+	return int64(len(targets)), nil
+}
+
+func (r *recorder) Broadcast(ctx context.Context, kind session.Kind, disconnect bool, message []byte) (int64, error) {
+	r.record(message)
+	return 1, nil
+}
+
+func (r *recorder) record(message []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = append(r.messages, string(message))
+
+	if len(r.messages) == r.total {
+		close(r.done)
+	}
+}
@@ -3,7 +3,9 @@ package gate
 import (
 	"sync"
 
+	"github.com/dobyte/due/v2/core/endpoint"
 	"github.com/dobyte/due/v2/internal/transporter/internal/client"
+	"github.com/dobyte/due/v2/internal/transporter/internal/socket"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -22,25 +24,34 @@ func NewBuilder(opts *ClientOptions) *Builder {
 }
 
 // Build 构建客户端
-func (b *Builder) Build(addr string) (*Client, error) {
-	if cli, ok := b.clients.Load(addr); ok {
+// 根据端点协议选择TCP、Unix域套接字或进程内传输
+func (b *Builder) Build(ep *endpoint.Endpoint) (*Client, error) {
+	network, err := socket.Network(ep)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := ep.Address()
+	key := network + "://" + addr
+
+	if cli, ok := b.clients.Load(key); ok {
 		return cli.(*Client), nil
 	}
 
-	cli, err, _ := b.sfg.Do(addr, func() (any, error) {
-		if cli, ok := b.clients.Load(addr); ok {
+	cli, err, _ := b.sfg.Do(key, func() (any, error) {
+		if cli, ok := b.clients.Load(key); ok {
 			return cli.(*Client), nil
 		}
 
-		c := client.NewClient(addr, b.opts)
+		c := client.NewClient(network, addr, b.opts)
 
 		if err := c.Establish(); err != nil {
 			return nil, err
 		}
 
-		cli := NewClient(c)
+		cli := NewClient(c, b.opts)
 
-		b.clients.Store(addr, cli)
+		b.clients.Store(key, cli)
 
 		return cli, nil
 	})
@@ -50,3 +61,11 @@ func (b *Builder) Build(addr string) (*Client, error) {
 
 	return cli.(*Client), nil
 }
+
+// Close 关闭所有客户端
+func (b *Builder) Close() {
+	b.clients.Range(func(_, cli any) bool {
+		cli.(*Client).Close()
+		return true
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/core/endpoint"
 	"github.com/dobyte/due/v2/internal/transporter/gate"
 	"github.com/dobyte/due/v2/session"
 	"github.com/dobyte/due/v2/utils/xuuid"
@@ -24,7 +25,7 @@ func TestBuilder(t *testing.T) {
 		FaultRecoveryTime: 3 * time.Second,
 	})
 
-	client, err := builder.Build("127.0.0.1:49899")
+	client, err := builder.Build(endpoint.NewEndpoint("drpc", "127.0.0.1:49899", false))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -58,7 +59,7 @@ func TestBuilder_Fault(t *testing.T) {
 	})
 
 	for i := range 3 {
-		if _, err := builder.Build("127.0.0.1:49899"); err != nil {
+		if _, err := builder.Build(endpoint.NewEndpoint("drpc", "127.0.0.1:49899", false)); err != nil {
 			t.Log(err)
 			time.Sleep(time.Duration(i+1) * time.Second)
 		} else {
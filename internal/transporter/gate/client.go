@@ -14,14 +14,19 @@ import (
 )
 
 type Client struct {
-	seq uint64
-	cli *client.Client
+	seq     uint64
+	cli     *client.Client
+	batcher *batcher
 }
 
-func NewClient(cli *client.Client) *Client {
-	return &Client{
-		cli: cli,
+func NewClient(cli *client.Client, opts *ClientOptions) *Client {
+	c := &Client{cli: cli}
+
+	if opts.BatchWindow > 0 {
+		c.batcher = newBatcher(cli, opts.BatchWindow, opts.BatchSize)
 	}
+
+	return c
 }
 
 // Bind 绑定用户与连接
@@ -139,12 +144,13 @@ func (c *Client) Disconnect(ctx context.Context, kind session.Kind, target int64
 }
 
 // Push 推送消息
+// 开启批量推送时，无需回执的推送将合并后发送，需要回执的推送经由同一连接发送
 func (c *Client) Push(ctx context.Context, kind session.Kind, target int64, disconnect bool, message buffer.Buffer, ack bool) error {
 	if ack {
 		seq := c.doGenSequence()
 		buf := protocol.EncodePushReq(seq, kind, target, disconnect, message)
 
-		res, err := c.cli.Call(ctx, seq, buf)
+		res, err := c.doPushCall(ctx, seq, buf)
 		if err != nil {
 			return err
 		}
@@ -157,17 +163,18 @@ func (c *Client) Push(ctx context.Context, kind session.Kind, target int64, disc
 
 		return codes.CodeToError(code)
 	} else {
-		return c.cli.Send(ctx, protocol.EncodePushReq(0, kind, target, disconnect, message), target)
+		return c.doPushSend(ctx, protocol.EncodePushReq(0, kind, target, disconnect, message), target)
 	}
 }
 
 // Multicast 推送组播消息
+// 开启批量推送时，无需回执的组播将合并后发送，需要回执的组播经由同一连接发送
 func (c *Client) Multicast(ctx context.Context, kind session.Kind, targets []int64, disconnect bool, message buffer.Buffer, ack bool) (int64, error) {
 	if ack {
 		seq := c.doGenSequence()
 		buf := protocol.EncodeMulticastReq(seq, kind, targets, disconnect, message)
 
-		res, err := c.cli.Call(ctx, seq, buf)
+		res, err := c.doPushCall(ctx, seq, buf)
 		if err != nil {
 			return 0, err
 		}
@@ -180,17 +187,18 @@ func (c *Client) Multicast(ctx context.Context, kind session.Kind, targets []int
 
 		return int64(total), codes.CodeToError(code)
 	} else {
-		return 0, c.cli.Send(ctx, protocol.EncodeMulticastReq(0, kind, targets, disconnect, message))
+		return 0, c.doPushSend(ctx, protocol.EncodeMulticastReq(0, kind, targets, disconnect, message))
 	}
 }
 
 // Broadcast 推送广播消息
+// 开启批量推送时，无需回执的广播将合并后发送，需要回执的广播经由同一连接发送
 func (c *Client) Broadcast(ctx context.Context, kind session.Kind, disconnect bool, message buffer.Buffer, ack bool) (int64, error) {
 	if ack {
 		seq := c.doGenSequence()
 		buf := protocol.EncodeBroadcastReq(seq, kind, disconnect, message)
 
-		res, err := c.cli.Call(ctx, seq, buf)
+		res, err := c.doPushCall(ctx, seq, buf)
 		if err != nil {
 			return 0, err
 		}
@@ -203,11 +211,12 @@ func (c *Client) Broadcast(ctx context.Context, kind session.Kind, disconnect bo
 
 		return int64(total), codes.CodeToError(code)
 	} else {
-		return 0, c.cli.Send(ctx, protocol.EncodeBroadcastReq(0, kind, disconnect, message))
+		return 0, c.doPushSend(ctx, protocol.EncodeBroadcastReq(0, kind, disconnect, message))
 	}
 }
 
 // Publish 发布频道消息
+// 开启批量推送时，无需回执的频道消息将合并后发送，需要回执的频道消息经由同一连接发送
 func (c *Client) Publish(ctx context.Context, channel string, disconnect bool, message buffer.Buffer, ack bool) (int64, error) {
 	if len(channel) > 1<<8-1 {
 		message.Release()
@@ -218,7 +227,7 @@ func (c *Client) Publish(ctx context.Context, channel string, disconnect bool, m
 		seq := c.doGenSequence()
 		buf := protocol.EncodePublishReq(seq, channel, disconnect, message)
 
-		res, err := c.cli.Call(ctx, seq, buf)
+		res, err := c.doPushCall(ctx, seq, buf)
 		if err != nil {
 			return 0, err
 		}
@@ -231,7 +240,7 @@ func (c *Client) Publish(ctx context.Context, channel string, disconnect bool, m
 
 		return int64(total), codes.CodeToError(code)
 	} else {
-		return 0, c.cli.Send(ctx, protocol.EncodePublishReq(0, channel, disconnect, message))
+		return 0, c.doPushSend(ctx, protocol.EncodePublishReq(0, channel, disconnect, message))
 	}
 }
 
@@ -319,6 +328,32 @@ func (c *Client) SetState(ctx context.Context, state cluster.State) error {
 	return codes.CodeToError(code)
 }
 
+// Close 关闭客户端
+// 开启批量推送时，发送批量推送器中尚未发送的消息
+func (c *Client) Close() {
+	if c.batcher != nil {
+		c.batcher.close()
+	}
+}
+
+// 调用推送类请求，开启批量推送时经由批量推送器调用，以保证与合并发送的推送保持顺序
+func (c *Client) doPushCall(ctx context.Context, seq uint64, buf *buffer.NocopyBuffer) (buffer.Buffer, error) {
+	if c.batcher != nil {
+		return c.batcher.call(ctx, seq, buf)
+	}
+
+	return c.cli.Call(ctx, seq, buf)
+}
+
+// 发送推送类请求，开启批量推送时合并后发送
+func (c *Client) doPushSend(ctx context.Context, buf *buffer.NocopyBuffer, idx ...int64) error {
+	if c.batcher != nil {
+		return c.batcher.add(buf)
+	}
+
+	return c.cli.Send(ctx, buf, idx...)
+}
+
 // 生成序列号，规避生成序列号为0的编号
 func (c *Client) doGenSequence() (seq uint64) {
 	for {
@@ -3,6 +3,7 @@ package gate
 import (
 	"context"
 
+	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/internal/transporter/internal/codes"
 	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
 	"github.com/dobyte/due/v2/internal/transporter/internal/route"
@@ -43,6 +44,7 @@ func (s *Server) init() {
 	s.RegisterHandler(route.Unsubscribe, s.unsubscribe)
 	s.RegisterHandler(route.GetState, s.getState)
 	s.RegisterHandler(route.SetState, s.setState)
+	s.RegisterHandler(route.Batch, s.batch)
 }
 
 // 绑定用户
@@ -173,6 +175,38 @@ func (s *Server) multicast(conn *server.Conn, data []byte) error {
 	}
 }
 
+// 批量推送消息
+// 按合并时的顺序依次分发批量包中的推送、组播、广播及频道消息，以保证同一目标的消息顺序
+func (s *Server) batch(conn *server.Conn, data []byte) error {
+	_, items, err := protocol.DecodeBatchReq(data)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		var e error
+
+		switch _, r, _ := protocol.ParseBuffer(item); r {
+		case route.Push:
+			e = s.push(conn, item)
+		case route.Multicast:
+			e = s.multicast(conn, item)
+		case route.Broadcast:
+			e = s.broadcast(conn, item)
+		case route.Publish:
+			e = s.publish(conn, item)
+		default:
+			e = errors.ErrInvalidMessage
+		}
+
+		if e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
 // 推送广播消息
 func (s *Server) broadcast(conn *server.Conn, data []byte) error {
 	seq, kind, disconnect, message, err := protocol.DecodeBroadcastReq(data)
@@ -23,18 +23,22 @@ type Options struct {
 	WriteTimeout      time.Duration // 写超时时间
 	WriteQueueSize    int32         // 写队列大小
 	FaultRecoveryTime time.Duration // 故障恢复时间
+	BatchWindow       time.Duration // 批量推送合并窗口，为0时不开启批量推送
+	BatchSize         int           // 批量推送合并字节数上限
 }
 
 type Client struct {
-	addr  string        // 连接地址
-	opts  *Options      // 配置
-	pool  sync.Pool     // 对象池
-	conns []*conn       // 连接
-	idx   atomic.Uint64 // 分配连接索引
+	network string        // 网络类型
+	addr    string        // 连接地址
+	opts    *Options      // 配置
+	pool    sync.Pool     // 对象池
+	conns   []*conn       // 连接
+	idx     atomic.Uint64 // 分配连接索引
 }
 
-func NewClient(addr string, opts *Options) *Client {
+func NewClient(network, addr string, opts *Options) *Client {
 	c := &Client{}
+	c.network = network
 	c.addr = addr
 	c.opts = opts
 	c.pool = sync.Pool{New: func() any { return &message{} }}
@@ -77,6 +81,17 @@ func (c *Client) Establish() error {
 
 // Call 调用
 func (c *Client) Call(ctx context.Context, seq uint64, buf *buffer.NocopyBuffer, idx ...int64) (buffer.Buffer, error) {
+	wait, err := c.Go(seq, buf, idx...)
+	if err != nil {
+		return nil, err
+	}
+
+	return wait(ctx)
+}
+
+// Go 异步调用
+// 请求写入连接的发送队列后立即返回，通过返回的wait函数等待响应
+func (c *Client) Go(seq uint64, buf *buffer.NocopyBuffer, idx ...int64) (func(ctx context.Context) (buffer.Buffer, error), error) {
 	conn := c.load(idx...)
 
 	if conn == nil {
@@ -95,6 +110,13 @@ func (c *Client) Call(ctx context.Context, seq uint64, buf *buffer.NocopyBuffer,
 		return nil, err
 	}
 
+	return func(ctx context.Context) (buffer.Buffer, error) {
+		return c.wait(ctx, conn, msg)
+	}, nil
+}
+
+// 等待响应
+func (c *Client) wait(ctx context.Context, conn *conn, msg *message) (buffer.Buffer, error) {
 	if c.opts.CallTimeout > 0 {
 		tctx, tcancel := context.WithTimeout(ctx, c.opts.CallTimeout)
 		defer tcancel()
@@ -11,6 +11,7 @@ import (
 	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/internal/transporter/internal/def"
 	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
+	"github.com/dobyte/due/v2/internal/transporter/internal/socket"
 	"github.com/dobyte/due/v2/log"
 	"github.com/dobyte/due/v2/mode"
 	"github.com/dobyte/due/v2/utils/xtime"
@@ -76,7 +77,7 @@ func (c *conn) doDial() error {
 	)
 
 	for {
-		conn, err := net.DialTimeout("tcp", c.cli.addr, c.cli.opts.DialTimeout)
+		conn, err := socket.Dial(c.cli.network, c.cli.addr, c.cli.opts.DialTimeout)
 		if err != nil {
 			retry++
 
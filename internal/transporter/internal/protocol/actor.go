@@ -0,0 +1,113 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/internal/transporter/internal/route"
+)
+
+const (
+	deliverActorReqBytes = defaultSizeBytes + defaultHeaderBytes + defaultRouteBytes + defaultSeqBytes + b64 + b16 + b16
+	deliverActorResBytes = defaultSizeBytes + defaultHeaderBytes + defaultRouteBytes + defaultSeqBytes + defaultCodeBytes
+)
+
+// MaxPIDBytes Actor PID的最大字节数
+const MaxPIDBytes = 1<<16 - 1
+
+// EncodeDeliverActorReq 编码投递Actor消息请求（src与dst的长度不可超过MaxPIDBytes）
+// 协议：size + header + route + seq + uid + src len + src + dst len + dst + <message packet>
+func EncodeDeliverActorReq(seq uint64, uid int64, src, dst string, buf buffer.Buffer) *buffer.NocopyBuffer {
+	srcBytes := len([]byte(src))
+	dstBytes := len([]byte(dst))
+	size := deliverActorReqBytes + srcBytes + dstBytes
+
+	writer := buffer.MallocWriter(size)
+	writer.WriteUint32s(binary.BigEndian, uint32(size-defaultSizeBytes+buf.Len()))
+	writer.WriteUint8s(dataBit)
+	writer.WriteUint8s(route.DeliverActor)
+	writer.WriteUint64s(binary.BigEndian, seq)
+	writer.WriteInt64s(binary.BigEndian, uid)
+	writer.WriteUint16s(binary.BigEndian, uint16(srcBytes))
+	writer.WriteString(src)
+	writer.WriteUint16s(binary.BigEndian, uint16(dstBytes))
+	writer.WriteString(dst)
+
+	return buffer.NewNocopyBuffer(writer, buf)
+}
+
+// DecodeDeliverActorReq 解码投递Actor消息请求
+// 协议：size + header + route + seq + uid + src len + src + dst len + dst + <message packet>
+func DecodeDeliverActorReq(data []byte) (seq uint64, uid int64, src, dst string, message []byte, err error) {
+	reader := buffer.NewReader(data)
+
+	if _, err = reader.Seek(defaultSizeBytes+defaultHeaderBytes+defaultRouteBytes, io.SeekStart); err != nil {
+		return
+	}
+
+	if seq, err = reader.ReadUint64(binary.BigEndian); err != nil {
+		return
+	}
+
+	if uid, err = reader.ReadInt64(binary.BigEndian); err != nil {
+		return
+	}
+
+	var srcBytes, dstBytes uint16
+
+	if srcBytes, err = reader.ReadUint16(binary.BigEndian); err != nil {
+		return
+	}
+
+	if src, err = reader.ReadString(int(srcBytes)); err != nil {
+		return
+	}
+
+	if dstBytes, err = reader.ReadUint16(binary.BigEndian); err != nil {
+		return
+	}
+
+	if dst, err = reader.ReadString(int(dstBytes)); err != nil {
+		return
+	}
+
+	message = data[deliverActorReqBytes+int(srcBytes)+int(dstBytes):]
+
+	return
+}
+
+// EncodeDeliverActorRes 编码投递Actor消息响应
+// 协议：size + header + route + seq + code
+func EncodeDeliverActorRes(seq uint64, code uint16) *buffer.NocopyBuffer {
+	writer := buffer.MallocWriter(deliverActorResBytes)
+	writer.WriteUint32s(binary.BigEndian, uint32(deliverActorResBytes-defaultSizeBytes))
+	writer.WriteUint8s(dataBit)
+	writer.WriteUint8s(route.DeliverActor)
+	writer.WriteUint64s(binary.BigEndian, seq)
+	writer.WriteUint16s(binary.BigEndian, code)
+
+	return buffer.NewNocopyBuffer(writer)
+}
+
+// DecodeDeliverActorRes 解码投递Actor消息响应
+// 协议：size + header + route + seq + code
+func DecodeDeliverActorRes(data []byte) (code uint16, err error) {
+	if len(data) != deliverActorResBytes {
+		err = errors.ErrInvalidMessage
+		return
+	}
+
+	reader := buffer.NewReader(data)
+
+	if _, err = reader.Seek(-defaultCodeBytes, io.SeekEnd); err != nil {
+		return
+	}
+
+	if code, err = reader.ReadUint16(binary.BigEndian); err != nil {
+		return
+	}
+
+	return
+}
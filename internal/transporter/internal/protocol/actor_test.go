@@ -0,0 +1,63 @@
+package protocol_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/internal/transporter/internal/codes"
+	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
+)
+
+func TestEncodeDeliverActorReq(t *testing.T) {
+	buffer := protocol.EncodeDeliverActorReq(1, 2, "room/1", "guild/2", buffer.NewNocopyBuffer([]byte("hello world")))
+
+	t.Log(buffer.Bytes())
+}
+
+func TestDecodeDeliverActorReq(t *testing.T) {
+	buffer := protocol.EncodeDeliverActorReq(1, 2, "room/1", "guild/2", buffer.NewNocopyBuffer([]byte("hello world")))
+
+	seq, uid, src, dst, message, err := protocol.DecodeDeliverActorReq(buffer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("seq: %v", seq)
+	t.Logf("uid: %v", uid)
+	t.Logf("src: %v", src)
+	t.Logf("dst: %v", dst)
+	t.Logf("message: %v", string(message))
+}
+
+func TestDecodeDeliverActorReq_LongPID(t *testing.T) {
+	src, dst := strings.Repeat("s", 300), strings.Repeat("d", 1000)
+
+	buffer := protocol.EncodeDeliverActorReq(1, 2, src, dst, buffer.NewNocopyBuffer([]byte("hello world")))
+
+	_, _, s, d, message, err := protocol.DecodeDeliverActorReq(buffer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s != src || d != dst || string(message) != "hello world" {
+		t.Fatalf("unexpected decode result: src %d bytes, dst %d bytes, message %q", len(s), len(d), message)
+	}
+}
+
+func TestEncodeDeliverActorRes(t *testing.T) {
+	buffer := protocol.EncodeDeliverActorRes(1, codes.OK)
+
+	t.Log(buffer.Bytes())
+}
+
+func TestDecodeDeliverActorRes(t *testing.T) {
+	buffer := protocol.EncodeDeliverActorRes(1, codes.NotFoundActor)
+
+	code, err := protocol.DecodeDeliverActorRes(buffer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("code: %v", code)
+}
@@ -0,0 +1,78 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/internal/transporter/internal/route"
+)
+
+const (
+	batchReqBytes = defaultSizeBytes + defaultHeaderBytes + defaultRouteBytes + defaultSeqBytes + b16
+)
+
+const MaxBatchItems = 1<<16 - 1 // 单个批量包最多可容纳的消息数
+
+// EncodeBatchReq 编码批量请求（最多合并65535个请求）
+// 协议：size + header + route + seq + count + <request packet> + <request packet> + ...
+func EncodeBatchReq(seq uint64, items ...*buffer.NocopyBuffer) *buffer.NocopyBuffer {
+	size := batchReqBytes - defaultSizeBytes
+	for _, item := range items {
+		size += item.Len()
+	}
+
+	writer := buffer.MallocWriter(batchReqBytes)
+	writer.WriteUint32s(binary.BigEndian, uint32(size))
+	writer.WriteUint8s(dataBit)
+	writer.WriteUint8s(route.Batch)
+	writer.WriteUint64s(binary.BigEndian, seq)
+	writer.WriteUint16s(binary.BigEndian, uint16(len(items)))
+
+	buf := buffer.NewNocopyBuffer(writer)
+	for _, item := range items {
+		buf.Mount(item)
+	}
+
+	return buf
+}
+
+// DecodeBatchReq 解码批量请求
+// 协议：size + header + route + seq + count + <request packet> + <request packet> + ...
+func DecodeBatchReq(data []byte) (seq uint64, items [][]byte, err error) {
+	reader := buffer.NewReader(data)
+
+	if _, err = reader.Seek(defaultSizeBytes+defaultHeaderBytes+defaultRouteBytes, io.SeekStart); err != nil {
+		return
+	}
+
+	if seq, err = reader.ReadUint64(binary.BigEndian); err != nil {
+		return
+	}
+
+	count, err := reader.ReadUint16(binary.BigEndian)
+	if err != nil {
+		return
+	}
+
+	items = make([][]byte, 0, count)
+
+	for offset := batchReqBytes; len(items) < int(count); {
+		if offset+defaultSizeBytes+defaultHeaderBytes+defaultRouteBytes > len(data) {
+			err = errors.ErrInvalidMessage
+			return
+		}
+
+		end := offset + defaultSizeBytes + int(binary.BigEndian.Uint32(data[offset:offset+defaultSizeBytes]))
+		if end > len(data) {
+			err = errors.ErrInvalidMessage
+			return
+		}
+
+		items = append(items, data[offset:end])
+		offset = end
+	}
+
+	return
+}
@@ -0,0 +1,64 @@
+package protocol_test
+
+import (
+	"testing"
+
+	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
+	"github.com/dobyte/due/v2/internal/transporter/internal/route"
+	"github.com/dobyte/due/v2/session"
+)
+
+func TestDecodeBatchReq(t *testing.T) {
+	buf := protocol.EncodeBatchReq(0,
+		protocol.EncodePushReq(0, session.User, 1, false, buffer.NewNocopyBuffer([]byte("hello"))),
+		protocol.EncodeMulticastReq(0, session.User, []int64{2, 3}, false, buffer.NewNocopyBuffer([]byte("world"))),
+		protocol.EncodePushReq(0, session.User, 1, true, buffer.NewNocopyBuffer([]byte("bye"))),
+	)
+
+	seq, items, err := protocol.DecodeBatchReq(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seq != 0 || len(items) != 3 {
+		t.Fatalf("invalid batch: seq = %d, items = %d", seq, len(items))
+	}
+
+	for i, want := range []uint8{route.Push, route.Multicast, route.Push} {
+		if _, r, _ := protocol.ParseBuffer(items[i]); r != want {
+			t.Fatalf("item %d: route = %d, want %d", i, r, want)
+		}
+	}
+
+	_, _, target, _, message, err := protocol.DecodePushReq(items[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if target != 1 || string(message) != "hello" {
+		t.Fatalf("invalid push: target = %d, message = %s", target, message)
+	}
+
+	_, _, targets, _, message, err := protocol.DecodeMulticastReq(items[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(targets) != 2 || string(message) != "world" {
+		t.Fatalf("invalid multicast: targets = %v, message = %s", targets, message)
+	}
+
+	_, _, _, disconnect, message, err := protocol.DecodePushReq(items[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !disconnect || string(message) != "bye" {
+		t.Fatalf("invalid push: disconnect = %v, message = %s", disconnect, message)
+	}
+
+	if _, _, err = protocol.DecodeBatchReq(buf.Bytes()[:len(buf.Bytes())-1]); err == nil {
+		t.Fatal("expected error for truncated batch")
+	}
+}
@@ -1,17 +1,19 @@
 package protocol
 
 const (
-	defaultSizeBytes   = 4 // 包长度字节数
-	defaultHeaderBytes = 1 // 头信息字节数
-	defaultSeqBytes    = 8 // 序列号字节数
-	defaultRouteBytes  = 1 // 路由号字节数
-	defaultCodeBytes   = 2 // 错误码字节数
+	defaultSizeBytes   = 4  // 包长度字节数
+	defaultHeaderBytes = 1  // 头信息字节数
+	defaultSeqBytes    = 8  // 序列号字节数
+	defaultRouteBytes  = 1  // 路由号字节数
+	defaultCodeBytes   = 2  // 错误码字节数
+	defaultTraceBytes  = 25 // 链路上下文字节数：traceID(16) + spanID(8) + flags(1)
 )
 
 const (
 	dataBit       uint8 = 0 << 7 // 数据标识位
 	heartbeatBit  uint8 = 1 << 7 // 心跳标识位
 	disconnectBit uint8 = 1 << 6 // 断连标识位
+	traceBit      uint8 = 1 << 5 // 链路追踪标识位
 )
 
 const (
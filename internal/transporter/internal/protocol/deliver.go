@@ -7,6 +7,7 @@ import (
 	"github.com/dobyte/due/v2/core/buffer"
 	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/internal/transporter/internal/route"
+	"github.com/dobyte/due/v2/tracing"
 )
 
 const (
@@ -15,20 +16,35 @@ const (
 )
 
 // EncodeDeliverReq 编码投递消息请求
-// 协议：size + header + route + seq + cid + uid + <message packet>
-func EncodeDeliverReq(seq uint64, cid int64, uid int64, buf buffer.Buffer) *buffer.NocopyBuffer {
-	writer := buffer.MallocWriter(deliverReqBytes)
-	writer.WriteUint32s(binary.BigEndian, uint32(deliverReqBytes-defaultSizeBytes+buf.Len()))
-	writer.WriteUint8s(dataBit)
+// 协议：size + header + route + seq + cid + uid + [trace] + <message packet>
+// 链路上下文有效时，header中的链路追踪标识位置1，并在uid后附带traceID + spanID + flags
+func EncodeDeliverReq(seq uint64, cid int64, uid int64, sc tracing.SpanContext, buf buffer.Buffer) *buffer.NocopyBuffer {
+	var (
+		size   = deliverReqBytes
+		header = dataBit
+	)
+
+	if sc.IsValid() {
+		size += defaultTraceBytes
+		header |= traceBit
+	}
+
+	writer := buffer.MallocWriter(size)
+	writer.WriteUint32s(binary.BigEndian, uint32(size-defaultSizeBytes+buf.Len()))
+	writer.WriteUint8s(header)
 	writer.WriteUint8s(route.Deliver)
 	writer.WriteUint64s(binary.BigEndian, seq)
 	writer.WriteInt64s(binary.BigEndian, cid, uid)
 
+	if sc.IsValid() {
+		writeTrace(writer, sc)
+	}
+
 	return buffer.NewNocopyBuffer(writer, buf)
 }
 
 // DecodeDeliverReq 解码投递消息请求
-func DecodeDeliverReq(data []byte) (seq uint64, cid int64, uid int64, message []byte, err error) {
+func DecodeDeliverReq(data []byte) (seq uint64, cid int64, uid int64, sc tracing.SpanContext, message []byte, err error) {
 	reader := buffer.NewReader(data)
 
 	if _, err = reader.Seek(defaultSizeBytes+defaultHeaderBytes+defaultRouteBytes, io.SeekStart); err != nil {
@@ -47,7 +63,16 @@ func DecodeDeliverReq(data []byte) (seq uint64, cid int64, uid int64, message []
 		return
 	}
 
-	message = data[deliverReqBytes:]
+	if data[defaultSizeBytes]&traceBit != traceBit {
+		message = data[deliverReqBytes:]
+		return
+	}
+
+	if sc, err = readTrace(reader); err != nil {
+		return
+	}
+
+	message = data[deliverReqBytes+defaultTraceBytes:]
 
 	return
 }
@@ -6,22 +6,36 @@ import (
 	"github.com/dobyte/due/v2/core/buffer"
 	"github.com/dobyte/due/v2/internal/transporter/internal/codes"
 	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
+	"github.com/dobyte/due/v2/tracing"
 )
 
 func TestEncodeDeliverReq(t *testing.T) {
-	buffer := protocol.EncodeDeliverReq(1, 2, 3, buffer.NewNocopyBuffer([]byte("hello world")))
+	buffer := protocol.EncodeDeliverReq(1, 2, 3, tracing.SpanContext{}, buffer.NewNocopyBuffer([]byte("hello world")))
 
 	t.Log(buffer.Bytes())
 }
 
 func TestDecodeDeliverReq(t *testing.T) {
-	buffer := protocol.EncodeDeliverReq(1, 2, 3, buffer.NewNocopyBuffer([]byte("hello world")))
+	sc, err := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := protocol.EncodeDeliverReq(1, 2, 3, sc, buffer.NewNocopyBuffer([]byte("hello world")))
 
-	seq, cid, uid, message, err := protocol.DecodeDeliverReq(buffer.Bytes())
+	seq, cid, uid, trace, message, err := protocol.DecodeDeliverReq(buffer.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if trace.TraceID != sc.TraceID || trace.SpanID != sc.SpanID || !trace.Sampled {
+		t.Fatalf("trace mismatch: %v", trace)
+	}
+
+	if string(message) != "hello world" {
+		t.Fatalf("message mismatch: %s", message)
+	}
+
 	t.Logf("seq: %v", seq)
 	t.Logf("cid: %v", cid)
 	t.Logf("uid: %v", uid)
@@ -92,6 +92,10 @@ func DecodeStatRes(data []byte) (code uint16, total uint64, err error) {
 		return
 	}
 
+	if code, err = reader.ReadUint16(binary.BigEndian); err != nil {
+		return
+	}
+
 	if len(data) == statResBytes {
 		total, err = reader.ReadUint64(binary.BigEndian)
 	}
@@ -1,6 +1,7 @@
 package protocol_test
 
 import (
+	"github.com/dobyte/due/v2/internal/transporter/internal/codes"
 	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
 	"github.com/dobyte/due/v2/session"
 	"testing"
@@ -19,13 +20,17 @@ func TestDecodeStatReq(t *testing.T) {
 }
 
 func TestDecodeStatRes(t *testing.T) {
-	buffer := protocol.EncodeStatRes(1, 2000)
+	buffer := protocol.EncodeStatRes(1, codes.OK, 2000)
 
 	code, total, err := protocol.DecodeStatRes(buffer.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if code != codes.OK || total != 2000 {
+		t.Fatalf("code = %v total = %v, want %v %v", code, total, codes.OK, 2000)
+	}
+
 	t.Logf("code: %v", code)
 	t.Logf("total: %v", total)
 }
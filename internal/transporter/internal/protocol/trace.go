@@ -0,0 +1,47 @@
+package protocol
+
+import (
+	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/tracing"
+)
+
+// 写入链路上下文
+func writeTrace(writer *buffer.Writer, sc tracing.SpanContext) {
+	var flags uint8
+
+	if sc.Sampled {
+		flags = 1
+	}
+
+	writer.WriteBytes(sc.TraceID[:]...)
+	writer.WriteBytes(sc.SpanID[:]...)
+	writer.WriteUint8s(flags)
+}
+
+// 读取链路上下文
+func readTrace(reader *buffer.Reader) (sc tracing.SpanContext, err error) {
+	var (
+		traceID []byte
+		spanID  []byte
+		flags   uint8
+	)
+
+	if traceID, err = reader.ReadBytes(len(sc.TraceID)); err != nil {
+		return
+	}
+
+	if spanID, err = reader.ReadBytes(len(sc.SpanID)); err != nil {
+		return
+	}
+
+	if flags, err = reader.ReadUint8(); err != nil {
+		return
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags&0x01 == 0x01
+	sc.Remote = true
+
+	return
+}
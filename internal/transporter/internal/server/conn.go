@@ -49,21 +49,28 @@ func (c *Conn) Send(buf *buffer.NocopyBuffer) error {
 		return errors.ErrConnectionClosed
 	}
 
+	ctx := c.ctx
+
 	if c.server.opts.WriteTimeout > 0 && len(c.chWrite) == cap(c.chWrite) {
-		if len(c.chWrite) == cap(c.chWrite) {
-			ctx, cancel := context.WithTimeout(context.Background(), c.server.opts.WriteTimeout)
-			defer cancel()
-
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case c.chWrite <- buf:
-				return nil
-			}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(c.ctx, c.server.opts.WriteTimeout)
+		defer cancel()
+	}
+
+	// 连接关闭后写协程退出，写入通道不再关闭，避免与处理中的请求并发发送时出现panic
+	select {
+	case <-ctx.Done():
+		if c.ctx.Err() != nil {
+			return errors.ErrConnectionClosed
 		}
+		return ctx.Err()
+	case c.chWrite <- buf:
 	}
 
-	c.chWrite <- buf
+	// 关闭连接与写入并发时，释放关闭后写入通道中残留的消息
+	if c.ctx.Err() != nil {
+		c.drain()
+	}
 
 	return nil
 }
@@ -76,17 +83,25 @@ func (c *Conn) close(isNeedRecycle ...bool) error {
 
 	c.cancel()
 
+	c.drain()
+
 	if len(isNeedRecycle) > 0 && isNeedRecycle[0] {
 		c.server.recycle(c.conn)
 	}
 
-	err := c.conn.Close()
-
-	time.AfterFunc(time.Second, func() {
-		close(c.chWrite)
-	})
+	return c.conn.Close()
+}
 
-	return err
+// 释放写入通道中未发送的消息
+func (c *Conn) drain() {
+	for {
+		select {
+		case buf := <-c.chWrite:
+			buf.Release()
+		default:
+			return
+		}
+	}
 }
 
 // 读取消息
@@ -143,12 +158,8 @@ func (c *Conn) write() {
 				_ = c.close(true)
 				return
 			}
-		case buf, ok := <-c.chWrite:
-			if !ok {
-				return
-			}
-
-			ok = buf.Visit(func(node *buffer.NocopyNode) bool {
+		case buf := <-c.chWrite:
+			ok := buf.Visit(func(node *buffer.NocopyNode) bool {
 				if _, err := c.conn.Write(node.Bytes()); err != nil {
 					log.Warnf("write buffer message error: %v", err)
 					return false
@@ -6,20 +6,19 @@ import (
 	"time"
 
 	"github.com/dobyte/due/v2/core/endpoint"
-	xnet "github.com/dobyte/due/v2/core/net"
 	"github.com/dobyte/due/v2/internal/transporter/internal/codes"
 	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
 	"github.com/dobyte/due/v2/internal/transporter/internal/route"
+	"github.com/dobyte/due/v2/internal/transporter/internal/socket"
 	"github.com/dobyte/due/v2/log"
 )
 
-const scheme = "drpc"
-
 type RouteHandler func(conn *Conn, data []byte) error
 
 type Server struct {
 	opts        *Options
 	listener    net.Listener           // 监听器
+	network     string                 // 网络类型
 	listenAddr  string                 // 监听地址
 	exposeAddr  string                 // 暴露地址
 	endpoint    *endpoint.Endpoint     // 暴露端点
@@ -29,16 +28,17 @@ type Server struct {
 }
 
 func NewServer(opts *Options) (*Server, error) {
-	listenAddr, exposeAddr, err := xnet.ParseAddr(opts.Addr, opts.Expose)
+	network, listenAddr, exposeAddr, err := socket.ParseAddr(opts.Addr, opts.Expose)
 	if err != nil {
 		return nil, err
 	}
 
 	s := &Server{}
 	s.opts = opts
+	s.network = network
 	s.listenAddr = listenAddr
 	s.exposeAddr = exposeAddr
-	s.endpoint = endpoint.NewEndpoint(scheme, exposeAddr, false)
+	s.endpoint = endpoint.NewEndpoint(socket.Scheme(network), exposeAddr, false)
 	s.connections = make(map[net.Conn]*Conn)
 	s.handlers = make(map[uint8]RouteHandler)
 	s.handlers[route.Handshake] = s.handshake
@@ -48,7 +48,7 @@ func NewServer(opts *Options) (*Server, error) {
 
 // Scheme 协议
 func (s *Server) Scheme() string {
-	return scheme
+	return socket.Scheme(s.network)
 }
 
 // ListenAddr 监听地址
@@ -68,12 +68,7 @@ func (s *Server) Endpoint() *endpoint.Endpoint {
 
 // Start 启动服务器
 func (s *Server) Start() error {
-	addr, err := net.ResolveTCPAddr("tcp", s.listenAddr)
-	if err != nil {
-		return err
-	}
-
-	ln, err := net.ListenTCP(addr.Network(), addr)
+	ln, err := socket.Listen(s.network, s.listenAddr)
 	if err != nil {
 		return err
 	}
@@ -96,12 +91,12 @@ func (s *Server) Start() error {
 					tempDelay = time.Second
 				}
 
-				log.Warnf("tcp accept connect error: %v; retrying in %v", err, tempDelay)
+				log.Warnf("%s accept connect error: %v; retrying in %v", s.network, err, tempDelay)
 				time.Sleep(tempDelay)
 				continue
 			}
 
-			log.Warnf("tcp accept connect error: %v", err)
+			log.Warnf("%s accept connect error: %v", s.network, err)
 			return nil
 		}
 
@@ -0,0 +1,118 @@
+package socket
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+)
+
+var listeners sync.Map
+
+type inprocAddr string
+
+func (a inprocAddr) Network() string {
+	return NetworkInproc
+}
+
+func (a inprocAddr) String() string {
+	return string(a)
+}
+
+type inprocConn struct {
+	net.Conn
+	localAddr  net.Addr
+	remoteAddr net.Addr
+}
+
+// LocalAddr 获取本地地址
+func (c *inprocConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+// RemoteAddr 获取远端地址
+func (c *inprocConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+type inprocListener struct {
+	addr  inprocAddr
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+// 监听进程内地址
+func listenInproc(name string) (net.Listener, error) {
+	ln := &inprocListener{
+		addr:  inprocAddr(name),
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+
+	if _, loaded := listeners.LoadOrStore(name, ln); loaded {
+		return nil, &net.OpError{Op: "listen", Net: NetworkInproc, Addr: ln.addr, Err: errors.ErrIllegalOperation}
+	}
+
+	return ln, nil
+}
+
+// 拨号进程内地址
+// 两端通过内存管道直接交换数据，无需经过内核协议栈
+func dialInproc(name string, timeout time.Duration) (net.Conn, error) {
+	v, ok := listeners.Load(name)
+	if !ok {
+		return nil, &net.OpError{Op: "dial", Net: NetworkInproc, Addr: inprocAddr(name), Err: errors.ErrServerClosed}
+	}
+
+	ln := v.(*inprocListener)
+	cp, sp := net.Pipe()
+	cc := &inprocConn{Conn: cp, localAddr: inprocAddr("client"), remoteAddr: ln.addr}
+	sc := &inprocConn{Conn: sp, localAddr: ln.addr, remoteAddr: inprocAddr("client")}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case ln.conns <- sc:
+		return cc, nil
+	case <-ln.done:
+		_ = cp.Close()
+		_ = sp.Close()
+		return nil, &net.OpError{Op: "dial", Net: NetworkInproc, Addr: ln.addr, Err: errors.ErrServerClosed}
+	case <-expired:
+		_ = cp.Close()
+		_ = sp.Close()
+		return nil, &net.OpError{Op: "dial", Net: NetworkInproc, Addr: ln.addr, Err: errors.ErrDeadlineExceeded}
+	}
+}
+
+// Accept 接收连接
+func (l *inprocListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, &net.OpError{Op: "accept", Net: NetworkInproc, Addr: l.addr, Err: net.ErrClosed}
+	}
+}
+
+// Close 关闭监听器
+func (l *inprocListener) Close() error {
+	l.once.Do(func() {
+		listeners.CompareAndDelete(string(l.addr), l)
+		close(l.done)
+	})
+
+	return nil
+}
+
+// Addr 监听地址
+func (l *inprocListener) Addr() net.Addr {
+	return l.addr
+}
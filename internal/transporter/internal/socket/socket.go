@@ -0,0 +1,118 @@
+package socket
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dobyte/due/v2/core/endpoint"
+	xnet "github.com/dobyte/due/v2/core/net"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/utils/xuuid"
+)
+
+const (
+	NetworkTCP    = "tcp"    // TCP网络
+	NetworkUnix   = "unix"   // Unix域套接字
+	NetworkInproc = "inproc" // 进程内管道
+)
+
+const (
+	SchemeTCP    = "drpc"        // TCP传输协议
+	SchemeUnix   = "drpc+unix"   // Unix域套接字传输协议
+	SchemeInproc = "drpc+inproc" // 进程内传输协议
+)
+
+const (
+	unixPrefix   = NetworkUnix + "://"
+	inprocPrefix = NetworkInproc + "://"
+)
+
+// ParseAddr 解析监听地址
+// 支持以下格式：
+// unix:///path/to/due.sock : Unix域套接字，相对路径将被转换为绝对路径
+// inproc://name           : 进程内管道，名称为空时将自动生成
+// [host]:[port]           : TCP地址，解析规则同core/net.ParseAddr
+func ParseAddr(addr string, expose bool) (network, listenAddr, exposeAddr string, err error) {
+	switch {
+	case strings.HasPrefix(addr, unixPrefix):
+		path := strings.TrimPrefix(addr, unixPrefix)
+		if path == "" {
+			return "", "", "", errors.ErrInvalidArgument
+		}
+
+		if path, err = filepath.Abs(path); err != nil {
+			return "", "", "", err
+		}
+
+		return NetworkUnix, path, path, nil
+	case strings.HasPrefix(addr, inprocPrefix):
+		name := strings.TrimPrefix(addr, inprocPrefix)
+		if name == "" {
+			name = xuuid.UUID()
+		}
+
+		return NetworkInproc, name, name, nil
+	default:
+		if listenAddr, exposeAddr, err = xnet.ParseAddr(addr, expose); err != nil {
+			return "", "", "", err
+		}
+
+		return NetworkTCP, listenAddr, exposeAddr, nil
+	}
+}
+
+// Scheme 获取网络对应的端点协议
+func Scheme(network string) string {
+	switch network {
+	case NetworkUnix:
+		return SchemeUnix
+	case NetworkInproc:
+		return SchemeInproc
+	default:
+		return SchemeTCP
+	}
+}
+
+// Network 获取端点对应的网络
+func Network(ep *endpoint.Endpoint) (string, error) {
+	switch ep.Scheme() {
+	case SchemeTCP:
+		return NetworkTCP, nil
+	case SchemeUnix:
+		return NetworkUnix, nil
+	case SchemeInproc:
+		return NetworkInproc, nil
+	default:
+		return "", errors.ErrUnsupportedScheme
+	}
+}
+
+// Listen 监听地址
+func Listen(network, addr string) (net.Listener, error) {
+	switch network {
+	case NetworkInproc:
+		return listenInproc(addr)
+	case NetworkUnix:
+		// 清理进程异常退出后残留的套接字文件
+		if err := os.Remove(addr); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		return net.Listen(network, addr)
+	default:
+		return net.Listen(network, addr)
+	}
+}
+
+// Dial 拨号
+func Dial(network, addr string, timeout time.Duration) (net.Conn, error) {
+	switch network {
+	case NetworkInproc:
+		return dialInproc(addr, timeout)
+	default:
+		return net.DialTimeout(network, addr, timeout)
+	}
+}
@@ -0,0 +1,100 @@
+package socket_test
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/core/endpoint"
+	"github.com/dobyte/due/v2/internal/transporter/internal/socket"
+)
+
+func TestParseAddr(t *testing.T) {
+	network, listenAddr, exposeAddr, err := socket.ParseAddr("unix://due.sock", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if network != socket.NetworkUnix || !filepath.IsAbs(listenAddr) || listenAddr != exposeAddr {
+		t.Fatalf("unexpected unix addr: %s %s %s", network, listenAddr, exposeAddr)
+	}
+
+	network, listenAddr, _, err = socket.ParseAddr("inproc://", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if network != socket.NetworkInproc || listenAddr == "" {
+		t.Fatalf("unexpected inproc addr: %s %s", network, listenAddr)
+	}
+}
+
+func TestListenAndDial(t *testing.T) {
+	addrs := []string{
+		"unix://" + filepath.Join(t.TempDir(), "due.sock"),
+		"inproc://due",
+	}
+
+	for _, addr := range addrs {
+		network, listenAddr, exposeAddr, err := socket.ParseAddr(addr, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ln, err := socket.Listen(network, listenAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			_, _ = io.Copy(conn, conn)
+		}()
+
+		ep := endpoint.NewEndpoint(socket.Scheme(network), exposeAddr, false)
+
+		ep, err = endpoint.ParseEndpoint(ep.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := socket.Network(ep)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conn, err := socket.Dial(n, ep.Address(), time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data := []byte("hello due")
+
+		go func() {
+			_, _ = conn.Write(data)
+		}()
+
+		buf := make([]byte, len(data))
+		if _, err = io.ReadFull(conn, buf); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(buf, data) {
+			t.Fatalf("%s: unexpected echo: %s", network, buf)
+		}
+
+		_ = conn.Close()
+		_ = ln.Close()
+
+		if _, err = socket.Dial(n, ep.Address(), time.Second); err == nil {
+			t.Fatalf("%s: dial closed listener should fail", network)
+		}
+	}
+}
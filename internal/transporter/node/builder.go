@@ -3,7 +3,9 @@ package node
 import (
 	"sync"
 
+	"github.com/dobyte/due/v2/core/endpoint"
 	"github.com/dobyte/due/v2/internal/transporter/internal/client"
+	"github.com/dobyte/due/v2/internal/transporter/internal/socket"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -22,17 +24,26 @@ func NewBuilder(opts *ClientOptions) *Builder {
 }
 
 // Build 构建客户端
-func (b *Builder) Build(addr string) (*Client, error) {
-	if cli, ok := b.clients.Load(addr); ok {
+// 根据端点协议选择TCP、Unix域套接字或进程内传输
+func (b *Builder) Build(ep *endpoint.Endpoint) (*Client, error) {
+	network, err := socket.Network(ep)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := ep.Address()
+	key := network + "://" + addr
+
+	if cli, ok := b.clients.Load(key); ok {
 		return cli.(*Client), nil
 	}
 
-	cli, err, _ := b.sfg.Do(addr, func() (any, error) {
-		if cli, ok := b.clients.Load(addr); ok {
+	cli, err, _ := b.sfg.Do(key, func() (any, error) {
+		if cli, ok := b.clients.Load(key); ok {
 			return cli.(*Client), nil
 		}
 
-		c := client.NewClient(addr, b.opts)
+		c := client.NewClient(network, addr, b.opts)
 
 		if err := c.Establish(); err != nil {
 			return nil, err
@@ -40,7 +51,7 @@ func (b *Builder) Build(addr string) (*Client, error) {
 
 		cli := NewClient(c)
 
-		b.clients.Store(addr, cli)
+		b.clients.Store(key, cli)
 
 		return cli, nil
 	})
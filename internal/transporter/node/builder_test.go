@@ -7,6 +7,7 @@ import (
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/core/endpoint"
 	"github.com/dobyte/due/v2/internal/transporter/node"
 	"github.com/dobyte/due/v2/utils/xuuid"
 )
@@ -24,7 +25,7 @@ func TestBuilder(t *testing.T) {
 		FaultRecoveryTime: 3 * time.Second,
 	})
 
-	client, err := builder.Build("127.0.0.1:49898")
+	client, err := builder.Build(endpoint.NewEndpoint("drpc", "127.0.0.1:49898", false))
 	if err != nil {
 		t.Fatal(err)
 	}
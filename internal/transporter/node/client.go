@@ -6,9 +6,11 @@ import (
 
 	"github.com/dobyte/due/v2/cluster"
 	"github.com/dobyte/due/v2/core/buffer"
+	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/internal/transporter/internal/client"
 	"github.com/dobyte/due/v2/internal/transporter/internal/codes"
 	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
+	"github.com/dobyte/due/v2/tracing"
 )
 
 type Client struct {
@@ -29,7 +31,29 @@ func (c *Client) Trigger(ctx context.Context, event cluster.Event, cid, uid int6
 
 // Deliver 投递消息
 func (c *Client) Deliver(ctx context.Context, cid, uid int64, buf buffer.Buffer) error {
-	return c.cli.Send(ctx, protocol.EncodeDeliverReq(0, cid, uid, buf), cid)
+	return c.cli.Send(ctx, protocol.EncodeDeliverReq(0, cid, uid, tracing.SpanContextFromContext(ctx), buf), cid)
+}
+
+// DeliverActor 投递Actor消息
+func (c *Client) DeliverActor(ctx context.Context, uid int64, src, dst string, buf buffer.Buffer) error {
+	if len(src) > protocol.MaxPIDBytes || len(dst) > protocol.MaxPIDBytes {
+		return errors.ErrInvalidMessage
+	}
+
+	seq := c.doGenSequence()
+
+	res, err := c.cli.Call(ctx, seq, protocol.EncodeDeliverActorReq(seq, uid, src, dst, buf), uid)
+	if err != nil {
+		return err
+	}
+	defer res.Release()
+
+	code, err := protocol.DecodeDeliverActorRes(res.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return codes.CodeToError(code)
 }
 
 // GetState 获取状态
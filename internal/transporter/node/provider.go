@@ -10,6 +10,8 @@ type Provider interface {
 	Trigger(ctx context.Context, gid string, cid, uid int64, event cluster.Event) error
 	// Deliver 投递消息
 	Deliver(ctx context.Context, gid, nid string, cid, uid int64, message []byte) error
+	// DeliverActor 投递Actor消息
+	DeliverActor(ctx context.Context, nid string, uid int64, src, dst string, message []byte) error
 	// GetState 获取状态
 	GetState() (cluster.State, error)
 	// SetState 设置状态
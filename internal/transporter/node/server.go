@@ -9,6 +9,7 @@ import (
 	"github.com/dobyte/due/v2/internal/transporter/internal/protocol"
 	"github.com/dobyte/due/v2/internal/transporter/internal/route"
 	"github.com/dobyte/due/v2/internal/transporter/internal/server"
+	"github.com/dobyte/due/v2/tracing"
 )
 
 type Server struct {
@@ -35,6 +36,7 @@ func (s *Server) init() {
 	s.RegisterHandler(route.Deliver, s.deliver)
 	s.RegisterHandler(route.GetState, s.getState)
 	s.RegisterHandler(route.SetState, s.setState)
+	s.RegisterHandler(route.DeliverActor, s.deliverActor)
 }
 
 // 触发事件
@@ -61,7 +63,7 @@ func (s *Server) trigger(conn *server.Conn, data []byte) error {
 
 // 投递消息
 func (s *Server) deliver(conn *server.Conn, data []byte) error {
-	seq, cid, uid, message, err := protocol.DecodeDeliverReq(data)
+	seq, cid, uid, sc, message, err := protocol.DecodeDeliverReq(data)
 	if err != nil {
 		return err
 	}
@@ -80,13 +82,31 @@ func (s *Server) deliver(conn *server.Conn, data []byte) error {
 		return errors.ErrIllegalRequest
 	}
 
-	if err = s.provider.Deliver(context.Background(), gid, nid, cid, uid, message); seq == 0 {
+	ctx := tracing.ContextWithRemoteSpanContext(context.Background(), sc)
+
+	if err = s.provider.Deliver(ctx, gid, nid, cid, uid, message); seq == 0 {
 		return err
 	} else {
 		return conn.Send(protocol.EncodeDeliverRes(seq, codes.ErrorToCode(err)))
 	}
 }
 
+// 投递Actor消息
+func (s *Server) deliverActor(conn *server.Conn, data []byte) error {
+	seq, uid, src, dst, message, err := protocol.DecodeDeliverActorReq(data)
+	if err != nil {
+		return err
+	}
+
+	if conn.InsKind != cluster.Node {
+		return errors.ErrIllegalRequest
+	}
+
+	err = s.provider.DeliverActor(context.Background(), conn.InsID, uid, src, dst, message)
+
+	return conn.Send(protocol.EncodeDeliverActorRes(seq, codes.ErrorToCode(err)))
+}
+
 // 获取状态
 func (s *Server) getState(conn *server.Conn, data []byte) error {
 	seq, err := protocol.DecodeGetStateReq(data)
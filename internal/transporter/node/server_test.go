@@ -40,6 +40,12 @@ func (p *provider) Deliver(ctx context.Context, gid, nid string, cid, uid int64,
 	return nil
 }
 
+// DeliverActor 投递Actor消息
+func (p *provider) DeliverActor(ctx context.Context, nid string, uid int64, src, dst string, message []byte) error {
+	log.Infof("nid: %s, uid: %d, src: %s, dst: %s message: %s", nid, uid, src, dst, string(message))
+	return nil
+}
+
 // GetState 获取状态
 func (p *provider) GetState() (cluster.State, error) {
 	return cluster.Work, nil
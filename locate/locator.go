@@ -32,6 +32,15 @@ type Locator interface {
 	LocateNodes(ctx context.Context, uid int64) (map[string]string, error)
 }
 
+type ActorLocator interface {
+	// BindActor 绑定Actor所在节点
+	BindActor(ctx context.Context, pid, nid string) error
+	// UnbindActor 解绑Actor所在节点
+	UnbindActor(ctx context.Context, pid, nid string) error
+	// LocateActor 定位Actor所在节点
+	LocateActor(ctx context.Context, pid string) (string, error)
+}
+
 type Watcher interface {
 	// Next 返回用户位置列表
 	Next() ([]*Event, error)
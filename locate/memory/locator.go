@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/locate"
+)
+
+const name = "memory"
+
+var (
+	_ locate.Locator      = &Locator{}
+	_ locate.ActorLocator = &Locator{}
+)
+
+// Locator 基于进程内存的定位器
+// 同一进程内的多个组件共享同一个实例即可组成集群，适用于单元测试及单进程开发环境
+type Locator struct {
+	rw       sync.RWMutex
+	gates    map[int64]string            // uid -> gid
+	nodes    map[int64]map[string]string // uid -> name -> nid
+	actors   map[string]string           // pid -> nid
+	watchers map[int64]*watcher
+	idx      int64
+}
+
+func NewLocator() *Locator {
+	return &Locator{
+		gates:    make(map[int64]string),
+		nodes:    make(map[int64]map[string]string),
+		actors:   make(map[string]string),
+		watchers: make(map[int64]*watcher),
+	}
+}
+
+// Name 获取定位器组件名
+func (l *Locator) Name() string {
+	return name
+}
+
+// LocateGate 定位用户所在网关
+func (l *Locator) LocateGate(ctx context.Context, uid int64) (string, error) {
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	return l.gates[uid], nil
+}
+
+// LocateNode 定位用户所在节点
+func (l *Locator) LocateNode(ctx context.Context, uid int64, name string) (string, error) {
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	return l.nodes[uid][name], nil
+}
+
+// LocateNodes 定位用户所在节点列表
+func (l *Locator) LocateNodes(ctx context.Context, uid int64) (map[string]string, error) {
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	nodes := maps.Clone(l.nodes[uid])
+	if nodes == nil {
+		nodes = make(map[string]string)
+	}
+
+	return nodes, nil
+}
+
+// BindGate 绑定网关
+func (l *Locator) BindGate(ctx context.Context, uid int64, gid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	l.gates[uid] = gid
+
+	l.broadcast(locate.BindGate, uid, gid)
+
+	return nil
+}
+
+// BindNode 绑定节点
+func (l *Locator) BindNode(ctx context.Context, uid int64, name, nid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	nodes, ok := l.nodes[uid]
+	if !ok {
+		nodes = make(map[string]string)
+		l.nodes[uid] = nodes
+	}
+
+	nodes[name] = nid
+
+	l.broadcast(locate.BindNode, uid, nid, name)
+
+	return nil
+}
+
+// UnbindGate 解绑网关；仅当用户当前绑定的网关与gid一致时才会解绑
+func (l *Locator) UnbindGate(ctx context.Context, uid int64, gid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	if l.gates[uid] != gid {
+		return nil
+	}
+
+	delete(l.gates, uid)
+
+	l.broadcast(locate.UnbindGate, uid, gid)
+
+	return nil
+}
+
+// UnbindNode 解绑节点；仅当用户当前绑定的节点与nid一致时才会解绑
+func (l *Locator) UnbindNode(ctx context.Context, uid int64, name, nid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	nodes, ok := l.nodes[uid]
+	if !ok || nodes[name] != nid {
+		return nil
+	}
+
+	delete(nodes, name)
+
+	if len(nodes) == 0 {
+		delete(l.nodes, uid)
+	}
+
+	l.broadcast(locate.UnbindNode, uid, nid, name)
+
+	return nil
+}
+
+// LocateActor 定位Actor所在节点
+func (l *Locator) LocateActor(ctx context.Context, pid string) (string, error) {
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	return l.actors[pid], nil
+}
+
+// BindActor 绑定Actor所在节点
+func (l *Locator) BindActor(ctx context.Context, pid, nid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	l.actors[pid] = nid
+
+	return nil
+}
+
+// UnbindActor 解绑Actor所在节点；仅当Actor当前绑定的节点与nid一致时才会解绑
+func (l *Locator) UnbindActor(ctx context.Context, pid, nid string) error {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	if l.actors[pid] == nid {
+		delete(l.actors, pid)
+	}
+
+	return nil
+}
+
+// Watch 监听用户定位变化；kinds为空时监听所有类型的实例，监听器的生命周期与ctx无关，需调用Stop停止监听
+func (l *Locator) Watch(ctx context.Context, kinds ...string) (locate.Watcher, error) {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	l.idx++
+
+	w := newWatcher(l, l.idx, kinds...)
+
+	l.watchers[w.idx] = w
+
+	return w, nil
+}
+
+// 回收监听器
+func (l *Locator) recycle(idx int64) {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	delete(l.watchers, idx)
+}
+
+// 广播事件；调用方需持有写锁
+func (l *Locator) broadcast(typ locate.EventType, uid int64, insID string, insName ...string) {
+	if len(l.watchers) == 0 {
+		return
+	}
+
+	evt := &locate.Event{UID: uid, Type: typ, InsID: insID}
+
+	switch typ {
+	case locate.BindGate, locate.UnbindGate:
+		evt.InsKind = cluster.Gate.String()
+	case locate.BindNode, locate.UnbindNode:
+		evt.InsKind = cluster.Node.String()
+	}
+
+	if len(insName) > 0 {
+		evt.InsName = insName[0]
+	}
+
+	for _, w := range l.watchers {
+		w.notify(evt)
+	}
+}
@@ -0,0 +1,119 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/locate"
+	"github.com/dobyte/due/v2/locate/memory"
+)
+
+func TestLocator_Bind(t *testing.T) {
+	ctx := context.Background()
+	locator := memory.NewLocator()
+
+	if err := locator.BindGate(ctx, 1, "gate-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := locator.BindNode(ctx, 1, "node", "node-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if gid, _ := locator.LocateGate(ctx, 1); gid != "gate-1" {
+		t.Fatalf("unexpected gate: %s", gid)
+	}
+
+	if nid, _ := locator.LocateNode(ctx, 1, "node"); nid != "node-1" {
+		t.Fatalf("unexpected node: %s", nid)
+	}
+
+	// 解绑非当前绑定的实例不生效
+	_ = locator.UnbindGate(ctx, 1, "gate-2")
+	_ = locator.UnbindNode(ctx, 1, "node", "node-2")
+
+	if gid, _ := locator.LocateGate(ctx, 1); gid != "gate-1" {
+		t.Fatal("gate should not be unbound by another gate")
+	}
+
+	if nodes, _ := locator.LocateNodes(ctx, 1); len(nodes) != 1 {
+		t.Fatal("node should not be unbound by another node")
+	}
+
+	_ = locator.UnbindGate(ctx, 1, "gate-1")
+	_ = locator.UnbindNode(ctx, 1, "node", "node-1")
+
+	if gid, _ := locator.LocateGate(ctx, 1); gid != "" {
+		t.Fatal("unbind gate failed")
+	}
+
+	if nodes, _ := locator.LocateNodes(ctx, 1); len(nodes) != 0 {
+		t.Fatal("unbind node failed")
+	}
+}
+
+func TestLocator_BindActor(t *testing.T) {
+	ctx := context.Background()
+	locator := memory.NewLocator()
+
+	_ = locator.BindActor(ctx, "room/1", "node-1")
+	_ = locator.UnbindActor(ctx, "room/1", "node-2")
+
+	if nid, _ := locator.LocateActor(ctx, "room/1"); nid != "node-1" {
+		t.Fatal("actor should not be unbound by another node")
+	}
+
+	_ = locator.UnbindActor(ctx, "room/1", "node-1")
+
+	if nid, _ := locator.LocateActor(ctx, "room/1"); nid != "" {
+		t.Fatal("unbind actor failed")
+	}
+}
+
+func TestLocator_Watch(t *testing.T) {
+	ctx := context.Background()
+	locator := memory.NewLocator()
+
+	watcher, err := locator.Watch(ctx, cluster.Gate.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	_ = locator.BindNode(ctx, 1, "node", "node-1")
+	_ = locator.BindGate(ctx, 1, "gate-1")
+	_ = locator.UnbindGate(ctx, 1, "gate-1")
+
+	var events []*locate.Event
+
+	for len(events) < 2 {
+		ch := make(chan []*locate.Event, 1)
+
+		go func() {
+			evts, err := watcher.Next()
+			if err != nil {
+				t.Error(err)
+			}
+			ch <- evts
+		}()
+
+		select {
+		case evts := <-ch:
+			events = append(events, evts...)
+		case <-time.After(time.Second):
+			t.Fatal("watcher next timeout")
+		}
+	}
+
+	if len(events) != 2 || events[0].Type != locate.BindGate || events[1].Type != locate.UnbindGate {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	for _, evt := range events {
+		if evt.InsKind != cluster.Gate.String() || evt.UID != 1 || evt.InsID != "gate-1" {
+			t.Fatalf("unexpected event: %+v", evt)
+		}
+	}
+}
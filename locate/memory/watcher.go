@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/locate"
+)
+
+type watcher struct {
+	idx      int64
+	ctx      context.Context
+	cancel   context.CancelFunc
+	locator  *Locator
+	kinds    []string
+	rw       sync.Mutex
+	stopped  bool
+	events   []*locate.Event // 尚未被消费的事件列表
+	chNotify chan struct{}
+}
+
+func newWatcher(l *Locator, idx int64, kinds ...string) *watcher {
+	w := &watcher{}
+	w.idx = idx
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.locator = l
+	w.kinds = kinds
+	w.chNotify = make(chan struct{}, 1)
+
+	return w
+}
+
+// 通知事件；事件将暂存至下一次调用Next，不会阻塞绑定流程
+func (w *watcher) notify(evt *locate.Event) {
+	if len(w.kinds) > 0 && !slices.Contains(w.kinds, evt.InsKind) {
+		return
+	}
+
+	w.rw.Lock()
+	defer w.rw.Unlock()
+
+	if w.stopped {
+		return
+	}
+
+	w.events = append(w.events, evt)
+
+	select {
+	case w.chNotify <- struct{}{}:
+	default:
+	}
+}
+
+// Next 返回变动事件列表
+func (w *watcher) Next() ([]*locate.Event, error) {
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-w.chNotify:
+			w.rw.Lock()
+			events := w.events
+			w.events = nil
+			w.rw.Unlock()
+
+			if len(events) == 0 {
+				continue
+			}
+
+			return events, nil
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.rw.Lock()
+	if w.stopped {
+		w.rw.Unlock()
+		return errors.ErrIllegalOperation
+	}
+
+	w.stopped = true
+	w.events = nil
+	w.rw.Unlock()
+
+	w.cancel()
+	w.locator.recycle(w.idx)
+
+	return nil
+}
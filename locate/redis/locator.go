@@ -20,6 +20,7 @@ import (
 const (
 	userGateKey     = "%s:locate:user:%d:gate"     // string
 	userNodeKey     = "%s:locate:user:%d:node"     // hash
+	actorNodeKey    = "%s:locate:actor:%s:node"    // string
 	clusterEventKey = "%s:locate:cluster:%s:event" // channel
 )
 
@@ -28,15 +29,16 @@ const name = "redis"
 var _ locate.Locator = &Locator{}
 
 type Locator struct {
-	err              error
-	opts             *options
-	builtin          bool
-	ctx              context.Context
-	cancel           context.CancelFunc
-	sfg              singleflight.Group
-	watchers         sync.Map
-	unbindGateScript *redis.Script
-	unbindNodeScript *redis.Script
+	err               error
+	opts              *options
+	builtin           bool
+	ctx               context.Context
+	cancel            context.CancelFunc
+	sfg               singleflight.Group
+	watchers          sync.Map
+	unbindGateScript  *redis.Script
+	unbindNodeScript  *redis.Script
+	unbindActorScript *redis.Script
 }
 
 func NewLocator(opts ...Option) *Locator {
@@ -53,6 +55,7 @@ func NewLocator(opts ...Option) *Locator {
 			l.ctx, l.cancel = context.WithCancel(o.ctx)
 			l.unbindGateScript = redis.NewScript(unbindGateScript)
 			l.unbindNodeScript = redis.NewScript(unbindNodeScript)
+			l.unbindActorScript = redis.NewScript(unbindActorScript)
 		}
 	}()
 
@@ -233,6 +236,51 @@ func (l *Locator) UnbindNode(ctx context.Context, uid int64, name, nid string) e
 	return nil
 }
 
+// LocateActor 定位Actor所在节点
+func (l *Locator) LocateActor(ctx context.Context, pid string) (string, error) {
+	if l.err != nil {
+		return "", l.err
+	}
+
+	key := fmt.Sprintf(actorNodeKey, l.opts.prefix, pid)
+
+	val, err, _ := l.sfg.Do(key, func() (any, error) {
+		val, err := l.opts.client.Get(ctx, key).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return "", err
+		}
+
+		return val, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return val.(string), nil
+}
+
+// BindActor 绑定Actor所在节点
+func (l *Locator) BindActor(ctx context.Context, pid, nid string) error {
+	if l.err != nil {
+		return l.err
+	}
+
+	key := fmt.Sprintf(actorNodeKey, l.opts.prefix, pid)
+
+	return l.opts.client.Set(ctx, key, nid, redis.KeepTTL).Err()
+}
+
+// UnbindActor 解绑Actor所在节点
+func (l *Locator) UnbindActor(ctx context.Context, pid, nid string) error {
+	if l.err != nil {
+		return l.err
+	}
+
+	key := fmt.Sprintf(actorNodeKey, l.opts.prefix, pid)
+
+	return l.unbindActorScript.Run(ctx, l.opts.client, []string{key}, nid).Err()
+}
+
 // 广播事件
 func (l *Locator) broadcast(ctx context.Context, typ locate.EventType, uid int64, insID string, insName ...string) error {
 	evt := &locate.Event{UID: uid, Type: typ, InsID: insID}
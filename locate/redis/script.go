@@ -25,3 +25,16 @@ const unbindNodeScript = `
 
 	return {'OK'}
 `
+
+// 解绑Actor脚本
+const unbindActorScript = `
+	local val = redis.call('GET', KEYS[1])
+
+	if val == '' or val ~= ARGV[1] then
+		return {'NO'}
+	end
+
+	redis.call('DEL', KEYS[1])
+
+	return {'OK'}
+`
@@ -0,0 +1,110 @@
+package metrics
+
+import "strconv"
+
+// 框架内置指标，设置指标提供者后自动生效
+var (
+	// GateConnections 网关当前连接数
+	GateConnections = NewGauge(Opts{
+		Name:   "due_gate_connections",
+		Help:   "The number of client connections currently held by the gate.",
+		Labels: []string{"gate"},
+	})
+
+	// GateDeliverDuration 网关投递消息到节点的耗时（秒）
+	GateDeliverDuration = NewHistogram(Opts{
+		Name:   "due_gate_deliver_duration_seconds",
+		Help:   "The latency of delivering client messages from the gate to nodes.",
+		Labels: []string{"route", "result"},
+	})
+
+	// NodeHandleDuration 节点处理路由消息的耗时（秒）
+	NodeHandleDuration = NewHistogram(Opts{
+		Name:   "due_node_handle_duration_seconds",
+		Help:   "The latency of handling route messages in the node.",
+		Labels: []string{"route"},
+	})
+
+	// NodeActorMailboxDepth 投递消息时Actor邮箱中待处理的消息数
+	NodeActorMailboxDepth = NewHistogram(Opts{
+		Name:    "due_node_actor_mailbox_depth",
+		Help:    "The number of pending messages in the actor mailbox when a message is posted.",
+		Labels:  []string{"kind"},
+		Buckets: []float64{0, 1, 4, 16, 64, 256, 1024, 4096},
+	})
+
+	// NodeActorMailboxDropped Actor邮箱已满时丢弃的消息数
+	NodeActorMailboxDropped = NewCounter(Opts{
+		Name:   "due_node_actor_mailbox_dropped_total",
+		Help:   "The number of messages dropped because the actor mailbox is full.",
+		Labels: []string{"kind"},
+	})
+
+	// MeshHandleDuration 微服务处理调用的耗时（秒）
+	MeshHandleDuration = NewHistogram(Opts{
+		Name:   "due_mesh_handle_duration_seconds",
+		Help:   "The latency of handling mesh service calls.",
+		Labels: []string{"service", "method", "result"},
+	})
+
+	// LinkRPCRetries 内部RPC调用因目标实例变更而重试的次数
+	LinkRPCRetries = NewCounter(Opts{
+		Name:   "due_link_rpc_retries_total",
+		Help:   "The number of internal rpc calls retried after the target instance changed.",
+		Labels: []string{"target"},
+	})
+
+	// LinkBreakerTransitions 内部RPC熔断器状态切换的次数
+	LinkBreakerTransitions = NewCounter(Opts{
+		Name:   "due_link_breaker_transitions_total",
+		Help:   "The number of internal rpc circuit breaker state transitions.",
+		Labels: []string{"target", "state"},
+	})
+
+	// LinkBatchFlushFailures 节点批量推送到网关时异步发送失败的次数，此类错误无法返回给调用方
+	LinkBatchFlushFailures = NewCounter(Opts{
+		Name:   "due_link_batch_flush_failures_total",
+		Help:   "The number of batched pushes that failed to be flushed asynchronously to the gate.",
+		Labels: []string{"trigger"},
+	})
+
+	// NetworkWriteQueueDepth 写入消息时网络连接写入队列中待发送的消息数
+	NetworkWriteQueueDepth = NewHistogram(Opts{
+		Name:    "due_network_write_queue_depth",
+		Help:    "The number of pending messages in the connection write queue when a message is pushed.",
+		Labels:  []string{"protocol"},
+		Buckets: []float64{0, 1, 4, 16, 64, 256, 1024, 4096},
+	})
+
+	// NetworkWriteQueueFull 网络连接写入队列已满的次数
+	NetworkWriteQueueFull = NewCounter(Opts{
+		Name:   "due_network_write_queue_full_total",
+		Help:   "The number of times a message was pushed to a full connection write queue.",
+		Labels: []string{"protocol"},
+	})
+)
+
+const (
+	ResultOK    = "ok"    // 成功
+	ResultError = "error" // 失败
+)
+
+const RouteUnknown = "unknown" // 未注册的路由
+
+// Result 获取结果标签值
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+
+	return ResultOK
+}
+
+// Route 获取路由标签值，未注册的路由统一归为unknown，避免客户端伪造路由导致指标序列无限增长
+func Route(route int32, known bool) string {
+	if !known {
+		return RouteUnknown
+	}
+
+	return strconv.Itoa(int(route))
+}
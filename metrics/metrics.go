@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/log"
+)
+
+var (
+	mu             sync.Mutex
+	globalProvider Provider
+	instruments    []instrument
+)
+
+type Provider interface {
+	// Name 名称
+	Name() string
+	// NewCounter 新建计数器
+	NewCounter(opts Opts) Counter
+	// NewGauge 新建仪表盘
+	NewGauge(opts Opts) Gauge
+	// NewHistogram 新建直方图
+	NewHistogram(opts Opts) Histogram
+	// Handler 指标暴露处理器
+	Handler() http.Handler
+	// Close 关闭指标提供者
+	Close() error
+}
+
+type Opts struct {
+	Name    string    // 指标名称
+	Help    string    // 指标说明
+	Labels  []string  // 标签名称
+	Buckets []float64 // 直方图桶，仅直方图有效
+}
+
+type Counter interface {
+	// Add 增加计数，values为标签值，需与标签名称一一对应
+	Add(delta float64, values ...string)
+}
+
+type Gauge interface {
+	// Set 设置值
+	Set(value float64, values ...string)
+	// Add 增加值，delta可为负数
+	Add(delta float64, values ...string)
+}
+
+type Histogram interface {
+	// Observe 观测值
+	Observe(value float64, values ...string)
+}
+
+type instrument interface {
+	bind(provider Provider)
+}
+
+// SetProvider 设置指标提供者，已声明的指标将绑定到新的指标提供者上
+func SetProvider(provider Provider) {
+	if provider == nil {
+		log.Warn("cannot set a nil metrics provider")
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if globalProvider != nil {
+		if err := globalProvider.Close(); err != nil {
+			log.Errorf("close metrics provider failed: %v", err)
+		}
+	}
+
+	globalProvider = provider
+
+	for _, i := range instruments {
+		i.bind(provider)
+	}
+}
+
+// GetProvider 获取指标提供者
+func GetProvider() Provider {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return globalProvider
+}
+
+// Handler 获取指标暴露处理器，未设置指标提供者时返回404
+func Handler() http.Handler {
+	if provider := GetProvider(); provider != nil {
+		return provider.Handler()
+	}
+
+	return http.NotFoundHandler()
+}
+
+// NewCounter 声明计数器，未设置指标提供者时不进行任何统计
+func NewCounter(opts Opts) Counter {
+	c := &counter{opts: opts}
+
+	register(c)
+
+	return c
+}
+
+// NewGauge 声明仪表盘，未设置指标提供者时不进行任何统计
+func NewGauge(opts Opts) Gauge {
+	g := &gauge{opts: opts}
+
+	register(g)
+
+	return g
+}
+
+// NewHistogram 声明直方图，未设置指标提供者时不进行任何统计
+func NewHistogram(opts Opts) Histogram {
+	h := &histogram{opts: opts}
+
+	register(h)
+
+	return h
+}
+
+// 注册指标
+func register(i instrument) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	instruments = append(instruments, i)
+
+	if globalProvider != nil {
+		i.bind(globalProvider)
+	}
+}
+
+type counter struct {
+	opts Opts
+	impl atomic.Pointer[Counter]
+}
+
+func (c *counter) bind(provider Provider) {
+	impl := provider.NewCounter(c.opts)
+	c.impl.Store(&impl)
+}
+
+// Add 增加计数
+func (c *counter) Add(delta float64, values ...string) {
+	if impl := c.impl.Load(); impl != nil {
+		(*impl).Add(delta, values...)
+	}
+}
+
+type gauge struct {
+	opts Opts
+	impl atomic.Pointer[Gauge]
+}
+
+func (g *gauge) bind(provider Provider) {
+	impl := provider.NewGauge(g.opts)
+	g.impl.Store(&impl)
+}
+
+// Set 设置值
+func (g *gauge) Set(value float64, values ...string) {
+	if impl := g.impl.Load(); impl != nil {
+		(*impl).Set(value, values...)
+	}
+}
+
+// Add 增加值
+func (g *gauge) Add(delta float64, values ...string) {
+	if impl := g.impl.Load(); impl != nil {
+		(*impl).Add(delta, values...)
+	}
+}
+
+type histogram struct {
+	opts Opts
+	impl atomic.Pointer[Histogram]
+}
+
+func (h *histogram) bind(provider Provider) {
+	impl := provider.NewHistogram(h.opts)
+	h.impl.Store(&impl)
+}
+
+// Observe 观测值
+func (h *histogram) Observe(value float64, values ...string) {
+	if impl := h.impl.Load(); impl != nil {
+		(*impl).Observe(value, values...)
+	}
+}
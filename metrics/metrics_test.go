@@ -0,0 +1,102 @@
+package metrics_test
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dobyte/due/v2/metrics"
+)
+
+type provider struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (p *provider) Name() string { return "test" }
+
+func (p *provider) NewCounter(opts metrics.Opts) metrics.Counter {
+	return &instrument{provider: p, name: opts.Name}
+}
+
+func (p *provider) NewGauge(opts metrics.Opts) metrics.Gauge {
+	return &instrument{provider: p, name: opts.Name}
+}
+
+func (p *provider) NewHistogram(opts metrics.Opts) metrics.Histogram {
+	return &instrument{provider: p, name: opts.Name}
+}
+
+func (p *provider) Handler() http.Handler { return http.NotFoundHandler() }
+
+func (p *provider) Close() error { return nil }
+
+func (p *provider) value(name string, values ...string) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.values[name+"{"+strings.Join(values, ",")+"}"]
+}
+
+type instrument struct {
+	provider *provider
+	name     string
+}
+
+func (i *instrument) Add(delta float64, values ...string) {
+	i.provider.mu.Lock()
+	defer i.provider.mu.Unlock()
+
+	i.provider.values[i.name+"{"+strings.Join(values, ",")+"}"] += delta
+}
+
+func (i *instrument) Set(value float64, values ...string) {
+	i.provider.mu.Lock()
+	defer i.provider.mu.Unlock()
+
+	i.provider.values[i.name+"{"+strings.Join(values, ",")+"}"] = value
+}
+
+func (i *instrument) Observe(value float64, values ...string) {
+	i.Add(value, values...)
+}
+
+func TestSetProvider(t *testing.T) {
+	counter := metrics.NewCounter(metrics.Opts{Name: "test_counter", Labels: []string{"kind"}})
+
+	counter.Add(1, "a")
+
+	p := &provider{values: make(map[string]float64)}
+
+	metrics.SetProvider(p)
+
+	counter.Add(2, "a")
+	metrics.LinkRPCRetries.Add(1, "node")
+
+	if v := p.value("test_counter", "a"); v != 2 {
+		t.Fatalf("counter value = %v, want 2", v)
+	}
+
+	if v := p.value("due_link_rpc_retries_total", "node"); v != 1 {
+		t.Fatalf("retries value = %v, want 1", v)
+	}
+
+	gauge := metrics.NewGauge(metrics.Opts{Name: "test_gauge"})
+	gauge.Set(5)
+	gauge.Add(-2)
+
+	if v := p.value("test_gauge"); v != 3 {
+		t.Fatalf("gauge value = %v, want 3", v)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	if v := metrics.Route(1, true); v != "1" {
+		t.Fatalf("route label = %s, want 1", v)
+	}
+
+	if v := metrics.Route(999, false); v != metrics.RouteUnknown {
+		t.Fatalf("route label = %s, want %s", v, metrics.RouteUnknown)
+	}
+}
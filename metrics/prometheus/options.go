@@ -0,0 +1,66 @@
+package prometheus
+
+import (
+	"github.com/dobyte/due/v2/etc"
+	"github.com/dobyte/due/v2/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultGoCollectorKey      = "etc.metrics.prometheus.goCollector"
+	defaultProcessCollectorKey = "etc.metrics.prometheus.processCollector"
+	defaultConstLabelsKey      = "etc.metrics.prometheus.constLabels"
+)
+
+type Option func(o *options)
+
+type options struct {
+	// 是否采集Go运行时指标
+	// 默认为true
+	goCollector bool
+
+	// 是否采集进程指标
+	// 默认为true
+	processCollector bool
+
+	// 常量标签，会附加到所有指标上，可用于区分集群、环境等
+	// 默认为空
+	constLabels prometheus.Labels
+
+	// 自定义注册器
+	// 默认为新建的注册器
+	registry *prometheus.Registry
+}
+
+func defaultOptions() *options {
+	opts := &options{
+		goCollector:      etc.Get(defaultGoCollectorKey, true).Bool(),
+		processCollector: etc.Get(defaultProcessCollectorKey, true).Bool(),
+	}
+
+	if err := etc.Get(defaultConstLabelsKey).Scan(&opts.constLabels); err != nil {
+		log.Warnf("the constLabels config is invalid and will be ignored: %v", err)
+	}
+
+	return opts
+}
+
+// WithGoCollector 设置是否采集Go运行时指标
+func WithGoCollector(enable bool) Option {
+	return func(o *options) { o.goCollector = enable }
+}
+
+// WithProcessCollector 设置是否采集进程指标
+func WithProcessCollector(enable bool) Option {
+	return func(o *options) { o.processCollector = enable }
+}
+
+// WithConstLabels 设置常量标签
+func WithConstLabels(labels map[string]string) Option {
+	return func(o *options) { o.constLabels = labels }
+}
+
+// WithRegistry 设置自定义注册器
+func WithRegistry(registry *prometheus.Registry) Option {
+	return func(o *options) { o.registry = registry }
+}
@@ -0,0 +1,142 @@
+package prometheus
+
+import (
+	"net/http"
+
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const Name = "prometheus"
+
+var _ metrics.Provider = &Provider{}
+
+type Provider struct {
+	opts     *options
+	registry *prometheus.Registry
+	handler  http.Handler
+}
+
+func NewProvider(opts ...Option) *Provider {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	p := &Provider{opts: o}
+
+	if o.registry != nil {
+		p.registry = o.registry
+	} else {
+		p.registry = prometheus.NewRegistry()
+	}
+
+	if o.goCollector {
+		p.register(collectors.NewGoCollector())
+	}
+
+	if o.processCollector {
+		p.register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
+
+	p.handler = promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+
+	return p
+}
+
+// Name 名称
+func (p *Provider) Name() string {
+	return Name
+}
+
+// NewCounter 新建计数器
+func (p *Provider) NewCounter(opts metrics.Opts) metrics.Counter {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        opts.Name,
+		Help:        opts.Help,
+		ConstLabels: p.opts.constLabels,
+	}, opts.Labels)
+
+	return &counter{vec: p.register(vec).(*prometheus.CounterVec)}
+}
+
+// NewGauge 新建仪表盘
+func (p *Provider) NewGauge(opts metrics.Opts) metrics.Gauge {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        opts.Name,
+		Help:        opts.Help,
+		ConstLabels: p.opts.constLabels,
+	}, opts.Labels)
+
+	return &gauge{vec: p.register(vec).(*prometheus.GaugeVec)}
+}
+
+// NewHistogram 新建直方图
+func (p *Provider) NewHistogram(opts metrics.Opts) metrics.Histogram {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:        opts.Name,
+		Help:        opts.Help,
+		ConstLabels: p.opts.constLabels,
+		Buckets:     opts.Buckets,
+	}, opts.Labels)
+
+	return &histogram{vec: p.register(vec).(*prometheus.HistogramVec)}
+}
+
+// Handler 指标暴露处理器
+func (p *Provider) Handler() http.Handler {
+	return p.handler
+}
+
+// Close 关闭指标提供者
+func (p *Provider) Close() error {
+	return nil
+}
+
+// 注册采集器，已注册相同的采集器时返回已注册的采集器
+func (p *Provider) register(c prometheus.Collector) prometheus.Collector {
+	if err := p.registry.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+
+		log.Errorf("register prometheus collector failed: %v", err)
+	}
+
+	return c
+}
+
+type counter struct {
+	vec *prometheus.CounterVec
+}
+
+// Add 增加计数
+func (c *counter) Add(delta float64, values ...string) {
+	c.vec.WithLabelValues(values...).Add(delta)
+}
+
+type gauge struct {
+	vec *prometheus.GaugeVec
+}
+
+// Set 设置值
+func (g *gauge) Set(value float64, values ...string) {
+	g.vec.WithLabelValues(values...).Set(value)
+}
+
+// Add 增加值
+func (g *gauge) Add(delta float64, values ...string) {
+	g.vec.WithLabelValues(values...).Add(delta)
+}
+
+type histogram struct {
+	vec *prometheus.HistogramVec
+}
+
+// Observe 观测值
+func (h *histogram) Observe(value float64, values ...string) {
+	h.vec.WithLabelValues(values...).Observe(value)
+}
@@ -0,0 +1,36 @@
+package prometheus_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dobyte/due/metrics/prometheus/v2"
+	"github.com/dobyte/due/v2/metrics"
+)
+
+func TestProvider(t *testing.T) {
+	metrics.SetProvider(prometheus.NewProvider(prometheus.WithGoCollector(false), prometheus.WithProcessCollector(false)))
+
+	metrics.GateConnections.Add(2, "gate-1")
+	metrics.GateDeliverDuration.Observe(0.01, "1", metrics.ResultOK)
+
+	rec := httptest.NewRecorder()
+
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		`due_gate_connections{gate="gate-1"} 2`,
+		`due_gate_deliver_duration_seconds_count{result="ok",route="1"} 1`,
+	} {
+		if !strings.Contains(string(body), s) {
+			t.Fatalf("missing metric %s in:\n%s", s, body)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/network"
+)
+
+type client struct {
+	opts              *clientOptions            // 配置
+	id                int64                     // 连接ID
+	httpClient        *http.Client              // HTTP客户端
+	connectHandler    network.ConnectHandler    // 连接打开hook函数
+	disconnectHandler network.DisconnectHandler // 连接关闭hook函数
+	receiveHandler    network.ReceiveHandler    // 接收消息hook函数
+}
+
+var _ network.Client = &client{}
+
+func NewClient(opts ...ClientOption) network.Client {
+	o := defaultClientOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &client{opts: o, httpClient: &http.Client{}}
+}
+
+// Dial 拨号连接
+func (c *client) Dial(addr ...string) (network.Conn, error) {
+	var url string
+
+	if len(addr) > 0 && addr[0] != "" {
+		url = addr[0]
+	} else {
+		url = c.opts.url
+	}
+
+	url = strings.TrimSuffix(url, "/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.dialTimeout)
+	defer cancel()
+
+	var localAddr, remoteAddr net.Addr
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			localAddr, remoteAddr = info.Conn.LocalAddr(), info.Conn.RemoteAddr()
+		},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+openPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.NewError(errors.ErrConnectionNotOpened, resp.Status)
+	}
+
+	sid, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClientConn(atomic.AddInt64(&c.id, 1), string(sid), url, localAddr, remoteAddr, c), nil
+}
+
+// Protocol 协议
+func (c *client) Protocol() string {
+	return protocol
+}
+
+// OnConnect 监听连接打开
+func (c *client) OnConnect(handler network.ConnectHandler) {
+	c.connectHandler = handler
+}
+
+// OnDisconnect 监听连接关闭
+func (c *client) OnDisconnect(handler network.DisconnectHandler) {
+	c.disconnectHandler = handler
+}
+
+// OnReceive 监听接收到消息
+func (c *client) OnReceive(handler network.ReceiveHandler) {
+	c.receiveHandler = handler
+}
@@ -0,0 +1,623 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/dobyte/due/v2/utils/xnet"
+	"github.com/dobyte/due/v2/utils/xtime"
+)
+
+type clientConn struct {
+	rw                    sync.RWMutex       // 锁
+	id                    int64              // 连接ID
+	sid                   string             // 会话ID
+	url                   string             // 服务地址
+	uid                   atomic.Int64       // 用户ID
+	attr                  *attr              // 连接属性
+	state                 atomic.Int32       // 连接状态
+	closed                bool               // 是否已关闭
+	client                *client            // 客户端
+	localAddr             net.Addr           // 本地地址
+	remoteAddr            net.Addr           // 远端地址
+	ctx                   context.Context    // 请求上下文
+	cancel                context.CancelFunc // 请求取消函数
+	taskPool              sync.Pool          // 任务对象池
+	lowPriorityTaskQueue  chan *task         // 低优先级队列
+	highPriorityTaskQueue chan *task         // 高优先级队列
+	lastHeartbeatTime     atomic.Int64       // 上次心跳时间
+	done                  chan struct{}      // 写入完成信号
+	close                 chan struct{}      // 关闭信号
+}
+
+var _ network.Conn = &clientConn{}
+
+func newClientConn(id int64, sid, url string, localAddr, remoteAddr net.Addr, client *client) network.Conn {
+	c := &clientConn{
+		id:                    id,
+		sid:                   sid,
+		url:                   url,
+		attr:                  &attr{},
+		client:                client,
+		localAddr:             localAddr,
+		remoteAddr:            remoteAddr,
+		taskPool:              sync.Pool{New: func() any { return &task{} }},
+		lowPriorityTaskQueue:  make(chan *task, client.opts.writeQueueSize),
+		highPriorityTaskQueue: make(chan *task, client.opts.writeQueueSize),
+		done:                  make(chan struct{}, 1),
+		close:                 make(chan struct{}),
+	}
+
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.state.Store(int32(network.ConnOpened))
+	c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+
+	if client.opts.transport == SSETransport {
+		xcall.Go(c.stream)
+	} else {
+		xcall.Go(c.poll)
+	}
+
+	xcall.Go(c.write)
+
+	if c.client.connectHandler != nil {
+		c.client.connectHandler(c)
+	}
+
+	return c
+}
+
+// ID 获取连接ID
+func (c *clientConn) ID() int64 {
+	return c.id
+}
+
+// UID 获取用户ID
+func (c *clientConn) UID() int64 {
+	return c.uid.Load()
+}
+
+// Attr 获取属性接口
+func (c *clientConn) Attr() network.Attr {
+	return c.attr
+}
+
+// Bind 绑定用户ID
+func (c *clientConn) Bind(uid int64) {
+	c.uid.Store(uid)
+}
+
+// Unbind 解绑用户ID
+func (c *clientConn) Unbind() {
+	c.uid.Store(0)
+}
+
+// Send 发送消息（同步）
+func (c *clientConn) Send(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if c.closed {
+		return errors.ErrConnectionClosed
+	}
+
+	return c.doWriteToQueue(c.highPriorityTaskQueue, dataPacket, msg)
+}
+
+// Push 发送消息（异步）
+func (c *clientConn) Push(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if c.closed {
+		return errors.ErrConnectionClosed
+	}
+
+	return c.doWriteToQueue(c.lowPriorityTaskQueue, dataPacket, msg)
+}
+
+// State 获取连接状态
+func (c *clientConn) State() network.ConnState {
+	return network.ConnState(c.state.Load())
+}
+
+// Close 关闭连接（主动关闭）
+func (c *clientConn) Close(force ...bool) error {
+	if len(force) > 0 && force[0] {
+		return c.forceClose(true)
+	} else {
+		return c.graceClose()
+	}
+}
+
+// LocalIP 获取本地IP
+func (c *clientConn) LocalIP() (string, error) {
+	addr, err := c.LocalAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// LocalAddr 获取本地地址
+func (c *clientConn) LocalAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	if c.localAddr == nil {
+		return nil, errors.ErrNotFoundIPAddress
+	}
+
+	return c.localAddr, nil
+}
+
+// RemoteIP 获取远端IP
+func (c *clientConn) RemoteIP() (string, error) {
+	addr, err := c.RemoteAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// RemoteAddr 获取远端地址
+func (c *clientConn) RemoteAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	if c.remoteAddr == nil {
+		return nil, errors.ErrNotFoundIPAddress
+	}
+
+	return c.remoteAddr, nil
+}
+
+// 检测连接状态
+func (c *clientConn) checkState() error {
+	switch c.State() {
+	case network.ConnHanged:
+		return errors.ErrConnectionHanged
+	case network.ConnClosed:
+		return errors.ErrConnectionClosed
+	default:
+		return nil
+	}
+}
+
+// 优雅关闭
+func (c *clientConn) graceClose() error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnHanged)) {
+		return errors.ErrConnectionNotOpened
+	}
+
+	c.rw.RLock()
+	if c.closed {
+		c.rw.RUnlock()
+		return errors.ErrConnectionClosed
+	}
+	c.lowPriorityTaskQueue <- &task{typ: closeSig}
+	c.rw.RUnlock()
+
+	select {
+	case <-c.done:
+	case <-c.close:
+	}
+
+	if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+		return errors.ErrConnectionNotHanged
+	}
+
+	return c.doClose(true)
+}
+
+// 强制关闭
+// isNeedNotify 是否需要通知服务端关闭会话；会话已被服务端关闭时无需通知
+func (c *clientConn) forceClose(isNeedNotify bool) error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnClosed)) {
+		if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+			return errors.ErrConnectionClosed
+		}
+	}
+
+	return c.doClose(isNeedNotify)
+}
+
+// 执行关闭操作
+func (c *clientConn) doClose(isNeedNotify bool) error {
+	c.rw.Lock()
+
+	if c.closed {
+		c.rw.Unlock()
+		return errors.ErrConnectionClosed
+	}
+
+	close(c.lowPriorityTaskQueue)
+	close(c.highPriorityTaskQueue)
+	close(c.close)
+	c.closed = true
+	c.cancel()
+	c.rw.Unlock()
+
+	var err error
+
+	if isNeedNotify {
+		ctx, cancel := context.WithTimeout(context.Background(), c.client.opts.dialTimeout)
+		defer cancel()
+
+		var resp *http.Response
+		if resp, err = c.doRequest(ctx, http.MethodPost, closePath, nil); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	if c.client.disconnectHandler != nil {
+		c.client.disconnectHandler(c)
+	}
+
+	return err
+}
+
+// 长轮询读取消息
+func (c *clientConn) poll() {
+	for {
+		select {
+		case <-c.close:
+			return
+		default:
+			resp, err := c.doRequest(c.ctx, http.MethodGet, pollPath, nil)
+			if err != nil {
+				if c.ctx.Err() == nil {
+					log.Warnf("poll message failed: %v", err)
+					_ = c.forceClose(false)
+				}
+				return
+			}
+
+			switch resp.StatusCode {
+			case http.StatusOK:
+				c.refreshHeartbeat()
+
+				for {
+					msg, err := packet.ReadMessage(resp.Body)
+					if err != nil {
+						if !errors.Is(err, io.EOF) && c.ctx.Err() == nil {
+							log.Warnf("read message failed: %v", err)
+						}
+						break
+					}
+
+					c.doReceive(msg)
+				}
+			case http.StatusNoContent:
+				c.refreshHeartbeat()
+			case http.StatusGone:
+				resp.Body.Close()
+				_ = c.forceClose(false)
+				return
+			default:
+				log.Warnf("poll message failed: %s", resp.Status)
+				resp.Body.Close()
+				_ = c.forceClose(true)
+				return
+			}
+
+			resp.Body.Close()
+		}
+	}
+}
+
+// SSE读取消息
+func (c *clientConn) stream() {
+	resp, err := c.doRequest(c.ctx, http.MethodGet, eventsPath, nil)
+	if err != nil {
+		if c.ctx.Err() == nil {
+			log.Warnf("open event stream failed: %v", err)
+			_ = c.forceClose(false)
+		}
+		return
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusGone:
+		_ = c.forceClose(false)
+		return
+	default:
+		log.Warnf("open event stream failed: %s", resp.Status)
+		_ = c.forceClose(true)
+		return
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<24)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		c.refreshHeartbeat()
+
+		switch {
+		case line == sseCloseEvent:
+			_ = c.forceClose(false)
+			return
+		case strings.HasPrefix(line, sseDataPrefix):
+			data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, sseDataPrefix))
+			if err != nil {
+				log.Warnf("decode event failed: %v", err)
+				continue
+			}
+
+			reader := bytes.NewReader(data)
+			for {
+				msg, err := packet.ReadMessage(reader)
+				if err != nil {
+					break
+				}
+
+				c.doReceive(msg)
+			}
+		default:
+			// ignore comment and empty line
+		}
+	}
+
+	if c.ctx.Err() == nil {
+		if err = scanner.Err(); err != nil {
+			log.Warnf("read event stream failed: %v", err)
+		}
+
+		_ = c.forceClose(false)
+	}
+}
+
+// 处理接收到的消息
+func (c *clientConn) doReceive(msg []byte) {
+	switch c.State() {
+	case network.ConnHanged, network.ConnClosed:
+		return
+	default:
+		// ignore
+	}
+
+	// ignore empty packet
+	if len(msg) == 0 {
+		return
+	}
+
+	// check heartbeat packet
+	isHeartbeat, err := packet.CheckHeartbeat(msg)
+	if err != nil {
+		log.Errorf("check heartbeat message error: %v", err)
+		return
+	}
+
+	// ignore heartbeat packet
+	if isHeartbeat {
+		return
+	}
+
+	if c.client.receiveHandler != nil {
+		c.client.receiveHandler(c, msg)
+	}
+}
+
+// 写入消息
+// 每次将队列中已就绪的消息合并为一次上行请求
+func (c *clientConn) write() {
+	var ticker *time.Ticker
+
+	if c.client.opts.heartbeatInterval > 0 {
+		ticker = time.NewTicker(c.client.opts.heartbeatInterval)
+		defer ticker.Stop()
+	} else {
+		ticker = &time.Ticker{C: make(chan time.Time, 1)}
+	}
+
+	buf := &bytes.Buffer{}
+
+	for {
+		var t *task
+
+		select {
+		case t = <-c.highPriorityTaskQueue:
+		case tm := <-ticker.C:
+			if !c.doHandleHeartbeat(tm) {
+				return
+			}
+			continue
+		default:
+			select {
+			case t = <-c.highPriorityTaskQueue:
+			case t = <-c.lowPriorityTaskQueue:
+			case tm := <-ticker.C:
+				if !c.doHandleHeartbeat(tm) {
+					return
+				}
+				continue
+			}
+		}
+
+		if t == nil {
+			return
+		}
+
+		buf.Reset()
+
+		isClosing := false
+
+		for i := 0; t != nil; i++ {
+			if !c.doPack(buf, t) {
+				isClosing = true
+				break
+			}
+
+			if i+1 >= maxBatchSize {
+				break
+			}
+
+			select {
+			case t = <-c.highPriorityTaskQueue:
+			default:
+				select {
+				case t = <-c.highPriorityTaskQueue:
+				case t = <-c.lowPriorityTaskQueue:
+				default:
+					t = nil
+				}
+			}
+		}
+
+		if buf.Len() > 0 {
+			c.doWrite(buf.Bytes())
+		}
+
+		if isClosing {
+			select {
+			case c.done <- struct{}{}:
+			default:
+			}
+			return
+		}
+	}
+}
+
+// 打包任务到缓冲区；遇到关闭信号时返回false
+func (c *clientConn) doPack(buf *bytes.Buffer, t *task) bool {
+	if t.typ == closeSig {
+		return false
+	}
+
+	defer c.doRecycleToPool(t)
+
+	switch t.typ {
+	case heartbeatPacket:
+		if msg, err := packet.PackHeartbeat(); err != nil {
+			log.Errorf("pack heartbeat message error: %v", err)
+		} else {
+			buf.Write(msg)
+		}
+	default:
+		buf.Write(t.msg)
+	}
+
+	return true
+}
+
+// 执行上行请求
+func (c *clientConn) doWrite(body []byte) {
+	resp, err := c.doRequest(c.ctx, http.MethodPost, sendPath, bytes.NewReader(body))
+	if err != nil {
+		if c.ctx.Err() == nil {
+			log.Errorf("write message error: %v", err)
+		}
+		return
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+	case http.StatusGone:
+		_ = c.forceClose(false)
+	default:
+		log.Errorf("write message error: %s", resp.Status)
+	}
+}
+
+// 处理心跳
+func (c *clientConn) doHandleHeartbeat(t time.Time) bool {
+	deadline := t.Add(-2 * c.client.opts.heartbeatInterval).UnixNano()
+
+	if c.lastHeartbeatTime.Load() < deadline {
+		log.Debugf("connection heartbeat timeout")
+		_ = c.forceClose(true)
+		return false
+	}
+
+	if heartbeat, err := packet.PackHeartbeat(); err != nil {
+		log.Errorf("pack heartbeat message error: %v", err)
+	} else {
+		c.doWrite(heartbeat)
+	}
+
+	return true
+}
+
+// 执行HTTP请求
+func (c *clientConn) doRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path+"?"+sessionParam+"="+c.sid, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	return c.client.httpClient.Do(req)
+}
+
+// 刷新心跳时间
+func (c *clientConn) refreshHeartbeat() {
+	if c.client.opts.heartbeatInterval > 0 {
+		c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+	}
+}
+
+// 回收任务到对象池
+func (c *clientConn) doRecycleToPool(t *task) {
+	t.msg = nil
+	c.taskPool.Put(t)
+}
+
+// 写入任务到队列
+func (c *clientConn) doWriteToQueue(queue chan *task, typ int8, msg ...[]byte) error {
+	t := c.taskPool.Get().(*task)
+	t.typ = typ
+	if len(msg) > 0 {
+		t.msg = msg[0]
+	}
+
+	if c.client.opts.writeTimeout > 0 && len(queue) == cap(queue) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.client.opts.writeTimeout)
+		defer cancel()
+
+		select {
+		case <-ctx.Done():
+			c.doRecycleToPool(t)
+			return ctx.Err()
+		case queue <- t:
+			return nil
+		}
+	}
+
+	queue <- t
+
+	return nil
+}
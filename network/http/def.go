@@ -0,0 +1,32 @@
+package http
+
+const protocol = "http"
+
+const (
+	closeSig        int8 = iota // 关闭信号
+	dataPacket                  // 数据包
+	heartbeatPacket             // 心跳包
+)
+
+const (
+	openPath   = "/open"   // 打开会话
+	sendPath   = "/send"   // 上行数据包
+	pollPath   = "/poll"   // 长轮询下行数据包
+	eventsPath = "/events" // SSE下行数据包
+	closePath  = "/close"  // 关闭会话
+)
+
+const sessionParam = "sid" // 会话ID参数
+
+const (
+	sseDataPrefix  = "data: "       // SSE数据行前缀
+	sseCloseEvent  = "event: close" // SSE关闭事件
+	sseContentType = "text/event-stream"
+)
+
+const maxBatchSize = 128 // 单次下行的最大数据包数
+
+type task struct {
+	typ int8
+	msg []byte
+}
@@ -0,0 +1,281 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/utils/xcall"
+)
+
+type OpenHandler func(w http.ResponseWriter, r *http.Request) (allowed bool)
+
+type Server interface {
+	network.Server
+	// OnOpen 监听会话打开请求
+	OnOpen(handler OpenHandler)
+}
+
+type server struct {
+	opts              *serverOptions            // 配置
+	listener          net.Listener              // 监听器
+	httpServer        *http.Server              // HTTP服务器
+	connMgr           *serverConnMgr            // 连接管理器
+	startHandler      network.StartHandler      // 服务器启动hook函数
+	stopHandler       network.CloseHandler      // 服务器关闭hook函数
+	connectHandler    network.ConnectHandler    // 连接打开hook函数
+	disconnectHandler network.DisconnectHandler // 连接关闭hook函数
+	receiveHandler    network.ReceiveHandler    // 接收消息hook函数
+	openHandler       OpenHandler               // 会话打开hook函数
+}
+
+var _ Server = &server{}
+
+func NewServer(opts ...ServerOption) Server {
+	o := defaultServerOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &server{}
+	s.opts = o
+	s.connMgr = newConnMgr(s)
+
+	return s
+}
+
+// Addr 监听地址；服务器启动后返回实际监听地址
+func (s *server) Addr() string {
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+
+	return s.opts.addr
+}
+
+// Protocol 协议
+func (s *server) Protocol() string {
+	return protocol
+}
+
+// Start 启动服务器
+func (s *server) Start() error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	if s.startHandler != nil {
+		s.startHandler()
+	}
+
+	xcall.Go(s.serve)
+
+	return nil
+}
+
+// Stop 关闭服务器
+func (s *server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// 先关闭所有连接，使挂起的长轮询及SSE请求尽快返回
+	s.connMgr.close()
+
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if s.stopHandler != nil {
+		s.stopHandler()
+	}
+
+	return nil
+}
+
+// 初始化服务器
+func (s *server) init() error {
+	addr, err := net.ResolveTCPAddr("tcp", s.opts.addr)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.ListenTCP(addr.Network(), addr)
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.TrimSuffix(s.opts.path, "/")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix+openPath, s.cors(http.MethodPost, s.handleOpen))
+	mux.HandleFunc(prefix+sendPath, s.cors(http.MethodPost, s.handleSend))
+	mux.HandleFunc(prefix+pollPath, s.cors(http.MethodGet, s.handlePoll))
+	mux.HandleFunc(prefix+eventsPath, s.cors(http.MethodGet, s.handleEvents))
+	mux.HandleFunc(prefix+closePath, s.cors(http.MethodPost, s.handleClose))
+
+	s.listener = ln
+	s.httpServer = &http.Server{Handler: mux}
+
+	return nil
+}
+
+// 启动服务器
+func (s *server) serve() {
+	var err error
+	if s.opts.certFile != "" && s.opts.keyFile != "" {
+		err = s.httpServer.ServeTLS(s.listener, s.opts.certFile, s.opts.keyFile)
+	} else {
+		err = s.httpServer.Serve(s.listener)
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Errorf("http server shutdown, err: %v", err)
+	}
+}
+
+// 跨域检测及请求方法检测
+func (s *server) cors(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if s.opts.checkOrigin != nil && !s.opts.checkOrigin(r) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Vary", "Origin")
+		}
+
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", method)
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if r.Method != method {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// 处理会话打开请求
+func (s *server) handleOpen(w http.ResponseWriter, r *http.Request) {
+	if s.openHandler != nil && !s.openHandler(w, r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	conn, err := s.connMgr.allocate(r)
+	if err != nil {
+		log.Errorf("connection allocate error: %v", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = io.WriteString(w, conn.sid)
+}
+
+// 处理上行数据包
+func (s *server) handleSend(w http.ResponseWriter, r *http.Request) {
+	conn, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	if err := conn.receive(http.MaxBytesReader(w, r.Body, s.opts.maxBodySize)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 处理长轮询请求
+func (s *server) handlePoll(w http.ResponseWriter, r *http.Request) {
+	conn, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	conn.poll(w, r)
+}
+
+// 处理SSE请求
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	conn, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	if _, ok = w.(http.Flusher); !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	conn.stream(w, r)
+}
+
+// 处理会话关闭请求
+func (s *server) handleClose(w http.ResponseWriter, r *http.Request) {
+	conn, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	_ = conn.forceClose(true)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 查找会话连接；会话不存在或已关闭时返回410
+func (s *server) lookup(w http.ResponseWriter, r *http.Request) (*serverConn, bool) {
+	conn, ok := s.connMgr.load(r.URL.Query().Get(sessionParam))
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
+		return nil, false
+	}
+
+	return conn, true
+}
+
+// OnStart 监听服务器启动
+func (s *server) OnStart(handler network.StartHandler) {
+	s.startHandler = handler
+}
+
+// OnStop 监听服务器关闭
+func (s *server) OnStop(handler network.CloseHandler) {
+	s.stopHandler = handler
+}
+
+// OnOpen 监听会话打开请求
+func (s *server) OnOpen(handler OpenHandler) {
+	s.openHandler = handler
+}
+
+// OnConnect 监听连接打开
+func (s *server) OnConnect(handler network.ConnectHandler) {
+	s.connectHandler = handler
+}
+
+// OnDisconnect 监听连接关闭
+func (s *server) OnDisconnect(handler network.DisconnectHandler) {
+	s.disconnectHandler = handler
+}
+
+// OnReceive 监听接收到消息
+func (s *server) OnReceive(handler network.ReceiveHandler) {
+	s.receiveHandler = handler
+}
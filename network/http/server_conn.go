@@ -0,0 +1,598 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/dobyte/due/v2/utils/xnet"
+	"github.com/dobyte/due/v2/utils/xtime"
+)
+
+type serverConn struct {
+	id                int64          // 连接ID
+	sid               string         // 会话ID
+	uid               atomic.Int64   // 用户ID
+	attr              *attr          // 连接属性
+	state             atomic.Int32   // 连接状态
+	connMgr           *serverConnMgr // 连接管理
+	rw                sync.RWMutex   // 锁
+	mu                sync.Mutex     // 上行消息处理锁，保证同一连接的消息按序处理
+	closed            bool           // 是否已关闭
+	localAddr         net.Addr       // 本地地址
+	remoteAddr        net.Addr       // 远端地址
+	taskPool          sync.Pool      // 任务对象池
+	lowPriorityQueue  chan *task     // 低优先级队列
+	highPriorityQueue chan *task     // 高优先级队列
+	done              chan struct{}  // 写入完成信号
+	close             chan struct{}  // 关闭信号
+	lastHeartbeatTime atomic.Int64   // 上次心跳时间
+	authorizeTimer    atomic.Value   // 授权定时器
+}
+
+var _ network.Conn = &serverConn{}
+
+func newServerConn(cm *serverConnMgr, id int64, sid string, r *http.Request) *serverConn {
+	c := &serverConn{}
+	c.id = id
+	c.sid = sid
+	c.attr = &attr{}
+	c.connMgr = cm
+	c.taskPool = sync.Pool{New: func() any { return &task{} }}
+	c.lowPriorityQueue = make(chan *task, cm.server.opts.writeQueueSize)
+	c.highPriorityQueue = make(chan *task, cm.server.opts.writeQueueSize)
+	c.done = make(chan struct{}, 1)
+	c.close = make(chan struct{})
+	c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+	c.authorizeTimer.Store((*time.Timer)(nil))
+	c.state.Store(int32(network.ConnOpened))
+
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		c.localAddr = addr
+	}
+
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		c.remoteAddr = addr
+	}
+
+	return c
+}
+
+// ID 获取连接ID
+func (c *serverConn) ID() int64 {
+	return c.id
+}
+
+// UID 获取用户ID
+func (c *serverConn) UID() int64 {
+	return c.uid.Load()
+}
+
+// Attr 获取属性接口
+func (c *serverConn) Attr() network.Attr {
+	return c.attr
+}
+
+// Bind 绑定用户ID
+func (c *serverConn) Bind(uid int64) {
+	c.uid.Store(uid)
+
+	c.uncheckAuthorize()
+}
+
+// Unbind 解绑用户ID
+func (c *serverConn) Unbind() {
+	c.uid.Store(0)
+
+	c.checkAuthorize()
+}
+
+// Send 发送消息（同步）
+func (c *serverConn) Send(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if c.closed {
+		return errors.ErrConnectionClosed
+	}
+
+	return c.doWriteToQueue(c.highPriorityQueue, dataPacket, msg)
+}
+
+// Push 发送消息（异步）
+func (c *serverConn) Push(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if c.closed {
+		return errors.ErrConnectionClosed
+	}
+
+	return c.doWriteToQueue(c.lowPriorityQueue, dataPacket, msg)
+}
+
+// State 获取连接状态
+func (c *serverConn) State() network.ConnState {
+	return network.ConnState(c.state.Load())
+}
+
+// Close 关闭连接
+func (c *serverConn) Close(force ...bool) error {
+	if len(force) > 0 && force[0] {
+		return c.forceClose(true)
+	} else {
+		return c.graceClose(true)
+	}
+}
+
+// LocalIP 获取本地IP
+func (c *serverConn) LocalIP() (string, error) {
+	addr, err := c.LocalAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// LocalAddr 获取本地地址
+func (c *serverConn) LocalAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	if c.localAddr == nil {
+		return nil, errors.ErrNotFoundIPAddress
+	}
+
+	return c.localAddr, nil
+}
+
+// RemoteIP 获取远端IP
+func (c *serverConn) RemoteIP() (string, error) {
+	addr, err := c.RemoteAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// RemoteAddr 获取远端地址
+func (c *serverConn) RemoteAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	if c.remoteAddr == nil {
+		return nil, errors.ErrNotFoundIPAddress
+	}
+
+	return c.remoteAddr, nil
+}
+
+// 初始化连接
+func (c *serverConn) init() {
+	if c.connMgr.server.opts.heartbeatInterval > 0 {
+		xcall.Go(c.keepalive)
+	}
+
+	c.checkAuthorize()
+
+	if c.connMgr.server.connectHandler != nil {
+		c.connMgr.server.connectHandler(c)
+	}
+}
+
+// 检测连接状态
+func (c *serverConn) checkState() error {
+	switch c.State() {
+	case network.ConnHanged:
+		return errors.ErrConnectionHanged
+	case network.ConnClosed:
+		return errors.ErrConnectionClosed
+	default:
+		return nil
+	}
+}
+
+// 授权检查
+func (c *serverConn) checkAuthorize() {
+	if c.connMgr.server.opts.authorizeTimeout > 0 {
+		timer := c.authorizeTimer.Swap(time.AfterFunc(c.connMgr.server.opts.authorizeTimeout, func() {
+			if c.UID() != 0 {
+				return
+			}
+
+			c.forceClose(true)
+		}))
+		if t, ok := timer.(*time.Timer); ok && t != nil {
+			t.Stop()
+		}
+	}
+}
+
+// 取消授权检查
+func (c *serverConn) uncheckAuthorize() {
+	if c.connMgr.server.opts.authorizeTimeout > 0 {
+		timer := c.authorizeTimer.Swap((*time.Timer)(nil))
+
+		if t, ok := timer.(*time.Timer); ok && t != nil {
+			t.Stop()
+		}
+	}
+}
+
+// 优雅关闭
+// 关闭信号排在待下发消息之后，待客户端通过长轮询或SSE取走全部消息后再关闭；
+// 客户端长时间未拉取时，超过一个长轮询周期后强制关闭
+func (c *serverConn) graceClose(isNeedRecycle bool) error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnHanged)) {
+		return errors.ErrConnectionNotOpened
+	}
+
+	c.uncheckAuthorize()
+
+	c.rw.RLock()
+	if c.closed {
+		c.rw.RUnlock()
+		return errors.ErrConnectionClosed
+	}
+	c.lowPriorityQueue <- &task{typ: closeSig}
+	c.rw.RUnlock()
+
+	timer := time.NewTimer(c.connMgr.server.opts.pollTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-c.done:
+	case <-c.close:
+	case <-timer.C:
+	}
+
+	if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+		return errors.ErrConnectionNotHanged
+	}
+
+	return c.doClose(isNeedRecycle)
+}
+
+// 强制关闭
+func (c *serverConn) forceClose(isNeedRecycle bool) error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnClosed)) {
+		if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+			return errors.ErrConnectionClosed
+		}
+	}
+
+	c.uncheckAuthorize()
+
+	return c.doClose(isNeedRecycle)
+}
+
+// 执行关闭操作
+func (c *serverConn) doClose(isNeedRecycle bool) error {
+	c.rw.Lock()
+
+	if c.closed {
+		c.rw.Unlock()
+		return errors.ErrConnectionClosed
+	}
+
+	close(c.lowPriorityQueue)
+	close(c.highPriorityQueue)
+	close(c.close)
+	c.closed = true
+
+	c.rw.Unlock()
+
+	if c.connMgr.server.disconnectHandler != nil {
+		c.connMgr.server.disconnectHandler(c)
+	}
+
+	if isNeedRecycle {
+		c.connMgr.recycle(c.sid)
+	}
+
+	return nil
+}
+
+// 接收上行消息
+// 请求体由若干个完整的数据包拼接而成
+func (c *serverConn) receive(reader io.Reader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refreshHeartbeat()
+
+	for {
+		msg, err := packet.ReadMessage(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		switch c.State() {
+		case network.ConnHanged:
+			continue
+		case network.ConnClosed:
+			return nil
+		default:
+			// ignore
+		}
+
+		// ignore empty packet
+		if len(msg) == 0 {
+			continue
+		}
+
+		// check heartbeat packet
+		isHeartbeat, err := packet.CheckHeartbeat(msg)
+		if err != nil {
+			log.Errorf("check heartbeat message error: %v", err)
+			continue
+		}
+
+		// ignore heartbeat packet
+		if isHeartbeat {
+			// responsive heartbeat
+			if c.connMgr.server.opts.heartbeatMechanism == RespHeartbeat {
+				c.rw.RLock()
+				if !c.closed {
+					c.doWriteToQueue(c.highPriorityQueue, heartbeatPacket)
+				}
+				c.rw.RUnlock()
+			}
+		} else {
+			if c.connMgr.server.receiveHandler != nil {
+				c.connMgr.server.receiveHandler(c, msg)
+			}
+		}
+	}
+}
+
+// 处理长轮询请求
+// 首个消息到达前最多挂起一个长轮询周期，随后将队列中已就绪的消息合并为一次响应
+func (c *serverConn) poll(w http.ResponseWriter, r *http.Request) {
+	c.refreshHeartbeat()
+
+	timer := time.NewTimer(c.connMgr.server.opts.pollTimeout)
+	defer timer.Stop()
+
+	var t *task
+
+	select {
+	case t = <-c.highPriorityQueue:
+	default:
+		select {
+		case t = <-c.highPriorityQueue:
+		case t = <-c.lowPriorityQueue:
+		case <-timer.C:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case <-r.Context().Done():
+			return
+		case <-c.close:
+		}
+	}
+
+	if t == nil {
+		http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+
+	for i := 0; t != nil; i++ {
+		if !c.doPack(buf, t) {
+			break
+		}
+
+		if i+1 >= maxBatchSize {
+			break
+		}
+
+		select {
+		case t = <-c.highPriorityQueue:
+		default:
+			select {
+			case t = <-c.highPriorityQueue:
+			case t = <-c.lowPriorityQueue:
+			default:
+				t = nil
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if buf.Len() == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Warnf("write poll response failed: %d %v", c.id, err)
+	}
+}
+
+// 处理SSE请求
+// 每个数据包以base64编码后作为一条data事件下发，连接关闭时下发close事件
+func (c *serverConn) stream(w http.ResponseWriter, r *http.Request) {
+	flusher := w.(http.Flusher)
+
+	w.Header().Set("Content-Type", sseContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	c.refreshHeartbeat()
+
+	ticker := time.NewTicker(c.connMgr.server.opts.pollTimeout)
+	defer ticker.Stop()
+
+	buf := &bytes.Buffer{}
+
+	for {
+		var t *task
+
+		select {
+		case t = <-c.highPriorityQueue:
+		default:
+			select {
+			case t = <-c.highPriorityQueue:
+			case t = <-c.lowPriorityQueue:
+			case <-ticker.C:
+				// keepalive comment
+				if _, err := io.WriteString(w, ":\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+				continue
+			case <-r.Context().Done():
+				return
+			case <-c.close:
+			}
+		}
+
+		buf.Reset()
+
+		if t == nil || !c.doPack(buf, t) {
+			_, _ = io.WriteString(w, sseCloseEvent+"\n"+sseDataPrefix+"\n\n")
+			flusher.Flush()
+			return
+		}
+
+		if _, err := io.WriteString(w, sseDataPrefix+base64.StdEncoding.EncodeToString(buf.Bytes())+"\n\n"); err != nil {
+			log.Warnf("write event failed: %d %v", c.id, err)
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// 保活检测
+func (c *serverConn) keepalive() {
+	ticker := time.NewTicker(c.connMgr.server.opts.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.close:
+			return
+		case t := <-ticker.C:
+			deadline := t.Add(-2 * c.connMgr.server.opts.heartbeatInterval).UnixNano()
+
+			if c.lastHeartbeatTime.Load() < deadline {
+				log.Debugf("connection heartbeat timeout, cid: %d", c.id)
+				_ = c.forceClose(true)
+				return
+			}
+
+			if c.connMgr.server.opts.heartbeatMechanism == TickHeartbeat {
+				c.rw.RLock()
+				if !c.closed {
+					c.doWriteToQueue(c.highPriorityQueue, heartbeatPacket)
+				}
+				c.rw.RUnlock()
+			}
+		}
+	}
+}
+
+// 打包任务到缓冲区；遇到关闭信号时返回false
+func (c *serverConn) doPack(buf *bytes.Buffer, t *task) bool {
+	if t.typ == closeSig {
+		select {
+		case c.done <- struct{}{}:
+		default:
+		}
+		return false
+	}
+
+	defer c.doRecycleToPool(t)
+
+	switch t.typ {
+	case heartbeatPacket:
+		if msg, err := packet.PackHeartbeat(); err != nil {
+			log.Errorf("pack heartbeat message error: %v", err)
+		} else {
+			buf.Write(msg)
+		}
+	default:
+		buf.Write(t.msg)
+	}
+
+	return true
+}
+
+// 刷新心跳时间
+func (c *serverConn) refreshHeartbeat() {
+	if c.connMgr.server.opts.heartbeatInterval > 0 {
+		c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+	}
+}
+
+// 回收任务到对象池
+func (c *serverConn) doRecycleToPool(t *task) {
+	t.msg = nil
+	c.taskPool.Put(t)
+}
+
+// 写入任务到队列
+func (c *serverConn) doWriteToQueue(queue chan *task, typ int8, msg ...[]byte) error {
+	t := c.taskPool.Get().(*task)
+	t.typ = typ
+	if len(msg) > 0 {
+		t.msg = msg[0]
+	}
+
+	metrics.NetworkWriteQueueDepth.Observe(float64(len(queue)), protocol)
+
+	if len(queue) == cap(queue) {
+		metrics.NetworkWriteQueueFull.Add(1, protocol)
+	}
+
+	if c.connMgr.server.opts.writeTimeout > 0 && len(queue) == cap(queue) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.connMgr.server.opts.writeTimeout)
+		defer cancel()
+
+		select {
+		case <-ctx.Done():
+			c.doRecycleToPool(t)
+			return ctx.Err()
+		case queue <- t:
+			return nil
+		}
+	}
+
+	queue <- t
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/utils/xuuid"
+)
+
+type serverConnMgr struct {
+	id          atomic.Int64 // 连接ID
+	total       atomic.Int64 // 总连接数
+	server      *server      // 服务器
+	connections sync.Map     // 会话ID -> 连接
+}
+
+func newConnMgr(server *server) *serverConnMgr {
+	return &serverConnMgr{server: server}
+}
+
+// 关闭所有连接
+func (cm *serverConnMgr) close() {
+	cm.connections.Range(func(_, value any) bool {
+		_ = value.(*serverConn).forceClose(true)
+		return true
+	})
+}
+
+// 分配连接
+func (cm *serverConnMgr) allocate(r *http.Request) (*serverConn, error) {
+	if cm.total.Load() >= int64(cm.server.opts.maxConnNum) {
+		return nil, errors.ErrTooManyConnection
+	}
+
+	conn := newServerConn(cm, cm.id.Add(1), xuuid.UUID(), r)
+	cm.connections.Store(conn.sid, conn)
+	cm.total.Add(1)
+
+	conn.init()
+
+	return conn, nil
+}
+
+// 加载连接
+func (cm *serverConnMgr) load(sid string) (*serverConn, bool) {
+	if sid == "" {
+		return nil, false
+	}
+
+	val, ok := cm.connections.Load(sid)
+	if !ok {
+		return nil, false
+	}
+
+	return val.(*serverConn), true
+}
+
+// 回收连接
+func (cm *serverConnMgr) recycle(sid string) {
+	if _, ok := cm.connections.LoadAndDelete(sid); ok {
+		cm.total.Add(-1)
+	}
+}
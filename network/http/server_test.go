@@ -0,0 +1,145 @@
+package http_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/network/http/v2"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+)
+
+func TestServer(t *testing.T) {
+	for _, transport := range []http.Transport{http.PollTransport, http.SSETransport} {
+		t.Run(string(transport), func(t *testing.T) {
+			testServer(t, transport)
+		})
+	}
+}
+
+func testServer(t *testing.T, transport http.Transport) {
+	server := http.NewServer(
+		http.WithServerAddr("127.0.0.1:0"),
+		http.WithServerPollTimeout(time.Second),
+		http.WithServerHeartbeatInterval(200*time.Millisecond),
+	)
+	connected := make(chan network.Conn, 1)
+	server.OnConnect(func(conn network.Conn) {
+		connected <- conn
+	})
+	server.OnReceive(func(conn network.Conn, data []byte) {
+		message, err := packet.UnpackMessage(data)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		msg, err := packet.PackMessage(&packet.Message{
+			Seq:    message.Seq,
+			Route:  message.Route,
+			Buffer: append([]byte("echo: "), message.Buffer...),
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if err = conn.Push(msg); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan *packet.Message, 10)
+	disconnected := make(chan struct{})
+
+	client := http.NewClient(
+		http.WithClientUrl("http://"+server.Addr()),
+		http.WithClientTransport(transport),
+		http.WithClientHeartbeatInterval(200*time.Millisecond),
+	)
+	client.OnReceive(func(conn network.Conn, data []byte) {
+		message, err := packet.UnpackMessage(data)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		received <- message
+	})
+	client.OnDisconnect(func(conn network.Conn) {
+		close(disconnected)
+	})
+
+	conn, err := client.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		msg, err := packet.PackMessage(&packet.Message{Seq: int32(i), Route: 1, Buffer: []byte("hello")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = conn.Push(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case message := <-received:
+			if message.Seq != int32(i) || string(message.Buffer) != "echo: hello" {
+				t.Fatalf("unexpected message, seq: %d, buffer: %s", message.Seq, message.Buffer)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("wait message %d timeout", i)
+		}
+	}
+
+	// 多个心跳周期后连接仍应保持
+	time.Sleep(time.Second)
+
+	if conn.State() != network.ConnOpened {
+		t.Fatalf("connection should be opened, state: %v", conn.State())
+	}
+
+	// 优雅关闭时应先下发待发送消息
+	sc := <-connected
+
+	msg, err := packet.PackMessage(&packet.Message{Seq: 0, Route: 2, Buffer: []byte("bye")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sc.Push(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case message := <-received:
+		if message.Route != 2 || string(message.Buffer) != "bye" {
+			t.Fatalf("unexpected message, route: %d, buffer: %s", message.Route, message.Buffer)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("wait message timeout")
+	}
+
+	select {
+	case <-disconnected:
+	case <-time.After(3 * time.Second):
+		t.Fatal("wait disconnect timeout")
+	}
+
+	if err = server.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
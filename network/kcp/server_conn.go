@@ -8,6 +8,7 @@ import (
 
 	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
 	"github.com/dobyte/due/v2/network"
 	"github.com/dobyte/due/v2/packet"
 	"github.com/dobyte/due/v2/utils/xcall"
@@ -93,6 +94,12 @@ func (c *serverConn) Push(msg []byte) error {
 		return errors.ErrConnectionClosed
 	}
 
+	metrics.NetworkWriteQueueDepth.Observe(float64(len(c.chWrite)), protocol)
+
+	if len(c.chWrite) == cap(c.chWrite) {
+		metrics.NetworkWriteQueueFull.Add(1, protocol)
+	}
+
 	c.chWrite <- chWrite{typ: dataPacket, msg: msg}
 
 	return nil
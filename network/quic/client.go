@@ -0,0 +1,136 @@
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync/atomic"
+
+	ctls "github.com/dobyte/due/v2/core/tls"
+	"github.com/dobyte/due/v2/network"
+	"github.com/quic-go/quic-go"
+)
+
+type client struct {
+	opts              *clientOptions            // 配置
+	id                atomic.Int64              // 连接ID
+	connectHandler    network.ConnectHandler    // 连接打开hook函数
+	disconnectHandler network.DisconnectHandler // 连接关闭hook函数
+	receiveHandler    network.ReceiveHandler    // 接收消息hook函数
+}
+
+var _ network.Client = &client{}
+
+func NewClient(opts ...ClientOption) network.Client {
+	o := defaultClientOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &client{opts: o}
+}
+
+// Dial 拨号连接
+func (c *client) Dial(addr ...string) (network.Conn, error) {
+	var address string
+
+	if len(addr) > 0 && addr[0] != "" {
+		address = addr[0]
+	} else {
+		address = c.opts.addr
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := c.makeTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	tr, err := newTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.dialTimeout)
+	defer cancel()
+
+	conn, err := tr.Dial(ctx, udpAddr, config, &quic.Config{})
+	if err != nil {
+		closeTransport(tr)
+		return nil, err
+	}
+
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		_ = conn.CloseWithError(0, "")
+		closeTransport(tr)
+		return nil, err
+	}
+
+	if _, err = stream.Write(preface); err != nil {
+		_ = conn.CloseWithError(0, "")
+		closeTransport(tr)
+		return nil, err
+	}
+
+	return newClientConn(c, c.id.Add(1), tr, conn, stream), nil
+}
+
+// Protocol 协议
+func (c *client) Protocol() string {
+	return protocol
+}
+
+// OnConnect 监听连接打开
+func (c *client) OnConnect(handler network.ConnectHandler) {
+	c.connectHandler = handler
+}
+
+// OnDisconnect 监听连接关闭
+func (c *client) OnDisconnect(handler network.DisconnectHandler) {
+	c.disconnectHandler = handler
+}
+
+// OnReceive 监听接收到消息
+func (c *client) OnReceive(handler network.ReceiveHandler) {
+	c.receiveHandler = handler
+}
+
+// 构建TLS配置
+func (c *client) makeTLSConfig() (*tls.Config, error) {
+	var config *tls.Config
+
+	if c.opts.caFile != "" {
+		var err error
+		if config, err = ctls.MakeTCPClientTLSConfig(c.opts.caFile, c.opts.serverName); err != nil {
+			return nil, err
+		}
+	} else {
+		config = &tls.Config{ServerName: c.opts.serverName, InsecureSkipVerify: c.opts.insecureSkipVerify}
+	}
+
+	config.MinVersion = tls.VersionTLS13
+	config.NextProtos = []string{alpn}
+
+	return config, nil
+}
+
+// 创建传输层，每个传输层独占一个本地UDP端口
+func newTransport() (*quic.Transport, error) {
+	udpConn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quic.Transport{Conn: udpConn}, nil
+}
+
+// 关闭传输层及其本地UDP端口
+func closeTransport(tr *quic.Transport) {
+	_ = tr.Close()
+	_ = tr.Conn.Close()
+}
@@ -0,0 +1,561 @@
+package quic
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/dobyte/due/v2/utils/xnet"
+	"github.com/dobyte/due/v2/utils/xtime"
+	"github.com/quic-go/quic-go"
+)
+
+// Migrator 连接迁移接口，由客户端连接实现
+type Migrator interface {
+	// Migrate 将连接迁移至新的本地网络路径
+	// 客户端网络发生切换（如Wi-Fi切换至蜂窝网络）时调用，迁移过程中连接及会话保持不变
+	Migrate(ctx context.Context) error
+}
+
+type clientConn struct {
+	rw                sync.RWMutex
+	wmu               sync.Mutex        // 控制流写入锁
+	id                int64             // 连接ID
+	uid               atomic.Int64      // 用户ID
+	attr              *attr             // 连接属性
+	conn              *quic.Conn        // QUIC源连接
+	stream            *quic.Stream      // 控制流
+	transports        []*quic.Transport // 传输层，连接迁移后旧的传输层仍需保留至连接关闭
+	state             atomic.Int32      // 连接状态
+	client            *client           // 客户端
+	taskPool          sync.Pool         // 任务对象池
+	taskQueue         chan *task        // 任务队列
+	done              chan struct{}     // 写入完成信号
+	close             chan struct{}     // 关闭信号
+	lastHeartbeatTime atomic.Int64      // 上次心跳时间
+}
+
+var (
+	_ network.Conn = &clientConn{}
+	_ Migrator     = &clientConn{}
+)
+
+func newClientConn(client *client, id int64, tr *quic.Transport, conn *quic.Conn, stream *quic.Stream) network.Conn {
+	c := &clientConn{
+		id:         id,
+		attr:       &attr{},
+		conn:       conn,
+		stream:     stream,
+		transports: []*quic.Transport{tr},
+		client:     client,
+		taskPool:   sync.Pool{New: func() any { return &task{} }},
+		taskQueue:  make(chan *task, client.opts.writeQueueSize),
+		done:       make(chan struct{}),
+		close:      make(chan struct{}),
+	}
+
+	c.state.Store(int32(network.ConnOpened))
+	c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+
+	xcall.Go(c.read)
+
+	xcall.Go(c.readStreams)
+
+	xcall.Go(c.write)
+
+	if c.client.connectHandler != nil {
+		c.client.connectHandler(c)
+	}
+
+	return c
+}
+
+// ID 获取连接ID
+func (c *clientConn) ID() int64 {
+	return c.id
+}
+
+// UID 获取用户ID
+func (c *clientConn) UID() int64 {
+	return c.uid.Load()
+}
+
+// Attr 获取属性接口
+func (c *clientConn) Attr() network.Attr {
+	return c.attr
+}
+
+// Bind 绑定用户ID
+func (c *clientConn) Bind(uid int64) {
+	c.uid.Store(uid)
+}
+
+// Unbind 解绑用户ID
+func (c *clientConn) Unbind() {
+	c.uid.Store(0)
+}
+
+// Send 发送消息（同步）
+func (c *clientConn) Send(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	conn, stream := c.conn, c.stream
+	c.rw.RUnlock()
+
+	if conn == nil {
+		return errors.ErrConnectionClosed
+	}
+
+	if c.isLargeMessage(msg) {
+		return writeStream(conn, msg)
+	}
+
+	return c.doWriteControl(stream, msg)
+}
+
+// Push 发送消息（异步）
+func (c *clientConn) Push(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if c.conn == nil {
+		return errors.ErrConnectionClosed
+	}
+
+	return c.doWriteToQueue(c.taskQueue, dataPacket, msg)
+}
+
+// State 获取连接状态
+func (c *clientConn) State() network.ConnState {
+	return network.ConnState(c.state.Load())
+}
+
+// Close 关闭连接（主动关闭）
+func (c *clientConn) Close(force ...bool) error {
+	if len(force) > 0 && force[0] {
+		return c.forceClose()
+	} else {
+		return c.graceClose()
+	}
+}
+
+// LocalIP 获取本地IP
+func (c *clientConn) LocalIP() (string, error) {
+	addr, err := c.LocalAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// LocalAddr 获取本地地址
+// 发生连接迁移后返回迁移后的本地地址
+func (c *clientConn) LocalAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if c.conn == nil {
+		return nil, errors.ErrConnectionClosed
+	}
+
+	return c.transports[len(c.transports)-1].Conn.LocalAddr(), nil
+}
+
+// RemoteIP 获取远端IP
+func (c *clientConn) RemoteIP() (string, error) {
+	addr, err := c.RemoteAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// RemoteAddr 获取远端地址
+func (c *clientConn) RemoteAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	c.rw.RLock()
+	conn := c.conn
+	c.rw.RUnlock()
+
+	if conn == nil {
+		return nil, errors.ErrConnectionClosed
+	}
+
+	return conn.RemoteAddr(), nil
+}
+
+// Migrate 将连接迁移至新的本地网络路径
+func (c *clientConn) Migrate(ctx context.Context) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	conn := c.conn
+	c.rw.RUnlock()
+
+	if conn == nil {
+		return errors.ErrConnectionClosed
+	}
+
+	tr, err := newTransport()
+	if err != nil {
+		return err
+	}
+
+	path, err := conn.AddPath(tr)
+	if err != nil {
+		closeTransport(tr)
+		return err
+	}
+
+	if err = path.Probe(ctx); err != nil {
+		_ = path.Close()
+		closeTransport(tr)
+		return err
+	}
+
+	if err = path.Switch(); err != nil {
+		_ = path.Close()
+		closeTransport(tr)
+		return err
+	}
+
+	c.rw.Lock()
+	if c.conn == nil {
+		c.rw.Unlock()
+		closeTransport(tr)
+		return errors.ErrConnectionClosed
+	}
+	c.transports = append(c.transports, tr)
+	c.rw.Unlock()
+
+	return nil
+}
+
+// 检测连接状态
+func (c *clientConn) checkState() error {
+	switch c.State() {
+	case network.ConnHanged:
+		return errors.ErrConnectionHanged
+	case network.ConnClosed:
+		return errors.ErrConnectionClosed
+	default:
+		return nil
+	}
+}
+
+// 优雅关闭
+func (c *clientConn) graceClose() error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnHanged)) {
+		return errors.ErrConnectionNotOpened
+	}
+
+	c.rw.RLock()
+	if c.conn == nil {
+		c.rw.RUnlock()
+		return errors.ErrConnectionClosed
+	}
+	c.taskQueue <- &task{typ: closeSig}
+	c.rw.RUnlock()
+
+	<-c.done
+
+	if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+		return errors.ErrConnectionNotHanged
+	}
+
+	return c.doClose(true)
+}
+
+// 强制关闭
+func (c *clientConn) forceClose() error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnClosed)) {
+		if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+			return errors.ErrConnectionClosed
+		}
+	}
+
+	return c.doClose(false)
+}
+
+// 执行关闭操作
+// 优雅关闭时先关闭控制流的写入端，待服务端读取完剩余消息并关闭连接后再释放连接
+func (c *clientConn) doClose(isGraceful bool) error {
+	c.rw.Lock()
+
+	if c.conn == nil {
+		c.rw.Unlock()
+		return errors.ErrConnectionClosed
+	}
+
+	close(c.taskQueue)
+	close(c.close)
+	close(c.done)
+	conn, stream, transports := c.conn, c.stream, c.transports
+	c.conn, c.stream, c.transports = nil, nil, nil
+	c.rw.Unlock()
+
+	if isGraceful {
+		c.wmu.Lock()
+		_ = stream.Close()
+		c.wmu.Unlock()
+
+		timer := time.NewTimer(closeTimeout)
+		select {
+		case <-conn.Context().Done():
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+
+	err := conn.CloseWithError(0, "")
+
+	for _, tr := range transports {
+		closeTransport(tr)
+	}
+
+	if c.client.disconnectHandler != nil {
+		c.client.disconnectHandler(c)
+	}
+
+	return err
+}
+
+// 是否已关闭
+func (c *clientConn) isClosed() bool {
+	return c.State() == network.ConnClosed
+}
+
+// 是否需要通过独立流发送
+func (c *clientConn) isLargeMessage(msg []byte) bool {
+	return c.client.opts.streamThreshold > 0 && len(msg) >= c.client.opts.streamThreshold
+}
+
+// 读取控制流消息
+func (c *clientConn) read() {
+	stream := c.stream
+
+	for {
+		select {
+		case <-c.close:
+			return
+		default:
+			data, err := packet.ReadMessage(stream)
+			if err != nil {
+				_ = c.forceClose()
+				return
+			}
+
+			if !c.doHandleMessage(data) {
+				return
+			}
+		}
+	}
+}
+
+// 读取独立流消息
+func (c *clientConn) readStreams() {
+	conn := c.conn
+
+	acceptStreams(conn.Context(), conn, func(data []byte) {
+		c.doHandleMessage(data)
+	})
+}
+
+// 处理接收到的消息；连接已关闭时返回false
+func (c *clientConn) doHandleMessage(data []byte) bool {
+	if c.client.opts.heartbeatInterval > 0 {
+		c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+	}
+
+	switch c.State() {
+	case network.ConnHanged:
+		return true
+	case network.ConnClosed:
+		return false
+	default:
+		// ignore
+	}
+
+	// ignore empty packet
+	if len(data) == 0 {
+		return true
+	}
+
+	isHeartbeat, err := packet.CheckHeartbeat(data)
+	if err != nil {
+		log.Errorf("check heartbeat message error: %v", err)
+		return true
+	}
+
+	// ignore heartbeat packet
+	if isHeartbeat {
+		return true
+	}
+
+	if c.client.receiveHandler != nil {
+		c.client.receiveHandler(c, data)
+	}
+
+	return true
+}
+
+// 写入消息
+func (c *clientConn) write() {
+	var (
+		conn   = c.conn
+		stream = c.stream
+		ticker *time.Ticker
+	)
+
+	if c.client.opts.heartbeatInterval > 0 {
+		ticker = time.NewTicker(c.client.opts.heartbeatInterval)
+		defer ticker.Stop()
+	} else {
+		ticker = &time.Ticker{C: make(chan time.Time, 1)}
+	}
+
+	for {
+		select {
+		case t, ok := <-c.taskQueue:
+			if !ok {
+				return
+			}
+
+			if !c.doWrite(conn, stream, t) {
+				return
+			}
+		case t, ok := <-ticker.C:
+			if !ok {
+				return
+			}
+
+			if !c.doHandleHeartbeat(stream, t) {
+				return
+			}
+		}
+	}
+}
+
+// 执行写入操作
+func (c *clientConn) doWrite(conn *quic.Conn, stream *quic.Stream, t *task) bool {
+	defer c.doRecycleToPool(t)
+
+	if t.typ == closeSig {
+		c.rw.RLock()
+		if c.conn != nil {
+			c.done <- struct{}{}
+		}
+		c.rw.RUnlock()
+		return false
+	}
+
+	if c.isClosed() {
+		return false
+	}
+
+	if msg := t.msg; c.isLargeMessage(msg) {
+		xcall.Go(func() {
+			if err := writeStream(conn, msg); err != nil && !c.isClosed() {
+				log.Errorf("write stream message error: %v", err)
+			}
+		})
+	} else {
+		if err := c.doWriteControl(stream, msg); err != nil && !c.isClosed() {
+			log.Errorf("write message error: %v", err)
+		}
+	}
+
+	return true
+}
+
+// 处理心跳
+func (c *clientConn) doHandleHeartbeat(stream *quic.Stream, t time.Time) bool {
+	deadline := t.Add(-2 * c.client.opts.heartbeatInterval).UnixNano()
+
+	if c.lastHeartbeatTime.Load() < deadline {
+		log.Debugf("connection heartbeat timeout")
+		_ = c.forceClose()
+		return false
+	} else {
+		if c.isClosed() {
+			return false
+		}
+
+		if heartbeat, err := packet.PackHeartbeat(); err != nil {
+			log.Errorf("pack heartbeat message error: %v", err)
+		} else {
+			if err = c.doWriteControl(stream, heartbeat); err != nil && !c.isClosed() {
+				log.Errorf("write heartbeat message error: %v", err)
+			}
+		}
+	}
+
+	return true
+}
+
+// 写入控制流
+func (c *clientConn) doWriteControl(stream *quic.Stream, msg []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	_, err := stream.Write(msg)
+
+	return err
+}
+
+// 回收任务到对象池
+func (c *clientConn) doRecycleToPool(t *task) {
+	t.msg = nil
+	c.taskPool.Put(t)
+}
+
+// 写入任务到队列
+func (c *clientConn) doWriteToQueue(queue chan *task, typ int8, msg ...[]byte) error {
+	t := c.taskPool.Get().(*task)
+	t.typ = typ
+	if len(msg) > 0 {
+		t.msg = msg[0]
+	}
+
+	if c.client.opts.writeTimeout > 0 && len(queue) == cap(queue) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.client.opts.writeTimeout)
+		defer cancel()
+
+		select {
+		case <-ctx.Done():
+			c.doRecycleToPool(t)
+			return ctx.Err()
+		case queue <- t:
+			return nil
+		}
+	}
+
+	queue <- t
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package quic
+
+import "time"
+
+const protocol = "quic"
+
+const alpn = "due" // 应用层协议协商标识
+
+const (
+	closeSig   int8 = iota // 关闭信号
+	dataPacket             // 数据包
+)
+
+const (
+	handshakeTimeout = 3 * time.Second // 等待客户端打开控制流的最长时间
+	closeTimeout     = 3 * time.Second // 优雅关闭时等待对端关闭连接的最长时间
+)
+
+// 控制流开启时写入的空数据包，用于让对端感知到控制流
+var preface = []byte{0, 0, 0, 0}
+
+type task struct {
+	typ int8
+	msg []byte
+}
@@ -0,0 +1,171 @@
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+
+	ctls "github.com/dobyte/due/v2/core/tls"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/quic-go/quic-go"
+)
+
+type server struct {
+	opts              *serverOptions            // 配置
+	listener          *quic.Listener            // 监听器
+	connMgr           *serverConnMgr            // 连接管理器
+	startHandler      network.StartHandler      // 服务器启动hook函数
+	stopHandler       network.CloseHandler      // 服务器关闭hook函数
+	connectHandler    network.ConnectHandler    // 连接打开hook函数
+	disconnectHandler network.DisconnectHandler // 连接关闭hook函数
+	receiveHandler    network.ReceiveHandler    // 接收消息hook函数
+}
+
+var _ network.Server = &server{}
+
+func NewServer(opts ...ServerOption) network.Server {
+	o := defaultServerOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &server{}
+	s.opts = o
+	s.connMgr = newServerConnMgr(s)
+
+	return s
+}
+
+// Addr 监听地址；服务器启动后返回实际监听地址
+func (s *server) Addr() string {
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+
+	return s.opts.addr
+}
+
+// Start 启动服务器
+func (s *server) Start() error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	if s.startHandler != nil {
+		s.startHandler()
+	}
+
+	xcall.Go(s.serve)
+
+	return nil
+}
+
+// Stop 关闭服务器
+// 关闭监听器会同时销毁其上的所有连接，故而需要先关闭连接
+func (s *server) Stop() error {
+	s.connMgr.close()
+
+	if err := s.listener.Close(); err != nil {
+		return err
+	}
+
+	if s.stopHandler != nil {
+		s.stopHandler()
+	}
+
+	return nil
+}
+
+// Protocol 协议
+func (s *server) Protocol() string {
+	return protocol
+}
+
+// OnStart 监听服务器启动
+func (s *server) OnStart(handler network.StartHandler) {
+	s.startHandler = handler
+}
+
+// OnStop 监听服务器关闭
+func (s *server) OnStop(handler network.CloseHandler) {
+	s.stopHandler = handler
+}
+
+// OnConnect 监听连接打开
+func (s *server) OnConnect(handler network.ConnectHandler) {
+	s.connectHandler = handler
+}
+
+// OnDisconnect 监听连接关闭
+func (s *server) OnDisconnect(handler network.DisconnectHandler) {
+	s.disconnectHandler = handler
+}
+
+// OnReceive 监听接收到消息
+func (s *server) OnReceive(handler network.ReceiveHandler) {
+	s.receiveHandler = handler
+}
+
+// 初始化QUIC服务器
+func (s *server) init() error {
+	var (
+		config *tls.Config
+		err    error
+	)
+
+	if s.opts.certFile != "" && s.opts.keyFile != "" {
+		config, err = ctls.MakeServerTLSConfig(s.opts.certFile, s.opts.keyFile)
+	} else {
+		log.Warnf("quic server certFile or keyFile is not specified, a self-signed certificate will be used")
+		config, err = ctls.MakeSelfSignedServerTLSConfig("localhost", "127.0.0.1")
+	}
+	if err != nil {
+		return err
+	}
+
+	config.MinVersion = tls.VersionTLS13
+	config.NextProtos = []string{alpn}
+
+	if s.listener, err = quic.ListenAddr(s.opts.addr, config, &quic.Config{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// 等待连接
+func (s *server) serve() {
+	for {
+		conn, err := s.listener.Accept(context.Background())
+		if err != nil {
+			if !errors.Is(err, quic.ErrServerClosed) {
+				log.Warnf("quic accept error: %v", err)
+			}
+			return
+		}
+
+		xcall.Go(func() {
+			s.handshake(conn)
+		})
+	}
+}
+
+// 等待客户端打开控制流
+func (s *server) handshake(conn *quic.Conn) {
+	ctx, cancel := context.WithTimeout(conn.Context(), handshakeTimeout)
+	defer cancel()
+
+	stream, err := conn.AcceptStream(ctx)
+	if err != nil {
+		log.Warnf("quic accept control stream error: %v", err)
+		_ = conn.CloseWithError(0, "")
+		return
+	}
+
+	if err = s.connMgr.allocate(conn, stream); err != nil {
+		log.Errorf("connection allocate error: %v", err)
+		_ = conn.CloseWithError(0, err.Error())
+	}
+}
@@ -0,0 +1,560 @@
+package quic
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/dobyte/due/v2/utils/xnet"
+	"github.com/dobyte/due/v2/utils/xtime"
+	"github.com/quic-go/quic-go"
+)
+
+type serverConn struct {
+	id                int64          // 连接ID
+	uid               atomic.Int64   // 用户ID
+	attr              *attr          // 连接属性
+	state             atomic.Int32   // 连接状态
+	connMgr           *serverConnMgr // 连接管理
+	rw                sync.RWMutex   // 读写锁
+	wmu               sync.Mutex     // 控制流写入锁
+	conn              *quic.Conn     // QUIC源连接
+	stream            *quic.Stream   // 控制流
+	taskPool          sync.Pool      // 任务对象池
+	taskQueue         chan *task     // 任务队列
+	done              chan struct{}  // 写入完成信号
+	close             chan struct{}  // 关闭信号
+	lastHeartbeatTime atomic.Int64   // 上次心跳时间
+	authorizeTimer    atomic.Value   // 授权定时器
+}
+
+var _ network.Conn = &serverConn{}
+
+// ID 获取连接ID
+func (c *serverConn) ID() int64 {
+	return c.id
+}
+
+// UID 获取用户ID
+func (c *serverConn) UID() int64 {
+	return c.uid.Load()
+}
+
+// Attr 获取属性接口
+func (c *serverConn) Attr() network.Attr {
+	return c.attr
+}
+
+// Bind 绑定用户ID
+func (c *serverConn) Bind(uid int64) {
+	c.uid.Store(uid)
+
+	c.uncheckAuthorize()
+}
+
+// Unbind 解绑用户ID
+func (c *serverConn) Unbind() {
+	c.uid.Store(0)
+
+	c.checkAuthorize()
+}
+
+// Send 发送消息（同步）
+func (c *serverConn) Send(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	conn, stream := c.conn, c.stream
+	c.rw.RUnlock()
+
+	if conn == nil {
+		return errors.ErrConnectionClosed
+	}
+
+	if c.isLargeMessage(msg) {
+		return writeStream(conn, msg)
+	}
+
+	return c.doWriteControl(stream, msg)
+}
+
+// Push 发送消息（异步）
+func (c *serverConn) Push(msg []byte) error {
+	if err := c.checkState(); err != nil {
+		return err
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	if c.conn == nil {
+		return errors.ErrConnectionClosed
+	}
+
+	return c.doWriteToQueue(c.taskQueue, dataPacket, msg)
+}
+
+// State 获取连接状态
+func (c *serverConn) State() network.ConnState {
+	return network.ConnState(c.state.Load())
+}
+
+// Close 关闭连接
+func (c *serverConn) Close(force ...bool) error {
+	if len(force) > 0 && force[0] {
+		return c.forceClose(true)
+	} else {
+		return c.graceClose(true)
+	}
+}
+
+// LocalIP 获取本地IP
+func (c *serverConn) LocalIP() (string, error) {
+	addr, err := c.LocalAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// LocalAddr 获取本地地址
+func (c *serverConn) LocalAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	c.rw.RLock()
+	conn := c.conn
+	c.rw.RUnlock()
+
+	if conn == nil {
+		return nil, errors.ErrConnectionClosed
+	}
+
+	return conn.LocalAddr(), nil
+}
+
+// RemoteIP 获取远端IP
+func (c *serverConn) RemoteIP() (string, error) {
+	addr, err := c.RemoteAddr()
+	if err != nil {
+		return "", err
+	}
+
+	return xnet.ExtractIP(addr)
+}
+
+// RemoteAddr 获取远端地址
+// 客户端发生连接迁移后返回迁移后的地址
+func (c *serverConn) RemoteAddr() (net.Addr, error) {
+	if err := c.checkState(); err != nil {
+		return nil, err
+	}
+
+	c.rw.RLock()
+	conn := c.conn
+	c.rw.RUnlock()
+
+	if conn == nil {
+		return nil, errors.ErrConnectionClosed
+	}
+
+	return conn.RemoteAddr(), nil
+}
+
+// 检测连接状态
+func (c *serverConn) checkState() error {
+	switch c.State() {
+	case network.ConnHanged:
+		return errors.ErrConnectionHanged
+	case network.ConnClosed:
+		return errors.ErrConnectionClosed
+	default:
+		return nil
+	}
+}
+
+// 授权检查
+func (c *serverConn) checkAuthorize() {
+	if c.connMgr.server.opts.authorizeTimeout > 0 {
+		timer := c.authorizeTimer.Swap(time.AfterFunc(c.connMgr.server.opts.authorizeTimeout, func() {
+			if c.UID() != 0 {
+				return
+			}
+
+			c.forceClose(true)
+		}))
+		if t, ok := timer.(*time.Timer); ok && t != nil {
+			t.Stop()
+		}
+	}
+}
+
+// 取消授权检查
+func (c *serverConn) uncheckAuthorize() {
+	if c.connMgr.server.opts.authorizeTimeout > 0 {
+		timer := c.authorizeTimer.Swap((*time.Timer)(nil))
+
+		if t, ok := timer.(*time.Timer); ok && t != nil {
+			t.Stop()
+		}
+	}
+}
+
+// 初始化连接
+func (c *serverConn) init(cm *serverConnMgr, id int64, conn *quic.Conn, stream *quic.Stream) {
+	c.id = id
+	c.uid.Store(0)
+	c.attr = &attr{}
+	c.state.Store(int32(network.ConnOpened))
+	c.conn = conn
+	c.stream = stream
+	c.connMgr = cm
+	c.taskQueue = make(chan *task, c.connMgr.server.opts.writeQueueSize)
+	c.done = make(chan struct{})
+	c.close = make(chan struct{})
+	c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+	c.authorizeTimer.Store((*time.Timer)(nil))
+
+	xcall.Go(c.read)
+
+	xcall.Go(c.readStreams)
+
+	xcall.Go(c.write)
+
+	c.checkAuthorize()
+
+	if c.connMgr.server.connectHandler != nil {
+		c.connMgr.server.connectHandler(c)
+	}
+}
+
+// 优雅关闭
+func (c *serverConn) graceClose(isNeedRecycle bool) error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnHanged)) {
+		return errors.ErrConnectionNotOpened
+	}
+
+	c.uncheckAuthorize()
+
+	c.rw.RLock()
+	if c.conn == nil {
+		c.rw.RUnlock()
+		return errors.ErrConnectionClosed
+	}
+	c.taskQueue <- &task{typ: closeSig}
+	c.rw.RUnlock()
+
+	<-c.done
+
+	if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+		return errors.ErrConnectionNotHanged
+	}
+
+	return c.doClose(isNeedRecycle, true)
+}
+
+// 强制关闭
+func (c *serverConn) forceClose(isNeedRecycle bool) error {
+	if !c.state.CompareAndSwap(int32(network.ConnOpened), int32(network.ConnClosed)) {
+		if !c.state.CompareAndSwap(int32(network.ConnHanged), int32(network.ConnClosed)) {
+			return errors.ErrConnectionClosed
+		}
+	}
+
+	c.uncheckAuthorize()
+
+	return c.doClose(isNeedRecycle, false)
+}
+
+// 执行关闭操作
+// 优雅关闭时先关闭控制流的写入端，待客户端读取完剩余消息并关闭连接后再释放连接
+func (c *serverConn) doClose(isNeedRecycle bool, isGraceful bool) error {
+	c.rw.Lock()
+
+	if c.conn == nil {
+		c.rw.Unlock()
+		return errors.ErrConnectionClosed
+	}
+
+	close(c.taskQueue)
+	close(c.close)
+	close(c.done)
+	conn, stream := c.conn, c.stream
+	c.conn, c.stream = nil, nil
+	c.taskQueue = nil
+	c.rw.Unlock()
+
+	if isGraceful {
+		c.wmu.Lock()
+		_ = stream.Close()
+		c.wmu.Unlock()
+
+		timer := time.NewTimer(closeTimeout)
+		select {
+		case <-conn.Context().Done():
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+
+	err := conn.CloseWithError(0, "")
+
+	if c.connMgr.server.disconnectHandler != nil {
+		c.connMgr.server.disconnectHandler(c)
+	}
+
+	if isNeedRecycle {
+		c.connMgr.recycle(conn)
+	}
+
+	return err
+}
+
+// 读取控制流消息
+func (c *serverConn) read() {
+	stream := c.stream
+
+	for {
+		select {
+		case <-c.close:
+			return
+		default:
+			data, err := packet.ReadMessage(stream)
+			if err != nil {
+				_ = c.forceClose(true)
+				return
+			}
+
+			if !c.doHandleMessage(data) {
+				return
+			}
+		}
+	}
+}
+
+// 读取独立流消息
+func (c *serverConn) readStreams() {
+	conn := c.conn
+
+	acceptStreams(conn.Context(), conn, func(data []byte) {
+		c.doHandleMessage(data)
+	})
+}
+
+// 处理接收到的消息；连接已关闭时返回false
+func (c *serverConn) doHandleMessage(data []byte) bool {
+	if c.connMgr.server.opts.heartbeatInterval > 0 {
+		c.lastHeartbeatTime.Store(xtime.Now().UnixNano())
+	}
+
+	switch c.State() {
+	case network.ConnHanged:
+		return true
+	case network.ConnClosed:
+		return false
+	default:
+		// ignore
+	}
+
+	// ignore empty packet
+	if len(data) == 0 {
+		return true
+	}
+
+	isHeartbeat, err := packet.CheckHeartbeat(data)
+	if err != nil {
+		log.Errorf("check heartbeat message error: %v", err)
+		return true
+	}
+
+	// ignore heartbeat packet
+	if isHeartbeat {
+		// responsive heartbeat
+		if c.connMgr.server.opts.heartbeatMechanism == RespHeartbeat {
+			c.rw.RLock()
+			stream := c.stream
+			c.rw.RUnlock()
+
+			if stream != nil {
+				c.doSendHeartbeat(stream)
+			}
+		}
+	} else {
+		if c.connMgr.server.receiveHandler != nil {
+			c.connMgr.server.receiveHandler(c, data)
+		}
+	}
+
+	return true
+}
+
+// 写入消息
+func (c *serverConn) write() {
+	var (
+		conn   = c.conn
+		stream = c.stream
+		ticker *time.Ticker
+	)
+
+	if c.connMgr.server.opts.heartbeatInterval > 0 {
+		ticker = time.NewTicker(c.connMgr.server.opts.heartbeatInterval)
+		defer ticker.Stop()
+	} else {
+		ticker = &time.Ticker{C: make(chan time.Time, 1)}
+	}
+
+	for {
+		select {
+		case t, ok := <-c.taskQueue:
+			if !ok {
+				return
+			}
+
+			if !c.doWrite(conn, stream, t) {
+				return
+			}
+		case t, ok := <-ticker.C:
+			if !ok {
+				return
+			}
+
+			if !c.doHandleHeartbeat(stream, t) {
+				return
+			}
+		}
+	}
+}
+
+// 执行写入操作
+func (c *serverConn) doWrite(conn *quic.Conn, stream *quic.Stream, t *task) bool {
+	defer c.doRecycleToPool(t)
+
+	if t.typ == closeSig {
+		c.rw.RLock()
+		if c.conn != nil {
+			c.done <- struct{}{}
+		}
+		c.rw.RUnlock()
+		return false
+	}
+
+	if c.isClosed() {
+		return false
+	}
+
+	if msg := t.msg; c.isLargeMessage(msg) {
+		xcall.Go(func() {
+			if err := writeStream(conn, msg); err != nil && !c.isClosed() {
+				log.Errorf("write stream message error: %v", err)
+			}
+		})
+	} else {
+		if err := c.doWriteControl(stream, msg); err != nil && !c.isClosed() {
+			log.Errorf("write message error: %v", err)
+		}
+	}
+
+	return true
+}
+
+// 处理心跳
+func (c *serverConn) doHandleHeartbeat(stream *quic.Stream, t time.Time) bool {
+	deadline := t.Add(-2 * c.connMgr.server.opts.heartbeatInterval).UnixNano()
+
+	if c.lastHeartbeatTime.Load() < deadline {
+		log.Debugf("connection heartbeat timeout, cid: %d", c.id)
+		_ = c.forceClose(true)
+		return false
+	} else {
+		if c.isClosed() {
+			return false
+		}
+
+		if c.connMgr.server.opts.heartbeatMechanism == TickHeartbeat {
+			c.doSendHeartbeat(stream)
+		}
+
+		return true
+	}
+}
+
+// 是否已关闭
+func (c *serverConn) isClosed() bool {
+	return c.State() == network.ConnClosed
+}
+
+// 是否需要通过独立流发送
+func (c *serverConn) isLargeMessage(msg []byte) bool {
+	return c.connMgr.server.opts.streamThreshold > 0 && len(msg) >= c.connMgr.server.opts.streamThreshold
+}
+
+// 发送心跳包
+func (c *serverConn) doSendHeartbeat(stream *quic.Stream) {
+	if heartbeat, err := packet.PackHeartbeat(); err != nil {
+		log.Errorf("pack heartbeat message error: %v", err)
+	} else {
+		if err = c.doWriteControl(stream, heartbeat); err != nil && !c.isClosed() {
+			log.Errorf("write heartbeat message error: %v", err)
+		}
+	}
+}
+
+// 写入控制流
+func (c *serverConn) doWriteControl(stream *quic.Stream, msg []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	_, err := stream.Write(msg)
+
+	return err
+}
+
+// 回收任务到对象池
+func (c *serverConn) doRecycleToPool(t *task) {
+	t.msg = nil
+	c.taskPool.Put(t)
+}
+
+// 写入任务到队列
+func (c *serverConn) doWriteToQueue(queue chan *task, typ int8, msg ...[]byte) error {
+	t := c.taskPool.Get().(*task)
+	t.typ = typ
+	if len(msg) > 0 {
+		t.msg = msg[0]
+	}
+
+	metrics.NetworkWriteQueueDepth.Observe(float64(len(queue)), protocol)
+
+	if len(queue) == cap(queue) {
+		metrics.NetworkWriteQueueFull.Add(1, protocol)
+	}
+
+	if c.connMgr.server.opts.writeTimeout > 0 && len(queue) == cap(queue) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.connMgr.server.opts.writeTimeout)
+		defer cancel()
+
+		select {
+		case <-ctx.Done():
+			c.doRecycleToPool(t)
+			return ctx.Err()
+		case queue <- t:
+			return nil
+		}
+	}
+
+	queue <- t
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package quic
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/quic-go/quic-go"
+)
+
+type serverConnMgr struct {
+	id          atomic.Int64               // 连接ID
+	total       atomic.Int64               // 总连接数
+	server      *server                    // 服务器
+	rw          sync.RWMutex               // 锁
+	connections map[*quic.Conn]*serverConn // 连接
+}
+
+func newServerConnMgr(server *server) *serverConnMgr {
+	return &serverConnMgr{server: server, connections: make(map[*quic.Conn]*serverConn)}
+}
+
+// 关闭连接
+func (cm *serverConnMgr) close() {
+	cm.rw.RLock()
+	connections := make([]*serverConn, 0, len(cm.connections))
+	for _, conn := range cm.connections {
+		connections = append(connections, conn)
+	}
+	cm.rw.RUnlock()
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(connections))
+
+	for i := range connections {
+		conn := connections[i]
+
+		xcall.Go(func() {
+			_ = conn.Close()
+			wg.Done()
+		})
+	}
+
+	wg.Wait()
+}
+
+// 分配连接
+func (cm *serverConnMgr) allocate(c *quic.Conn, stream *quic.Stream) error {
+	if cm.total.Load() >= int64(cm.server.opts.maxConnNum) {
+		return errors.ErrTooManyConnection
+	}
+
+	conn := &serverConn{taskPool: sync.Pool{New: func() any { return &task{} }}}
+
+	cm.rw.Lock()
+	cm.connections[c] = conn
+	cm.rw.Unlock()
+
+	cm.total.Add(1)
+
+	conn.init(cm, cm.id.Add(1), c, stream)
+
+	return nil
+}
+
+// 回收连接
+func (cm *serverConnMgr) recycle(c *quic.Conn) {
+	cm.rw.Lock()
+	_, ok := cm.connections[c]
+	delete(cm.connections, c)
+	cm.rw.Unlock()
+
+	if ok {
+		cm.total.Add(-1)
+	}
+}
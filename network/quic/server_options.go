@@ -0,0 +1,207 @@
+package quic
+
+import (
+	"time"
+
+	"github.com/dobyte/due/v2/etc"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/utils/xconv"
+)
+
+const (
+	defaultServerAddr               = ":3553"
+	defaultServerMaxConnNum         = 5000
+	defaultServerWriteTimeout       = "0s"
+	defaultServerWriteQueueSize     = 1024
+	defaultServerStreamThreshold    = 0
+	defaultServerHeartbeatInterval  = "10s"
+	defaultServerHeartbeatMechanism = "resp"
+	defaultServerAuthorizeTimeout   = "0s"
+)
+
+const (
+	defaultServerAddrKey               = "etc.network.quic.server.addr"
+	defaultServerCertFileKey           = "etc.network.quic.server.certFile"
+	defaultServerKeyFileKey            = "etc.network.quic.server.keyFile"
+	defaultServerMaxConnNumKey         = "etc.network.quic.server.maxConnNum"
+	defaultServerWriteTimeoutKey       = "etc.network.quic.server.writeTimeout"
+	defaultServerWriteQueueSizeKey     = "etc.network.quic.server.writeQueueSize"
+	defaultServerStreamThresholdKey    = "etc.network.quic.server.streamThreshold"
+	defaultServerHeartbeatIntervalKey  = "etc.network.quic.server.heartbeatInterval"
+	defaultServerHeartbeatMechanismKey = "etc.network.quic.server.heartbeatMechanism"
+	defaultServerAuthorizeTimeoutKey   = "etc.network.quic.server.authorizeTimeout"
+)
+
+const (
+	RespHeartbeat HeartbeatMechanism = "resp" // 响应式心跳
+	TickHeartbeat HeartbeatMechanism = "tick" // 主动定时心跳
+)
+
+type HeartbeatMechanism string
+
+type ServerOption func(o *serverOptions)
+
+type serverOptions struct {
+	addr               string             // 监听地址，默认0.0.0.0:3553
+	certFile           string             // 证书文件
+	keyFile            string             // 秘钥文件
+	maxConnNum         int                // 最大连接数，默认5000
+	writeTimeout       time.Duration      // 写超时时间，默认无超时
+	writeQueueSize     int                // 写队列大小，默认1024
+	streamThreshold    int                // 独立流发送阈值，大于等于该字节数的消息通过独立的单向流发送，默认0，不启用
+	heartbeatInterval  time.Duration      // 心跳检测间隔时间，默认10s
+	heartbeatMechanism HeartbeatMechanism // 心跳机制，默认resp
+	authorizeTimeout   time.Duration      // 授权超时时间，默认0s，不检测
+}
+
+func defaultServerOptions() *serverOptions {
+	opts := &serverOptions{}
+	opts.certFile = etc.Get(defaultServerCertFileKey).String()
+	opts.keyFile = etc.Get(defaultServerKeyFileKey).String()
+
+	if addr := etc.Get(defaultServerAddrKey, defaultServerAddr).String(); addr != "" {
+		opts.addr = addr
+	} else {
+		opts.addr = defaultServerAddr
+	}
+
+	if maxConnNum := etc.Get(defaultServerMaxConnNumKey, defaultServerMaxConnNum).Int(); maxConnNum > 0 {
+		opts.maxConnNum = maxConnNum
+	} else {
+		opts.maxConnNum = defaultServerMaxConnNum
+	}
+
+	if writeTimeout := etc.Get(defaultServerWriteTimeoutKey, defaultServerWriteTimeout).Duration(); writeTimeout >= 0 {
+		opts.writeTimeout = writeTimeout
+	} else {
+		opts.writeTimeout = xconv.Duration(defaultServerWriteTimeout)
+	}
+
+	if writeQueueSize := etc.Get(defaultServerWriteQueueSizeKey, defaultServerWriteQueueSize).Int(); writeQueueSize > 0 {
+		opts.writeQueueSize = writeQueueSize
+	} else {
+		opts.writeQueueSize = defaultServerWriteQueueSize
+	}
+
+	if streamThreshold := etc.Get(defaultServerStreamThresholdKey, defaultServerStreamThreshold).Int(); streamThreshold >= 0 {
+		opts.streamThreshold = streamThreshold
+	} else {
+		opts.streamThreshold = defaultServerStreamThreshold
+	}
+
+	if heartbeatInterval := etc.Get(defaultServerHeartbeatIntervalKey, defaultServerHeartbeatInterval).Duration(); heartbeatInterval >= 0 {
+		opts.heartbeatInterval = heartbeatInterval
+	} else {
+		opts.heartbeatInterval = xconv.Duration(defaultServerHeartbeatInterval)
+	}
+
+	switch heartbeatMechanism := HeartbeatMechanism(etc.Get(defaultServerHeartbeatMechanismKey, defaultServerHeartbeatMechanism).String()); heartbeatMechanism {
+	case RespHeartbeat, TickHeartbeat:
+		opts.heartbeatMechanism = heartbeatMechanism
+	default:
+		opts.heartbeatMechanism = defaultServerHeartbeatMechanism
+	}
+
+	if authorizeTimeout := etc.Get(defaultServerAuthorizeTimeoutKey, defaultServerAuthorizeTimeout).Duration(); authorizeTimeout >= 0 {
+		opts.authorizeTimeout = authorizeTimeout
+	} else {
+		opts.authorizeTimeout = xconv.Duration(defaultServerAuthorizeTimeout)
+	}
+
+	return opts
+}
+
+// WithServerAddr 设置监听地址
+func WithServerAddr(addr string) ServerOption {
+	return func(o *serverOptions) {
+		if addr != "" {
+			o.addr = addr
+		} else {
+			log.Warnf("the specified addr is empty and will be ignored")
+		}
+	}
+}
+
+// WithServerCredentials 设置服务器证书和秘钥
+// 未设置时服务器启动时将生成自签名证书，仅适用于开发及测试环境
+func WithServerCredentials(certFile, keyFile string) ServerOption {
+	return func(o *serverOptions) {
+		if certFile != "" && keyFile != "" {
+			o.certFile, o.keyFile = certFile, keyFile
+		} else {
+			log.Warnf("the specified certFile or keyFile is empty and will be ignored")
+		}
+	}
+}
+
+// WithServerMaxConnNum 设置连接的最大连接数
+func WithServerMaxConnNum(maxConnNum int) ServerOption {
+	return func(o *serverOptions) {
+		if maxConnNum > 0 {
+			o.maxConnNum = maxConnNum
+		} else {
+			log.Warnf("the specified maxConnNum is less than zero and will be ignored")
+		}
+	}
+}
+
+// WithServerWriteTimeout 设置写超时时间
+func WithServerWriteTimeout(writeTimeout time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		if writeTimeout >= 0 {
+			o.writeTimeout = writeTimeout
+		} else {
+			log.Warnf("the specified writeTimeout is less than zero and will be ignored")
+		}
+	}
+}
+
+// WithServerWriteQueueSize 设置写入队列大小
+func WithServerWriteQueueSize(writeQueueSize int) ServerOption {
+	return func(o *serverOptions) {
+		if writeQueueSize > 0 {
+			o.writeQueueSize = writeQueueSize
+		} else {
+			log.Warnf("the specified writeQueueSize is less than zero and will be ignored")
+		}
+	}
+}
+
+// WithServerStreamThreshold 设置独立流发送阈值
+// 大于等于该字节数的消息将通过独立的单向流发送，避免大消息阻塞控制流；独立流之间不保证消息顺序
+func WithServerStreamThreshold(streamThreshold int) ServerOption {
+	return func(o *serverOptions) {
+		if streamThreshold >= 0 {
+			o.streamThreshold = streamThreshold
+		} else {
+			log.Warnf("the specified streamThreshold is less than zero and will be ignored")
+		}
+	}
+}
+
+// WithServerHeartbeatInterval 设置心跳检测间隔时间
+func WithServerHeartbeatInterval(heartbeatInterval time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		if heartbeatInterval >= 0 {
+			o.heartbeatInterval = heartbeatInterval
+		} else {
+			log.Warnf("the specified heartbeatInterval is less than zero and will be ignored")
+		}
+	}
+}
+
+// WithServerHeartbeatMechanism 设置心跳机制
+func WithServerHeartbeatMechanism(heartbeatMechanism HeartbeatMechanism) ServerOption {
+	return func(o *serverOptions) { o.heartbeatMechanism = heartbeatMechanism }
+}
+
+// WithServerAuthorizeTimeout 设置授权超时时间
+func WithServerAuthorizeTimeout(authorizeTimeout time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		if authorizeTimeout >= 0 {
+			o.authorizeTimeout = authorizeTimeout
+		} else {
+			log.Warnf("the specified authorizeTimeout is less than zero and will be ignored")
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package quic_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/network/quic/v2"
+	"github.com/dobyte/due/v2/network"
+	"github.com/dobyte/due/v2/packet"
+)
+
+func TestServer(t *testing.T) {
+	connected := make(chan network.Conn, 1)
+
+	server := quic.NewServer(
+		quic.WithServerAddr("127.0.0.1:0"),
+		quic.WithServerStreamThreshold(1024),
+		quic.WithServerHeartbeatInterval(200*time.Millisecond),
+	)
+	server.OnConnect(func(conn network.Conn) {
+		connected <- conn
+	})
+	server.OnReceive(func(conn network.Conn, data []byte) {
+		if err := conn.Push(data); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan *packet.Message, 10)
+	disconnected := make(chan struct{})
+
+	client := quic.NewClient(
+		quic.WithClientAddr(server.Addr()),
+		quic.WithClientInsecureSkipVerify(true),
+		quic.WithClientStreamThreshold(1024),
+		quic.WithClientHeartbeatInterval(200*time.Millisecond),
+	)
+	client.OnReceive(func(conn network.Conn, data []byte) {
+		message, err := packet.UnpackMessage(data)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		received <- message
+	})
+	client.OnDisconnect(func(conn network.Conn) {
+		close(disconnected)
+	})
+
+	conn, err := client.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := <-connected
+
+	expect := func(route int32, buffer []byte) {
+		t.Helper()
+
+		msg, err := packet.PackMessage(&packet.Message{Route: route, Buffer: buffer})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = conn.Push(msg); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case message := <-received:
+			if message.Route != route || !bytes.Equal(message.Buffer, buffer) {
+				t.Fatalf("unexpected message, route: %d, size: %d", message.Route, len(message.Buffer))
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("wait message timeout, route: %d", route)
+		}
+	}
+
+	// 控制流
+	expect(1, []byte("hello"))
+
+	// 独立流
+	expect(2, bytes.Repeat([]byte("x"), 4000))
+
+	// 连接迁移
+	before, err := sc.RemoteAddr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err = conn.(quic.Migrator).Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(3, []byte("migrated"))
+
+	after, err := sc.RemoteAddr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if before.String() == after.String() {
+		t.Fatalf("remote address should be changed after migration, addr: %s", after)
+	}
+
+	// 多个心跳周期后连接仍应保持
+	time.Sleep(time.Second)
+
+	if conn.State() != network.ConnOpened {
+		t.Fatalf("connection should be opened, state: %v", conn.State())
+	}
+
+	if err = server.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-disconnected:
+	case <-time.After(3 * time.Second):
+		t.Fatal("wait disconnect timeout")
+	}
+}
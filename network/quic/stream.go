@@ -0,0 +1,49 @@
+package quic
+
+import (
+	"context"
+
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/packet"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/quic-go/quic-go"
+)
+
+// 通过独立的单向流发送消息，每个单向流仅承载一个数据包
+func writeStream(conn *quic.Conn, msg []byte) error {
+	stream, err := conn.OpenUniStream()
+	if err != nil {
+		// 超出对端允许的并发流数量时等待可用的流
+		if stream, err = conn.OpenUniStreamSync(conn.Context()); err != nil {
+			return err
+		}
+	}
+
+	if _, err = stream.Write(msg); err != nil {
+		stream.CancelWrite(0)
+		return err
+	}
+
+	return stream.Close()
+}
+
+// 接收对端通过独立的单向流发送的消息
+func acceptStreams(ctx context.Context, conn *quic.Conn, handler func(data []byte)) {
+	for {
+		stream, err := conn.AcceptUniStream(ctx)
+		if err != nil {
+			return
+		}
+
+		xcall.Go(func() {
+			data, err := packet.ReadMessage(stream)
+			if err != nil {
+				log.Warnf("read stream message failed: %v", err)
+				stream.CancelRead(0)
+				return
+			}
+
+			handler(data)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
 	"github.com/dobyte/due/v2/network"
 	"github.com/dobyte/due/v2/packet"
 	"github.com/dobyte/due/v2/utils/xcall"
@@ -467,6 +468,12 @@ func (c *serverConn) doWriteToQueue(queue chan *task, typ int8, msg ...[]byte) e
 		t.msg = msg[0]
 	}
 
+	metrics.NetworkWriteQueueDepth.Observe(float64(len(queue)), protocol)
+
+	if len(queue) == cap(queue) {
+		metrics.NetworkWriteQueueFull.Add(1, protocol)
+	}
+
 	if c.connMgr.server.opts.writeTimeout > 0 && len(queue) == cap(queue) {
 		ctx, cancel := context.WithTimeout(context.Background(), c.connMgr.server.opts.writeTimeout)
 		defer cancel()
@@ -0,0 +1,256 @@
+package packet
+
+import (
+	"crypto/cipher"
+	"sync"
+
+	"github.com/dobyte/due/v2/errors"
+)
+
+const (
+	encryptBit   = 0x08 // 加密标识，占用扩展操作码（extcode）的第4位
+	handshakeBit = 0x10 // 握手标识，占用扩展操作码（extcode）的第5位
+)
+
+const defaultCounterBytes = 8
+
+const (
+	replayBlockBits  = 64                                   // 重放窗口单个块的位数
+	replayBlocks     = 32                                   // 重放窗口的块数
+	replayWindowSize = (replayBlocks - 1) * replayBlockBits // 重放窗口大小，允许乱序到达的最大计数器跨度
+)
+
+// encrypted data packet
+// --------------------------------------------------------------------------------------------------------------------------------
+// | size(4 byte) | header(1 byte) | route(n byte) | seq(m byte) | counter(8 byte) | ciphertext(x byte) | tag(AEAD overhead byte) |
+// --------------------------------------------------------------------------------------------------------------------------------
+
+type SecurePacker interface {
+	// PackHandshake 打包握手消息
+	PackHandshake(payload []byte) ([]byte, error)
+	// CheckHandshake 检测握手消息，返回是否为握手消息及握手内容
+	CheckHandshake(data []byte) (bool, []byte, error)
+	// Seal 加密数据包
+	Seal(cipher *Cipher, data []byte) ([]byte, error)
+	// Open 解密数据包
+	Open(cipher *Cipher, data []byte) ([]byte, error)
+}
+
+// Cipher 连接会话密码器
+// 收发两个方向使用独立的密钥，随机数由单调递增的计数器构造
+// 接收方通过滑动窗口记录已接收的计数器，允许窗口内的数据包乱序到达（如QUIC多路流），拒绝重复或过旧的数据包以防止重放
+type Cipher struct {
+	sealer cipher.AEAD
+	opener cipher.AEAD
+	mu     sync.Mutex
+	sent   uint64
+	recv   uint64               // 已接收的最大计数器
+	window [replayBlocks]uint64 // 重放窗口位图
+}
+
+// NewCipher 创建会话密码器，sealer用于加密发送的数据包，opener用于解密接收的数据包
+func NewCipher(sealer, opener cipher.AEAD) *Cipher {
+	return &Cipher{sealer: sealer, opener: opener}
+}
+
+// 生成发送计数器
+func (c *Cipher) nextSent() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent++
+
+	return c.sent
+}
+
+// 检测接收计数器是否可用，计数器为0、过旧或已接收时返回false
+func (c *Cipher) checkRecv(counter uint64) bool {
+	if counter == 0 || counter+replayWindowSize < c.recv {
+		return false
+	}
+
+	if counter > c.recv {
+		return true
+	}
+
+	return c.window[(counter/replayBlockBits)%replayBlocks]&(1<<(counter%replayBlockBits)) == 0
+}
+
+// 标记计数器已接收，向前滑动窗口时清理滑出窗口的块
+func (c *Cipher) markRecv(counter uint64) {
+	index := counter / replayBlockBits
+
+	if counter > c.recv {
+		current := c.recv / replayBlockBits
+
+		diff := index - current
+		if diff > replayBlocks {
+			diff = replayBlocks
+		}
+
+		for i := uint64(1); i <= diff; i++ {
+			c.window[(current+i)%replayBlocks] = 0
+		}
+
+		c.recv = counter
+	}
+
+	c.window[index%replayBlocks] |= 1 << (counter % replayBlockBits)
+}
+
+// 构造随机数
+func makeNonce(size int, counter uint64) []byte {
+	nonce := make([]byte, size)
+
+	for i := 0; i < defaultCounterBytes && i < size; i++ {
+		nonce[size-1-i] = byte(counter >> (8 * i))
+	}
+
+	return nonce
+}
+
+// PackHandshake 打包握手消息
+func PackHandshake(payload []byte) ([]byte, error) {
+	if p, ok := globalPacker.(SecurePacker); ok {
+		return p.PackHandshake(payload)
+	}
+
+	return nil, errors.ErrInvalidPacker
+}
+
+// CheckHandshake 检测握手消息
+func CheckHandshake(data []byte) (bool, []byte, error) {
+	if p, ok := globalPacker.(SecurePacker); ok {
+		return p.CheckHandshake(data)
+	}
+
+	return false, nil, errors.ErrInvalidPacker
+}
+
+// Seal 加密数据包
+func Seal(cipher *Cipher, data []byte) ([]byte, error) {
+	if p, ok := globalPacker.(SecurePacker); ok {
+		return p.Seal(cipher, data)
+	}
+
+	return nil, errors.ErrInvalidPacker
+}
+
+// Open 解密数据包
+func Open(cipher *Cipher, data []byte) ([]byte, error) {
+	if p, ok := globalPacker.(SecurePacker); ok {
+		return p.Open(cipher, data)
+	}
+
+	return nil, errors.ErrInvalidPacker
+}
+
+// PackHandshake 打包握手消息
+func (p *defaultPacker) PackHandshake(payload []byte) ([]byte, error) {
+	ln := defaultSizeBytes + defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes
+	data := make([]byte, ln+len(payload))
+
+	p.opts.byteOrder.PutUint32(data, uint32(len(data)-defaultSizeBytes))
+	data[defaultSizeBytes] = dataBit | handshakeBit
+	copy(data[ln:], payload)
+
+	return data, nil
+}
+
+// CheckHandshake 检测握手消息
+func (p *defaultPacker) CheckHandshake(data []byte) (bool, []byte, error) {
+	ln := defaultSizeBytes + defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes
+
+	if len(data) < defaultSizeBytes+defaultHeaderBytes {
+		return false, nil, errors.ErrInvalidMessage
+	}
+
+	if data[defaultSizeBytes]&(heartbeatBit|handshakeBit) != handshakeBit {
+		return false, nil, nil
+	}
+
+	if len(data) < ln {
+		return false, nil, errors.ErrInvalidMessage
+	}
+
+	return true, data[ln:], nil
+}
+
+// Seal 加密数据包，心跳包不进行加密
+func (p *defaultPacker) Seal(cipher *Cipher, data []byte) ([]byte, error) {
+	ln := defaultSizeBytes + defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes
+
+	if len(data) < defaultSizeBytes+defaultHeaderBytes {
+		return nil, errors.ErrInvalidMessage
+	}
+
+	if data[defaultSizeBytes]&heartbeatBit == heartbeatBit {
+		return data, nil
+	}
+
+	if len(data) < ln {
+		return nil, errors.ErrInvalidMessage
+	}
+
+	var (
+		counter = cipher.nextSent()
+		nonce   = makeNonce(cipher.sealer.NonceSize(), counter)
+		buf     = make([]byte, ln+defaultCounterBytes, ln+defaultCounterBytes+len(data)-ln+cipher.sealer.Overhead())
+	)
+
+	copy(buf, data[:ln])
+	buf[defaultSizeBytes] |= encryptBit
+	p.opts.byteOrder.PutUint64(buf[ln:], counter)
+
+	aad := make([]byte, ln-defaultSizeBytes+defaultCounterBytes)
+	copy(aad, buf[defaultSizeBytes:])
+
+	buf = cipher.sealer.Seal(buf, nonce, data[ln:], aad)
+
+	p.opts.byteOrder.PutUint32(buf, uint32(len(buf)-defaultSizeBytes))
+
+	return buf, nil
+}
+
+// Open 解密数据包，心跳包无需解密
+func (p *defaultPacker) Open(cipher *Cipher, data []byte) ([]byte, error) {
+	ln := defaultSizeBytes + defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes
+
+	if len(data) < defaultSizeBytes+defaultHeaderBytes {
+		return nil, errors.ErrInvalidMessage
+	}
+
+	if data[defaultSizeBytes]&heartbeatBit == heartbeatBit {
+		return data, nil
+	}
+
+	if data[defaultSizeBytes]&encryptBit != encryptBit || len(data) < ln+defaultCounterBytes+cipher.opener.Overhead() {
+		return nil, errors.ErrInvalidMessage
+	}
+
+	counter := p.opts.byteOrder.Uint64(data[ln:])
+
+	cipher.mu.Lock()
+	defer cipher.mu.Unlock()
+
+	if !cipher.checkRecv(counter) {
+		return nil, errors.ErrReplayedMessage
+	}
+
+	nonce := makeNonce(cipher.opener.NonceSize(), counter)
+
+	plaintext, err := cipher.opener.Open(nil, nonce, data[ln+defaultCounterBytes:], data[defaultSizeBytes:ln+defaultCounterBytes])
+	if err != nil {
+		return nil, errors.ErrInvalidMessage
+	}
+
+	cipher.markRecv(counter)
+
+	buf := make([]byte, ln+len(plaintext))
+	copy(buf, data[:ln])
+	copy(buf[ln:], plaintext)
+	buf[defaultSizeBytes] &^= encryptBit
+	p.opts.byteOrder.PutUint32(buf, uint32(len(buf)-defaultSizeBytes))
+
+	return buf, nil
+}
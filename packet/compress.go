@@ -0,0 +1,204 @@
+package packet
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// 压缩算法占用扩展操作码（extcode）的低3位
+const compressMask = 0x07
+
+type Compression uint8
+
+const (
+	NoCompression Compression = iota // 不压缩
+	Gzip                             // gzip压缩
+	Deflate                          // deflate压缩
+	Zstd                             // zstd压缩
+	Snappy                           // snappy压缩
+)
+
+const (
+	gzipName    = "gzip"
+	deflateName = "deflate"
+	zstdName    = "zstd"
+	snappyName  = "snappy"
+)
+
+var (
+	gzipWriters    = sync.Pool{New: func() any { w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression); return w }}
+	gzipReaders    = sync.Pool{}
+	deflateWriters = sync.Pool{New: func() any { w, _ := flate.NewWriter(nil, flate.DefaultCompression); return w }}
+	deflateReaders = sync.Pool{}
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	zstdReaders    = sync.Pool{}
+)
+
+// ParseCompression 解析压缩算法名称
+func ParseCompression(name string) Compression {
+	switch strings.ToLower(name) {
+	case gzipName:
+		return Gzip
+	case deflateName:
+		return Deflate
+	case zstdName:
+		return Zstd
+	case snappyName:
+		return Snappy
+	default:
+		return NoCompression
+	}
+}
+
+// String 获取压缩算法名称
+func (c Compression) String() string {
+	switch c {
+	case Gzip:
+		return gzipName
+	case Deflate:
+		return deflateName
+	case Zstd:
+		return zstdName
+	case Snappy:
+		return snappyName
+	default:
+		return ""
+	}
+}
+
+// 压缩数据
+func compress(c Compression, data []byte) ([]byte, error) {
+	switch c {
+	case Gzip:
+		buf := &bytes.Buffer{}
+		w := gzipWriters.Get().(*gzip.Writer)
+		defer gzipWriters.Put(w)
+
+		w.Reset(buf)
+
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	case Deflate:
+		buf := &bytes.Buffer{}
+		w := deflateWriters.Get().(*flate.Writer)
+		defer deflateWriters.Put(w)
+
+		w.Reset(buf)
+
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	case Zstd:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	case Snappy:
+		return s2.EncodeSnappy(nil, data), nil
+	default:
+		return nil, errors.ErrInvalidCompression
+	}
+}
+
+// 解压数据，解压后的数据长度不能超过limit
+func decompress(c Compression, data []byte, limit int) ([]byte, error) {
+	switch c {
+	case Gzip:
+		var (
+			r   *gzip.Reader
+			err error
+		)
+
+		if v := gzipReaders.Get(); v != nil {
+			r = v.(*gzip.Reader)
+			err = r.Reset(bytes.NewReader(data))
+		} else {
+			r, err = gzip.NewReader(bytes.NewReader(data))
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReaders.Put(r)
+
+		return readLimit(r, limit)
+	case Deflate:
+		var r io.ReadCloser
+
+		if v := deflateReaders.Get(); v != nil {
+			r = v.(io.ReadCloser)
+
+			if err := r.(flate.Resetter).Reset(bytes.NewReader(data), nil); err != nil {
+				return nil, err
+			}
+		} else {
+			r = flate.NewReader(bytes.NewReader(data))
+		}
+		defer deflateReaders.Put(r)
+
+		return readLimit(r, limit)
+	case Zstd:
+		var (
+			r   *zstd.Decoder
+			err error
+		)
+
+		// 数据可能包含多个连续的帧，需按流式读取限制解压后的总长度
+		if v := zstdReaders.Get(); v != nil {
+			r = v.(*zstd.Decoder)
+			err = r.Reset(bytes.NewReader(data))
+		} else {
+			r, err = zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1))
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer zstdReaders.Put(r)
+
+		return readLimit(r, limit)
+	case Snappy:
+		n, err := s2.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if n > limit {
+			return nil, errors.ErrMessageTooLarge
+		}
+
+		return s2.Decode(nil, data)
+	default:
+		return nil, errors.ErrInvalidCompression
+	}
+}
+
+// 限制读取长度
+func readLimit(r io.Reader, limit int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > limit {
+		return nil, errors.ErrMessageTooLarge
+	}
+
+	return data, nil
+}
@@ -29,14 +29,17 @@ const (
 	defaultBufferBytes        = 5000
 	defaultHeartbeatTime      = false
 	defaultHeartbeatTimeBytes = 8
+	defaultCompressThreshold  = 1024
 )
 
 const (
-	defaultEndianKey        = "etc.packet.byteOrder"
-	defaultRouteBytesKey    = "etc.packet.routeBytes"
-	defaultSeqBytesKey      = "etc.packet.seqBytes"
-	defaultBufferBytesKey   = "etc.packet.bufferBytes"
-	defaultHeartbeatTimeKey = "etc.packet.heartbeatTime"
+	defaultEndianKey            = "etc.packet.byteOrder"
+	defaultRouteBytesKey        = "etc.packet.routeBytes"
+	defaultSeqBytesKey          = "etc.packet.seqBytes"
+	defaultBufferBytesKey       = "etc.packet.bufferBytes"
+	defaultHeartbeatTimeKey     = "etc.packet.heartbeatTime"
+	defaultCompressKey          = "etc.packet.compress"
+	defaultCompressThresholdKey = "etc.packet.compressThreshold"
 )
 
 type options struct {
@@ -59,17 +62,27 @@ type options struct {
 	// 是否携带心跳时间
 	// 默认为false
 	heartbeatTime bool
+
+	// 压缩算法，压缩算法标识将写入扩展操作码（extcode）的低3位
+	// 默认为不压缩
+	compression Compression
+
+	// 压缩阈值，消息字节数达到该值时才进行压缩
+	// 默认为1024字节
+	compressThreshold int
 }
 
 type Option func(o *options)
 
 func defaultOptions() *options {
 	opts := &options{
-		byteOrder:     binary.BigEndian,
-		routeBytes:    etc.Get(defaultRouteBytesKey, defaultRouteBytes).Int(),
-		seqBytes:      etc.Get(defaultSeqBytesKey, defaultSeqBytes).Int(),
-		bufferBytes:   etc.Get(defaultBufferBytesKey, defaultBufferBytes).Int(),
-		heartbeatTime: etc.Get(defaultHeartbeatTimeKey, defaultHeartbeatTime).Bool(),
+		byteOrder:         binary.BigEndian,
+		routeBytes:        etc.Get(defaultRouteBytesKey, defaultRouteBytes).Int(),
+		seqBytes:          etc.Get(defaultSeqBytesKey, defaultSeqBytes).Int(),
+		bufferBytes:       etc.Get(defaultBufferBytesKey, defaultBufferBytes).Int(),
+		heartbeatTime:     etc.Get(defaultHeartbeatTimeKey, defaultHeartbeatTime).Bool(),
+		compression:       ParseCompression(etc.Get(defaultCompressKey).String()),
+		compressThreshold: etc.Get(defaultCompressThresholdKey, defaultCompressThreshold).Int(),
 	}
 
 	endian := etc.Get(defaultEndianKey, bigEndian).String()
@@ -107,3 +120,13 @@ func WithBufferBytes(bufferBytes int) Option {
 func WithHeartbeatTime(heartbeatTime bool) Option {
 	return func(o *options) { o.heartbeatTime = heartbeatTime }
 }
+
+// WithCompression 设置压缩算法
+func WithCompression(compression Compression) Option {
+	return func(o *options) { o.compression = compression }
+}
+
+// WithCompressThreshold 设置压缩阈值
+func WithCompressThreshold(compressThreshold int) Option {
+	return func(o *options) { o.compressThreshold = compressThreshold }
+}
@@ -47,6 +47,12 @@ type Packer interface {
 	CheckHeartbeat(data []byte) (bool, error)
 }
 
+// HeaderPacker 支持仅解包消息头的打包器，未实现时将完整解包消息
+type HeaderPacker interface {
+	// UnpackHeader 解包消息头，仅解析路由和序列号，不解析（解压）消息体
+	UnpackHeader(data []byte) (*Message, error)
+}
+
 type defaultPacker struct {
 	opts      *options
 	heartbeat []byte
@@ -120,9 +126,14 @@ func (p *defaultPacker) PackBuffer(message *Message) (*buffer.NocopyBuffer, erro
 		return nil, errors.ErrMessageTooLarge
 	}
 
+	header, data, err := p.compress(message.Buffer)
+	if err != nil {
+		return nil, err
+	}
+
 	writer := buffer.MallocWriter(defaultSizeBytes + defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes)
-	writer.WriteInt32s(p.opts.byteOrder, int32(defaultHeaderBytes+p.opts.routeBytes+p.opts.seqBytes+len(message.Buffer)))
-	writer.WriteInt8s(int8(dataBit))
+	writer.WriteInt32s(p.opts.byteOrder, int32(defaultHeaderBytes+p.opts.routeBytes+p.opts.seqBytes+len(data)))
+	writer.WriteUint8s(header)
 
 	switch p.opts.routeBytes {
 	case 1:
@@ -142,7 +153,7 @@ func (p *defaultPacker) PackBuffer(message *Message) (*buffer.NocopyBuffer, erro
 		writer.WriteInt32s(p.opts.byteOrder, message.Seq)
 	}
 
-	return buffer.NewNocopyBuffer(writer, message.Buffer), nil
+	return buffer.NewNocopyBuffer(writer, data), nil
 }
 
 // ReadMessage 读取消息
@@ -224,19 +235,24 @@ func (p *defaultPacker) PackMessage(message *Message) ([]byte, error) {
 		return nil, errors.ErrMessageTooLarge
 	}
 
+	header, data, err := p.compress(message.Buffer)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
-		size = defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes + len(message.Buffer)
+		size = defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes + len(data)
 		buf  = &bytes.Buffer{}
 	)
 
 	buf.Grow(size + defaultSizeBytes)
 
-	err := binary.Write(buf, p.opts.byteOrder, int32(size))
+	err = binary.Write(buf, p.opts.byteOrder, int32(size))
 	if err != nil {
 		return nil, err
 	}
 
-	err = binary.Write(buf, p.opts.byteOrder, int8(dataBit))
+	err = binary.Write(buf, p.opts.byteOrder, header)
 	if err != nil {
 		return nil, err
 	}
@@ -265,7 +281,7 @@ func (p *defaultPacker) PackMessage(message *Message) ([]byte, error) {
 		return nil, err
 	}
 
-	err = binary.Write(buf, p.opts.byteOrder, message.Buffer)
+	err = binary.Write(buf, p.opts.byteOrder, data)
 	if err != nil {
 		return nil, err
 	}
@@ -275,6 +291,16 @@ func (p *defaultPacker) PackMessage(message *Message) ([]byte, error) {
 
 // UnpackMessage 解包消息
 func (p *defaultPacker) UnpackMessage(data []byte) (*Message, error) {
+	return p.unpack(data, true)
+}
+
+// UnpackHeader 解包消息头，仅解析路由和序列号，不解析（解压）消息体
+func (p *defaultPacker) UnpackHeader(data []byte) (*Message, error) {
+	return p.unpack(data, false)
+}
+
+// 解包消息
+func (p *defaultPacker) unpack(data []byte, body bool) (*Message, error) {
 	var (
 		ln     = defaultSizeBytes + defaultHeaderBytes + p.opts.routeBytes + p.opts.seqBytes
 		reader = bytes.NewReader(data)
@@ -354,7 +380,17 @@ func (p *defaultPacker) UnpackMessage(data []byte) (*Message, error) {
 		}
 	}
 
-	message.Buffer = data[ln:]
+	if !body {
+		return message, nil
+	}
+
+	if c := Compression(header & compressMask); c != NoCompression {
+		if message.Buffer, err = decompress(c, data[ln:], p.opts.bufferBytes); err != nil {
+			return nil, err
+		}
+	} else {
+		message.Buffer = data[ln:]
+	}
 
 	return message, nil
 }
@@ -414,6 +450,25 @@ func (p *defaultPacker) CheckHeartbeat(data []byte) (bool, error) {
 	return header&heartbeatBit == heartbeatBit, nil
 }
 
+// 压缩消息，返回消息头与压缩后的消息
+func (p *defaultPacker) compress(data []byte) (uint8, []byte, error) {
+	if p.opts.compression == NoCompression || len(data) < p.opts.compressThreshold {
+		return dataBit, data, nil
+	}
+
+	compressed, err := compress(p.opts.compression, data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// 压缩后体积未减小时按原始数据发送
+	if len(compressed) >= len(data) {
+		return dataBit, data, nil
+	}
+
+	return dataBit | uint8(p.opts.compression)&compressMask, compressed, nil
+}
+
 // 构建心跳包
 func makeHeartbeat(byteOrder binary.ByteOrder) []byte {
 	buf := bytes.NewBuffer(nil)
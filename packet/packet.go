@@ -47,6 +47,15 @@ func UnpackMessage(data []byte) (*Message, error) {
 	return globalPacker.UnpackMessage(data)
 }
 
+// UnpackHeader 解包消息头，仅解析路由和序列号，不解析（解压）消息体；打包器未实现HeaderPacker时将完整解包消息
+func UnpackHeader(data []byte) (*Message, error) {
+	if p, ok := globalPacker.(HeaderPacker); ok {
+		return p.UnpackHeader(data)
+	}
+
+	return globalPacker.UnpackMessage(data)
+}
+
 // PackHeartbeat 打包心跳
 func PackHeartbeat() ([]byte, error) {
 	return globalPacker.PackHeartbeat()
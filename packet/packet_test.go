@@ -2,10 +2,15 @@ package packet_test
 
 import (
 	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
 	"testing"
 
+	"github.com/dobyte/due/v2/errors"
 	"github.com/dobyte/due/v2/packet"
 	"github.com/dobyte/due/v2/utils/xrand"
+	"github.com/klauspost/compress/zstd"
 )
 
 var packer = packet.NewPacker(
@@ -203,3 +208,135 @@ func BenchmarkDefaultPacker_UnpackMessage(b *testing.B) {
 		}
 	}
 }
+
+func TestDefaultPacker_Compression(t *testing.T) {
+	data := bytes.Repeat([]byte("hello world"), 200)
+
+	for _, compression := range []packet.Compression{packet.Gzip, packet.Deflate, packet.Zstd, packet.Snappy} {
+		packer := packet.NewPacker(
+			packet.WithCompression(compression),
+			packet.WithCompressThreshold(64),
+			packet.WithBufferBytes(len(data)),
+		)
+
+		msg, err := packer.PackMessage(&packet.Message{Seq: 1, Route: 1, Buffer: data})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		message, err := packer.UnpackMessage(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(message.Buffer, data) {
+			t.Fatalf("%s: unpacked message mismatch", compression)
+		}
+
+		header, err := packer.UnpackHeader(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if header.Seq != 1 || header.Route != 1 || header.Buffer != nil {
+			t.Fatalf("%s: unpacked header mismatch", compression)
+		}
+
+		buf, err := packer.PackBuffer(&packet.Message{Seq: 1, Route: 1, Buffer: data})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(buf.Bytes(), msg) {
+			t.Fatalf("%s: packed buffer mismatch", compression)
+		}
+
+		buf.Release()
+
+		t.Logf("%s: %d -> %d", compression, len(data), len(msg))
+	}
+}
+
+func TestDefaultPacker_DecompressLimit(t *testing.T) {
+	packer := packet.NewPacker(
+		packet.WithCompression(packet.Zstd),
+		packet.WithCompressThreshold(64),
+		packet.WithBufferBytes(1024),
+	)
+
+	msg, err := packer.PackMessage(&packet.Message{Seq: 1, Route: 1, Buffer: bytes.Repeat([]byte("a"), 512)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 在首个帧之后追加超出限制的帧
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg = encoder.EncodeAll(bytes.Repeat([]byte("b"), 1<<20), msg)
+
+	binary.BigEndian.PutUint32(msg, uint32(len(msg)-4))
+
+	if _, err = packer.UnpackMessage(msg); !errors.Is(err, errors.ErrMessageTooLarge) {
+		t.Fatalf("oversized message should be rejected, err: %v", err)
+	}
+}
+
+func TestDefaultPacker_ReplayWindow(t *testing.T) {
+	block, err := aes.NewCipher(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		sender   = packet.NewCipher(aead, aead)
+		receiver = packet.NewCipher(aead, aead)
+		sealed   = make([][]byte, 3000)
+	)
+
+	data, err := packer.PackMessage(&packet.Message{Seq: 1, Route: 1, Buffer: []byte("hello world")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range sealed {
+		if sealed[i], err = packer.Seal(sender, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 窗口内乱序到达的数据包均可解密，重复的数据包被拒绝
+	for _, i := range []int{9, 3, 0, 5, 1, 2, 4, 8, 7, 6, 1999, 1000} {
+		if _, err = packer.Open(receiver, sealed[i]); err != nil {
+			t.Fatalf("packet %d: %v", i, err)
+		}
+
+		if _, err = packer.Open(receiver, sealed[i]); !errors.Is(err, errors.ErrReplayedMessage) {
+			t.Fatalf("packet %d: replayed message should be rejected, err: %v", i, err)
+		}
+	}
+
+	// 滑出窗口的数据包被拒绝
+	if _, err = packer.Open(receiver, sealed[10]); !errors.Is(err, errors.ErrReplayedMessage) {
+		t.Fatalf("stale message should be rejected, err: %v", err)
+	}
+
+	// 伪造的数据包不会推动窗口
+	forged := bytes.Clone(sealed[2999])
+	forged[len(forged)-1] ^= 0xff
+
+	if _, err = packer.Open(receiver, forged); errors.Is(err, errors.ErrReplayedMessage) || err == nil {
+		t.Fatalf("forged message should be rejected, err: %v", err)
+	}
+
+	if _, err = packer.Open(receiver, sealed[1500]); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dobyte/due/v2/registry"
+)
+
+const name = "memory"
+
+var _ registry.Registry = &Registry{}
+
+// Registry 基于进程内存的服务注册发现组件
+// 同一进程内的多个组件共享同一个实例即可组成集群，适用于单元测试及单进程开发环境
+type Registry struct {
+	rw       sync.RWMutex
+	services map[string]map[string]*registry.ServiceInstance // serviceName -> insID -> instance
+	watchers map[string]map[int64]*watcher                   // serviceName -> idx -> watcher
+	idx      int64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		services: make(map[string]map[string]*registry.ServiceInstance),
+		watchers: make(map[string]map[int64]*watcher),
+	}
+}
+
+// Name 获取服务注册发现组件名
+func (r *Registry) Name() string {
+	return name
+}
+
+// Register 注册服务实例；相同ID的服务实例重复注册时将覆盖原有实例
+func (r *Registry) Register(ctx context.Context, ins *registry.ServiceInstance) error {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	instances, ok := r.services[ins.Name]
+	if !ok {
+		instances = make(map[string]*registry.ServiceInstance)
+		r.services[ins.Name] = instances
+	}
+
+	instances[ins.ID] = clone(ins)
+
+	r.broadcast(ins.Name)
+
+	return nil
+}
+
+// Deregister 解注册服务实例
+func (r *Registry) Deregister(ctx context.Context, ins *registry.ServiceInstance) error {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	instances, ok := r.services[ins.Name]
+	if !ok {
+		return nil
+	}
+
+	if _, ok = instances[ins.ID]; !ok {
+		return nil
+	}
+
+	delete(instances, ins.ID)
+
+	if len(instances) == 0 {
+		delete(r.services, ins.Name)
+	}
+
+	r.broadcast(ins.Name)
+
+	return nil
+}
+
+// Services 获取服务实例列表
+func (r *Registry) Services(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	return r.snapshot(serviceName), nil
+}
+
+// Watch 监听相同服务名的服务实例变化；监听器的生命周期与ctx无关，需调用Stop停止监听
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.idx++
+
+	w := newWatcher(r, serviceName, r.idx, r.snapshot(serviceName))
+
+	watchers, ok := r.watchers[serviceName]
+	if !ok {
+		watchers = make(map[int64]*watcher)
+		r.watchers[serviceName] = watchers
+	}
+
+	watchers[w.idx] = w
+
+	return w, nil
+}
+
+// 回收监听器
+func (r *Registry) recycle(serviceName string, idx int64) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	watchers, ok := r.watchers[serviceName]
+	if !ok {
+		return
+	}
+
+	delete(watchers, idx)
+
+	if len(watchers) == 0 {
+		delete(r.watchers, serviceName)
+	}
+}
+
+// 广播服务实例变化；调用方需持有写锁
+func (r *Registry) broadcast(serviceName string) {
+	watchers, ok := r.watchers[serviceName]
+	if !ok {
+		return
+	}
+
+	services := r.snapshot(serviceName)
+
+	for _, w := range watchers {
+		w.notify(services)
+	}
+}
+
+// 获取服务实例列表快照；调用方需持有读锁
+func (r *Registry) snapshot(serviceName string) []*registry.ServiceInstance {
+	instances := r.services[serviceName]
+	services := make([]*registry.ServiceInstance, 0, len(instances))
+
+	for _, ins := range instances {
+		services = append(services, clone(ins))
+	}
+
+	slices.SortFunc(services, func(a, b *registry.ServiceInstance) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return services
+}
+
+// 深拷贝服务实例，避免调用方修改已注册的实例
+func clone(ins *registry.ServiceInstance) *registry.ServiceInstance {
+	c := *ins
+	c.Events = slices.Clone(ins.Events)
+	c.Routes = slices.Clone(ins.Routes)
+	c.Services = slices.Clone(ins.Services)
+	c.Metadata = maps.Clone(ins.Metadata)
+
+	return &c
+}
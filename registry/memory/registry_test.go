@@ -0,0 +1,131 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/cluster"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/registry"
+	"github.com/dobyte/due/v2/registry/memory"
+)
+
+const serviceName = "node"
+
+func next(t *testing.T, w registry.Watcher) []*registry.ServiceInstance {
+	t.Helper()
+
+	ch := make(chan []*registry.ServiceInstance, 1)
+
+	go func() {
+		services, err := w.Next()
+		if err != nil {
+			t.Error(err)
+		}
+		ch <- services
+	}()
+
+	select {
+	case services := <-ch:
+		return services
+	case <-time.After(time.Second):
+		t.Fatal("watcher next timeout")
+		return nil
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	ctx := context.Background()
+	reg := memory.NewRegistry()
+
+	ins := &registry.ServiceInstance{
+		ID:       "test-1",
+		Name:     serviceName,
+		Kind:     cluster.Node.String(),
+		State:    cluster.Work.String(),
+		Routes:   []registry.Route{{ID: 1, Stateful: true}},
+		Metadata: map[string]string{"key": "value"},
+	}
+
+	if err := reg.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	ins.State = cluster.Busy.String()
+	ins.Metadata["key"] = "changed"
+
+	services, err := reg.Services(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(services) != 1 || services[0].State != cluster.Work.String() || services[0].Metadata["key"] != "value" {
+		t.Fatal("registered instance should not be affected by caller modification")
+	}
+
+	if err = reg.Register(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	if services, _ = reg.Services(ctx, serviceName); len(services) != 1 || services[0].State != cluster.Busy.String() {
+		t.Fatal("re-register should replace the instance")
+	}
+
+	if err = reg.Deregister(ctx, ins); err != nil {
+		t.Fatal(err)
+	}
+
+	if services, _ = reg.Services(ctx, serviceName); len(services) != 0 {
+		t.Fatal("deregister failed")
+	}
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	ctx := context.Background()
+	reg := memory.NewRegistry()
+
+	ins1 := &registry.ServiceInstance{ID: "test-1", Name: serviceName}
+	ins2 := &registry.ServiceInstance{ID: "test-2", Name: serviceName}
+
+	if err := reg.Register(ctx, ins1); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := reg.Watch(ctx, serviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if services := next(t, w); len(services) != 1 {
+		t.Fatalf("expected 1 instance, got %d", len(services))
+	}
+
+	if err = reg.Register(ctx, ins2); err != nil {
+		t.Fatal(err)
+	}
+
+	if services := next(t, w); len(services) != 2 {
+		t.Fatalf("expected 2 instances, got %d", len(services))
+	}
+
+	// 连续的变化会被合并，仅返回最新的服务实例列表
+	_ = reg.Deregister(ctx, ins1)
+	_ = reg.Deregister(ctx, ins2)
+
+	if services := next(t, w); len(services) != 0 {
+		t.Fatalf("expected 0 instances, got %d", len(services))
+	}
+
+	if err = w.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = w.Stop(); !errors.Is(err, errors.ErrIllegalOperation) {
+		t.Fatal("stop twice should fail")
+	}
+
+	if _, err = w.Next(); err == nil {
+		t.Fatal("next after stop should fail")
+	}
+}
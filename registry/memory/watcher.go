@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/registry"
+)
+
+type state int32
+
+const (
+	stateInitial state = 0
+	stateRunning state = 1
+	stateStopped state = 2
+)
+
+type watcher struct {
+	idx         int64
+	ctx         context.Context
+	cancel      context.CancelFunc
+	registry    *Registry
+	serviceName string
+	rw          sync.Mutex
+	state       state
+	services    []*registry.ServiceInstance // 尚未被消费的最新服务实例列表
+	pending     bool                        // 是否存在尚未被消费的变化
+	chNotify    chan struct{}
+}
+
+func newWatcher(r *Registry, serviceName string, idx int64, services []*registry.ServiceInstance) *watcher {
+	w := &watcher{}
+	w.idx = idx
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.registry = r
+	w.serviceName = serviceName
+	w.services = services
+	w.chNotify = make(chan struct{}, 1)
+
+	return w
+}
+
+// 通知服务实例变化；仅保留最新的服务实例列表，不会阻塞注册流程
+func (w *watcher) notify(services []*registry.ServiceInstance) {
+	w.rw.Lock()
+	defer w.rw.Unlock()
+
+	if w.state == stateStopped {
+		return
+	}
+
+	w.services = services
+	w.pending = true
+
+	select {
+	case w.chNotify <- struct{}{}:
+	default:
+	}
+}
+
+// Next 返回服务实例列表；首次调用立即返回当前服务实例列表，后续调用阻塞至服务实例发生变化
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	w.rw.Lock()
+	if w.state == stateInitial {
+		w.state = stateRunning
+		w.pending = false
+		services := w.services
+		w.rw.Unlock()
+		return services, nil
+	}
+	w.rw.Unlock()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-w.chNotify:
+			w.rw.Lock()
+			if !w.pending {
+				w.rw.Unlock()
+				continue
+			}
+
+			w.pending = false
+			services := w.services
+			w.rw.Unlock()
+
+			return services, nil
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.rw.Lock()
+	if w.state == stateStopped {
+		w.rw.Unlock()
+		return errors.ErrIllegalOperation
+	}
+
+	w.state = stateStopped
+	w.services = nil
+	w.rw.Unlock()
+
+	w.cancel()
+	w.registry.recycle(w.serviceName, w.idx)
+
+	return nil
+}
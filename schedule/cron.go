@@ -0,0 +1,285 @@
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+)
+
+const starBit = 1 << 63
+
+type bounds struct {
+	min uint
+	max uint
+}
+
+var (
+	seconds = bounds{0, 59}
+	minutes = bounds{0, 59}
+	hours   = bounds{0, 23}
+	doms    = bounds{1, 31}
+	months  = bounds{1, 12}
+	dows    = bounds{0, 7}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+type cron struct {
+	second uint64
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+}
+
+// 解析cron表达式
+// 支持5段式（分 时 日 月 周）与6段式（秒 分 时 日 月 周）表达式，以及@yearly、@monthly、@weekly、@daily、@hourly等描述符
+func parseCron(spec string) (*cron, error) {
+	spec = strings.TrimSpace(spec)
+
+	if v, ok := descriptors[spec]; ok {
+		spec = v
+	}
+
+	fields := strings.Fields(spec)
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, errors.NewError(errors.ErrInvalidArgument, "invalid cron spec: "+spec)
+	}
+
+	var (
+		err error
+		c   = &cron{}
+	)
+
+	if c.second, err = parseField(fields[0], seconds); err != nil {
+		return nil, err
+	}
+
+	if c.minute, err = parseField(fields[1], minutes); err != nil {
+		return nil, err
+	}
+
+	if c.hour, err = parseField(fields[2], hours); err != nil {
+		return nil, err
+	}
+
+	if c.dom, err = parseField(fields[3], doms); err != nil {
+		return nil, err
+	}
+
+	if c.month, err = parseField(fields[4], months); err != nil {
+		return nil, err
+	}
+
+	if c.dow, err = parseField(fields[5], dows); err != nil {
+		return nil, err
+	}
+
+	// 周日可以用0或7表示
+	if c.dow&(1<<7) > 0 {
+		c.dow |= 1
+	}
+
+	return c, nil
+}
+
+// 解析字段
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+
+	for _, expr := range strings.Split(field, ",") {
+		bit, err := parseRange(expr, b)
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= bit
+	}
+
+	return bits, nil
+}
+
+// 解析范围
+func parseRange(expr string, b bounds) (uint64, error) {
+	var (
+		err          error
+		start, end   uint
+		step         uint = 1
+		extra        uint64
+		rangeAndStep = strings.Split(expr, "/")
+		lowAndHigh   = strings.Split(rangeAndStep[0], "-")
+		singleDigit  = len(lowAndHigh) == 1
+	)
+
+	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+		start, end, extra = b.min, b.max, starBit
+	} else {
+		if start, err = parseUint(lowAndHigh[0]); err != nil {
+			return 0, err
+		}
+
+		switch len(lowAndHigh) {
+		case 1:
+			end = start
+		case 2:
+			if end, err = parseUint(lowAndHigh[1]); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, errors.NewError(errors.ErrInvalidArgument, "invalid cron range: "+expr)
+		}
+	}
+
+	switch len(rangeAndStep) {
+	case 1:
+	case 2:
+		if step, err = parseUint(rangeAndStep[1]); err != nil {
+			return 0, err
+		}
+
+		if singleDigit {
+			end = b.max
+		}
+
+		if step > 1 {
+			extra = 0
+		}
+	default:
+		return 0, errors.NewError(errors.ErrInvalidArgument, "invalid cron step: "+expr)
+	}
+
+	if start < b.min || end > b.max || start > end || step == 0 {
+		return 0, errors.NewError(errors.ErrInvalidArgument, "cron value out of range: "+expr)
+	}
+
+	var bits uint64
+
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+
+	return bits | extra, nil
+}
+
+// 解析无符号整数
+func parseUint(s string) (uint, error) {
+	v, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, errors.NewError(errors.ErrInvalidArgument, "invalid cron value: "+s)
+	}
+
+	return uint(v), nil
+}
+
+// 计算给定时间之后的下一次执行时间，无法匹配时返回零值
+func (c *cron) next(t time.Time) time.Time {
+	var (
+		loc       = t.Location()
+		added     = false
+		yearLimit = t.Year() + 5
+	)
+
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for 1<<uint(t.Month())&c.month == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+
+		t = t.AddDate(0, 1, 0)
+
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !c.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+
+		t = t.AddDate(0, 0, 1)
+
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Hour())&c.hour == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+
+		t = t.Add(time.Hour)
+
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Minute())&c.minute == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+
+		t = t.Add(time.Minute)
+
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Second())&c.second == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+
+		t = t.Add(time.Second)
+
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+// 检测日期是否匹配
+func (c *cron) dayMatches(t time.Time) bool {
+	var (
+		domMatch = 1<<uint(t.Day())&c.dom > 0
+		dowMatch = 1<<uint(t.Weekday())&c.dow > 0
+	)
+
+	if c.dom&starBit > 0 || c.dow&starBit > 0 {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
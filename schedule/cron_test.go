@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	specs := []string{
+		"* * * * *",
+		"*/5 * * * * *",
+		"0 30 9 * * 1-5",
+		"0 0 1,15 * *",
+		"@daily",
+		"@hourly",
+	}
+
+	for _, spec := range specs {
+		if _, err := parseCron(spec); err != nil {
+			t.Fatalf("parse %q failed: %v", spec, err)
+		}
+	}
+
+	invalids := []string{
+		"",
+		"* * *",
+		"60 * * * * *",
+		"* * 24 * * *",
+		"* * * 0 * *",
+		"*/0 * * * * *",
+		"a * * * *",
+	}
+
+	for _, spec := range invalids {
+		if _, err := parseCron(spec); err == nil {
+			t.Fatalf("parse %q should be failed", spec)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	now := time.Date(2024, 1, 31, 23, 59, 58, 500, time.UTC)
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * * *", time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)},
+		{"*/5 * * * * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 30 9 * * 1-5", time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		cron, err := parseCron(c.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if next := cron.next(now); !next.Equal(c.want) {
+			t.Fatalf("spec %q: want %v, got %v", c.spec, c.want, next)
+		}
+	}
+}
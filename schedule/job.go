@@ -0,0 +1,33 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+type Kind int
+
+const (
+	Cron  Kind = iota + 1 // cron表达式任务
+	Delay                 // 一次性延迟任务
+	Rate                  // 固定频率任务
+)
+
+type Handler func(ctx context.Context, job *Job) error
+
+type Job struct {
+	ID        string        `json:"id"`                 // 任务ID；cron任务与固定频率任务的ID即为任务名称
+	Kind      Kind          `json:"kind"`               // 任务类型
+	Topic     string        `json:"topic"`              // 任务主题，用于匹配任务处理器
+	Spec      string        `json:"spec,omitempty"`     // cron表达式；仅cron任务有效
+	Interval  time.Duration `json:"interval,omitempty"` // 执行间隔；仅固定频率任务有效
+	Payload   []byte        `json:"payload,omitempty"`  // 任务参数
+	RunAt     time.Time     `json:"runAt"`              // 下次执行时间
+	LastRunAt time.Time     `json:"lastRunAt"`          // 上次执行时间
+}
+
+// 克隆任务
+func (j *Job) clone() *Job {
+	job := *j
+	return &job
+}
@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/dobyte/due/v2/etc"
+	"github.com/dobyte/due/v2/lock"
+	"github.com/dobyte/due/v2/utils/xconv"
+)
+
+const (
+	defaultName           = "schedule" // 默认名称
+	defaultPrecision      = "1s"       // 默认调度精度
+	defaultSyncInterval   = "5s"       // 默认任务同步间隔
+	defaultLockExpiration = "1m"       // 默认任务锁过期时间
+)
+
+const (
+	defaultNameKey           = "etc.schedule.name"
+	defaultPrecisionKey      = "etc.schedule.precision"
+	defaultSyncIntervalKey   = "etc.schedule.syncInterval"
+	defaultLockExpirationKey = "etc.schedule.lockExpiration"
+)
+
+type Option func(o *options)
+
+type options struct {
+	ctx            context.Context // 上下文
+	name           string          // 组件名称
+	store          Store           // 任务存储器
+	maker          lock.Maker      // 分布式锁制造商
+	precision      time.Duration   // 调度精度
+	syncInterval   time.Duration   // 任务同步间隔
+	lockExpiration time.Duration   // 任务锁过期时间
+}
+
+func defaultOptions() *options {
+	opts := &options{
+		ctx:            context.Background(),
+		name:           defaultName,
+		precision:      xconv.Duration(defaultPrecision),
+		syncInterval:   xconv.Duration(defaultSyncInterval),
+		lockExpiration: xconv.Duration(defaultLockExpiration),
+	}
+
+	if name := etc.Get(defaultNameKey).String(); name != "" {
+		opts.name = name
+	}
+
+	if precision := etc.Get(defaultPrecisionKey).Duration(); precision > 0 {
+		opts.precision = precision
+	}
+
+	if syncInterval := etc.Get(defaultSyncIntervalKey).Duration(); syncInterval > 0 {
+		opts.syncInterval = syncInterval
+	}
+
+	if lockExpiration := etc.Get(defaultLockExpirationKey).Duration(); lockExpiration > 0 {
+		opts.lockExpiration = lockExpiration
+	}
+
+	return opts
+}
+
+// WithContext 设置上下文
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithName 设置组件名称
+func WithName(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// WithStore 设置任务存储器
+func WithStore(store Store) Option {
+	return func(o *options) { o.store = store }
+}
+
+// WithMaker 设置分布式锁制造商，未设置时使用全局的分布式锁制造商
+func WithMaker(maker lock.Maker) Option {
+	return func(o *options) { o.maker = maker }
+}
+
+// WithPrecision 设置调度精度
+func WithPrecision(precision time.Duration) Option {
+	return func(o *options) { o.precision = precision }
+}
+
+// WithSyncInterval 设置任务同步间隔
+func WithSyncInterval(syncInterval time.Duration) Option {
+	return func(o *options) { o.syncInterval = syncInterval }
+}
+
+// WithLockExpiration 设置任务锁过期时间，需大于各实例间的时钟偏差
+func WithLockExpiration(lockExpiration time.Duration) Option {
+	return func(o *options) { o.lockExpiration = lockExpiration }
+}
@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/dobyte/due/v2/core/tls"
+	"github.com/dobyte/due/v2/encoding/json"
+	"github.com/dobyte/due/v2/schedule"
+	"github.com/redis/go-redis/v9"
+)
+
+type Store struct {
+	err     error
+	opts    *options
+	builtin bool
+	key     string
+}
+
+var _ schedule.Store = &Store{}
+
+func NewStore(opts ...Option) *Store {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Store{}
+
+	defer func() {
+		if s.err == nil {
+			s.opts = o
+
+			if o.prefix == "" {
+				s.key = "jobs"
+			} else {
+				s.key = o.prefix + ":jobs"
+			}
+		}
+	}()
+
+	if o.client == nil {
+		options := &redis.UniversalOptions{
+			Addrs:      o.addrs,
+			DB:         o.db,
+			Username:   o.username,
+			Password:   o.password,
+			MaxRetries: o.maxRetries,
+		}
+
+		if o.certFile != "" && o.keyFile != "" && o.caFile != "" {
+			if options.TLSConfig, s.err = tls.MakeRedisTLSConfig(o.certFile, o.keyFile, o.caFile); s.err != nil {
+				return s
+			}
+		}
+
+		o.client, s.builtin = redis.NewUniversalClient(options), true
+	}
+
+	return s
+}
+
+// Save 保存任务
+func (s *Store) Save(ctx context.Context, job *schedule.Job) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	buf, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return s.opts.client.HSet(ctx, s.key, job.ID, buf).Err()
+}
+
+// Delete 删除任务
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.opts.client.HDel(ctx, s.key, id).Err()
+}
+
+// Load 加载所有任务
+func (s *Store) Load(ctx context.Context) ([]*schedule.Job, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	values, err := s.opts.client.HGetAll(ctx, s.key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*schedule.Job, 0, len(values))
+
+	for _, value := range values {
+		job := &schedule.Job{}
+
+		if err = json.Unmarshal([]byte(value), job); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// Close 关闭存储器
+func (s *Store) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.builtin {
+		return s.opts.client.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/schedule/redis/v2"
+	"github.com/dobyte/due/v2/schedule"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	client := goredis.NewUniversalClient(&goredis.UniversalOptions{Addrs: []string{"127.0.0.1:6379"}, MaxRetries: -1})
+	defer client.Close()
+
+	pctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	if err := client.Ping(pctx).Err(); err != nil {
+		t.Skipf("redis is not reachable: %v", err)
+	}
+
+	store := redis.NewStore(redis.WithClient(client))
+	defer store.Close()
+
+	job := &schedule.Job{
+		ID:      "job-1",
+		Kind:    schedule.Delay,
+		Topic:   "greet",
+		Payload: []byte("hello world"),
+		RunAt:   time.Now().Add(time.Minute),
+	}
+
+	if err := store.Save(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range jobs {
+		t.Logf("id: %s topic: %s runAt: %v", job.ID, job.Topic, job.RunAt)
+	}
+
+	if err = store.Delete(ctx, job.ID); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,342 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/v2/component"
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/lock"
+	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/utils/xcall"
+	"github.com/dobyte/due/v2/utils/xuuid"
+)
+
+type Scheduler struct {
+	component.Base
+	opts     *options
+	ctx      context.Context
+	cancel   context.CancelFunc
+	rw       sync.RWMutex
+	jobs     map[string]*entry  // 本地待调度的任务
+	handlers map[string]Handler // 任务处理器
+	wg       sync.WaitGroup
+}
+
+type entry struct {
+	job     *Job
+	cron    *cron
+	handler Handler
+}
+
+func NewScheduler(opts ...Option) *Scheduler {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.store == nil {
+		o.store = NewMemoryStore()
+	}
+
+	s := &Scheduler{}
+	s.opts = o
+	s.ctx, s.cancel = context.WithCancel(o.ctx)
+	s.jobs = make(map[string]*entry)
+	s.handlers = make(map[string]Handler)
+
+	return s
+}
+
+// Name 组件名称
+func (s *Scheduler) Name() string {
+	return s.opts.name
+}
+
+// Init 初始化调度器
+func (s *Scheduler) Init() {
+	if s.opts.maker == nil {
+		s.opts.maker = lock.GetMaker()
+	}
+
+	if s.opts.maker == nil {
+		log.Warn("lock-maker is not injected, the jobs will be executed on every instance")
+	}
+}
+
+// Start 启动调度器
+func (s *Scheduler) Start() {
+	s.sync()
+
+	go s.run()
+}
+
+// Destroy 销毁调度器
+func (s *Scheduler) Destroy() {
+	s.cancel()
+
+	s.wg.Wait()
+}
+
+// AddCronJob 添加cron任务
+// 所有实例需使用相同的任务名称添加同一任务，同一时刻的任务仅会在其中一个实例上执行
+func (s *Scheduler) AddCronJob(name string, spec string, handler Handler) error {
+	c, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+
+	runAt := c.next(time.Now())
+	if runAt.IsZero() {
+		return errors.NewError(errors.ErrInvalidArgument, "cron spec never matches: "+spec)
+	}
+
+	return s.addJob(&Job{ID: name, Kind: Cron, Topic: name, Spec: spec, RunAt: runAt}, c, handler)
+}
+
+// AddRateJob 添加固定频率任务
+// 执行时间按间隔对齐，以保证不同实例计算出的执行时间一致
+func (s *Scheduler) AddRateJob(name string, interval time.Duration, handler Handler) error {
+	if interval <= 0 {
+		return errors.NewError(errors.ErrInvalidArgument, "invalid job interval")
+	}
+
+	return s.addJob(&Job{ID: name, Kind: Rate, Topic: name, Interval: interval, RunAt: nextRateTime(time.Now(), interval)}, nil, handler)
+}
+
+// Handle 注册延迟任务处理器
+// 仅注册了对应主题处理器的实例才会参与该主题下延迟任务的调度
+func (s *Scheduler) Handle(topic string, handler Handler) {
+	s.rw.Lock()
+	s.handlers[topic] = handler
+	s.rw.Unlock()
+}
+
+// AddDelayJob 添加一次性延迟任务，返回任务ID
+// 延迟任务会被保存至存储器中，由注册了对应主题处理器的任一实例执行
+func (s *Scheduler) AddDelayJob(ctx context.Context, topic string, delay time.Duration, payload []byte) (string, error) {
+	if topic == "" {
+		return "", errors.NewError(errors.ErrInvalidArgument, "invalid job topic")
+	}
+
+	job := &Job{
+		ID:      xuuid.UUID(),
+		Kind:    Delay,
+		Topic:   topic,
+		Payload: payload,
+		RunAt:   time.Now().Add(delay),
+	}
+
+	if err := s.opts.store.Save(ctx, job); err != nil {
+		return "", err
+	}
+
+	s.rw.Lock()
+	if handler, ok := s.handlers[topic]; ok {
+		s.jobs[job.ID] = &entry{job: job, handler: handler}
+	}
+	s.rw.Unlock()
+
+	return job.ID, nil
+}
+
+// RemoveJob 移除任务
+func (s *Scheduler) RemoveJob(ctx context.Context, id string) error {
+	s.rw.Lock()
+	delete(s.jobs, id)
+	s.rw.Unlock()
+
+	return s.opts.store.Delete(ctx, id)
+}
+
+// 添加周期任务
+func (s *Scheduler) addJob(job *Job, c *cron, handler Handler) error {
+	if job.ID == "" {
+		return errors.NewError(errors.ErrInvalidArgument, "invalid job name")
+	}
+
+	if handler == nil {
+		return errors.NewError(errors.ErrInvalidArgument, "invalid job handler")
+	}
+
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	if _, ok := s.jobs[job.ID]; ok {
+		return errors.NewError(errors.ErrInvalidArgument, "job already exists: "+job.ID)
+	}
+
+	s.jobs[job.ID] = &entry{job: job, cron: c, handler: handler}
+
+	return nil
+}
+
+// 执行调度
+func (s *Scheduler) run() {
+	ticker := time.NewTicker(s.opts.precision)
+	defer ticker.Stop()
+
+	syncTicker := time.NewTicker(s.opts.syncInterval)
+	defer syncTicker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(now)
+		case <-syncTicker.C:
+			s.sync()
+		}
+	}
+}
+
+// 触发到期的任务
+func (s *Scheduler) tick(now time.Time) {
+	s.rw.Lock()
+
+	for id, e := range s.jobs {
+		if e.job.RunAt.After(now) {
+			continue
+		}
+
+		job := e.job.clone()
+
+		e.job.LastRunAt = job.RunAt
+
+		switch e.job.Kind {
+		case Cron:
+			if next := e.cron.next(now); next.IsZero() {
+				delete(s.jobs, id)
+			} else {
+				e.job.RunAt = next
+			}
+		case Rate:
+			e.job.RunAt = nextRateTime(now, e.job.Interval)
+		default:
+			delete(s.jobs, id)
+		}
+
+		s.wg.Add(1)
+
+		go s.execute(job, e.job.RunAt, e.handler)
+	}
+
+	s.rw.Unlock()
+}
+
+// 执行任务
+func (s *Scheduler) execute(job *Job, next time.Time, handler Handler) {
+	defer s.wg.Done()
+
+	if s.opts.maker != nil {
+		key := fmt.Sprintf("schedule:%s:%d", job.ID, job.RunAt.UnixMilli())
+
+		if err := s.opts.maker.Make(key).TryAcquire(s.ctx, s.opts.lockExpiration); err != nil {
+			if !errors.Is(err, errors.ErrIllegalOperation) {
+				log.Warnf("acquire job lock failed, id: %s err: %v", job.ID, err)
+			}
+			return
+		}
+	}
+
+	if !s.claim(job, next) {
+		return
+	}
+
+	xcall.Call(func() {
+		if err := handler(s.ctx, job); err != nil {
+			log.Errorf("execute job failed, id: %s topic: %s err: %v", job.ID, job.Topic, err)
+		}
+	})
+}
+
+// 认领任务，执行前先移除存储器中的延迟任务或推进周期任务的执行进度
+// 避免任务执行时间超过任务锁过期时间后，其他实例再次执行同一任务；延迟任务执行期间实例宕机将不再重试
+func (s *Scheduler) claim(job *Job, next time.Time) bool {
+	ctx, cancel := context.WithTimeout(s.ctx, s.opts.lockExpiration)
+	defer cancel()
+
+	switch job.Kind {
+	case Delay:
+		// 移除失败时不执行任务，由下次同步重新加载后重试
+		if err := s.opts.store.Delete(ctx, job.ID); err != nil {
+			log.Errorf("delete job failed, id: %s err: %v", job.ID, err)
+			return false
+		}
+	default:
+		progress := job.clone()
+		progress.LastRunAt, progress.RunAt = job.RunAt, next
+
+		if err := s.opts.store.Save(ctx, progress); err != nil {
+			log.Errorf("save job failed, id: %s err: %v", job.ID, err)
+		}
+	}
+
+	return true
+}
+
+// 同步存储器中的任务，加载延迟任务并恢复周期任务的执行进度
+func (s *Scheduler) sync() {
+	ctx, cancel := context.WithTimeout(s.ctx, s.opts.syncInterval)
+	defer cancel()
+
+	jobs, err := s.opts.store.Load(ctx)
+	if err != nil {
+		log.Errorf("load jobs failed: %v", err)
+		return
+	}
+
+	ids := make(map[string]struct{}, len(jobs))
+
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	for _, job := range jobs {
+		if job.Kind != Delay {
+			s.restore(job)
+			continue
+		}
+
+		ids[job.ID] = struct{}{}
+
+		if _, ok := s.jobs[job.ID]; ok {
+			continue
+		}
+
+		if handler, ok := s.handlers[job.Topic]; ok {
+			s.jobs[job.ID] = &entry{job: job, handler: handler}
+		}
+	}
+
+	// 移除已被其他实例执行或移除的延迟任务
+	for id, e := range s.jobs {
+		if _, ok := ids[id]; !ok && e.job.Kind == Delay {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// 恢复周期任务的执行进度，任务定义发生变化时不予恢复（需在加锁后调用）
+// 其他实例已执行过的时间点将被跳过，避免任务锁过期后重复执行
+func (s *Scheduler) restore(job *Job) {
+	e, ok := s.jobs[job.ID]
+	if !ok || e.job.Kind != job.Kind || e.job.Spec != job.Spec || e.job.Interval != job.Interval {
+		return
+	}
+
+	if job.LastRunAt.After(e.job.LastRunAt) {
+		e.job.LastRunAt = job.LastRunAt
+	}
+
+	if job.RunAt.After(e.job.RunAt) {
+		e.job.RunAt = job.RunAt
+	}
+}
+
+// 计算固定频率任务的下次执行时间
+func nextRateTime(now time.Time, interval time.Duration) time.Time {
+	return now.Truncate(interval).Add(interval)
+}
@@ -0,0 +1,203 @@
+package schedule_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+	"github.com/dobyte/due/v2/lock"
+	"github.com/dobyte/due/v2/schedule"
+)
+
+// 内存分布式锁制造商
+type maker struct {
+	rw    sync.Mutex
+	locks map[string]time.Time
+}
+
+func newMaker() *maker {
+	return &maker{locks: make(map[string]time.Time)}
+}
+
+func (m *maker) Make(name string) lock.Locker {
+	return &locker{maker: m, name: name}
+}
+
+func (m *maker) Close() error {
+	return nil
+}
+
+type locker struct {
+	maker *maker
+	name  string
+}
+
+func (l *locker) Acquire(ctx context.Context) error {
+	return l.TryAcquire(ctx)
+}
+
+func (l *locker) TryAcquire(_ context.Context, expiration ...time.Duration) error {
+	l.maker.rw.Lock()
+	defer l.maker.rw.Unlock()
+
+	if expiredAt, ok := l.maker.locks[l.name]; ok && time.Now().Before(expiredAt) {
+		return errors.ErrIllegalOperation
+	}
+
+	l.maker.locks[l.name] = time.Now().Add(expiration[0])
+
+	return nil
+}
+
+func (l *locker) Release(_ context.Context) error {
+	l.maker.rw.Lock()
+	defer l.maker.rw.Unlock()
+
+	delete(l.maker.locks, l.name)
+
+	return nil
+}
+
+func TestScheduler_AddDelayJob(t *testing.T) {
+	var (
+		count atomic.Int32
+		store = schedule.NewMemoryStore()
+		s     = schedule.NewScheduler(schedule.WithStore(store), schedule.WithPrecision(10*time.Millisecond))
+	)
+
+	s.Handle("greet", func(ctx context.Context, job *schedule.Job) error {
+		count.Add(1)
+		t.Logf("id: %s payload: %s", job.ID, string(job.Payload))
+		return nil
+	})
+
+	s.Init()
+	s.Start()
+	defer s.Destroy()
+
+	if _, err := s.AddDelayJob(context.Background(), "greet", 50*time.Millisecond, []byte("hello world")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if count.Load() != 1 {
+		t.Fatalf("want 1 execution, got %d", count.Load())
+	}
+
+	jobs, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jobs) != 0 {
+		t.Fatalf("want 0 jobs in store, got %d", len(jobs))
+	}
+}
+
+func TestScheduler_AddRateJob(t *testing.T) {
+	var (
+		count atomic.Int32
+		s     = schedule.NewScheduler(schedule.WithPrecision(10 * time.Millisecond))
+	)
+
+	err := s.AddRateJob("tick", 50*time.Millisecond, func(ctx context.Context, job *schedule.Job) error {
+		count.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Init()
+	s.Start()
+	defer s.Destroy()
+
+	time.Sleep(300 * time.Millisecond)
+
+	if count.Load() < 3 {
+		t.Fatalf("want at least 3 executions, got %d", count.Load())
+	}
+}
+
+func TestScheduler_Restore(t *testing.T) {
+	var (
+		store    = schedule.NewMemoryStore()
+		interval = 50 * time.Millisecond
+		runAt    = time.Now().Truncate(interval).Add(4 * interval)
+		lastRun  = runAt.Add(-interval)
+		s        = schedule.NewScheduler(schedule.WithStore(store), schedule.WithPrecision(10*time.Millisecond))
+		jobs     = make(chan schedule.Job, 16)
+	)
+
+	// 其他实例已执行至lastRun
+	if err := store.Save(context.Background(), &schedule.Job{ID: "tick", Kind: schedule.Rate, Topic: "tick", Interval: interval, RunAt: runAt, LastRunAt: lastRun}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.AddRateJob("tick", interval, func(ctx context.Context, job *schedule.Job) error {
+		jobs <- *job
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Init()
+	s.Start()
+	defer s.Destroy()
+
+	select {
+	case job := <-jobs:
+		if !job.RunAt.Equal(runAt) || !job.LastRunAt.Equal(lastRun) {
+			t.Fatalf("job runAt = %v lastRunAt = %v, want %v %v", job.RunAt, job.LastRunAt, runAt, lastRun)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wait job execution timeout")
+	}
+}
+
+func TestScheduler_LongRunningDelayJob(t *testing.T) {
+	var (
+		count      atomic.Int32
+		store      = schedule.NewMemoryStore()
+		maker      = newMaker()
+		schedulers = make([]*schedule.Scheduler, 2)
+	)
+
+	for i := range schedulers {
+		s := schedule.NewScheduler(
+			schedule.WithStore(store),
+			schedule.WithMaker(maker),
+			schedule.WithPrecision(10*time.Millisecond),
+			schedule.WithSyncInterval(10*time.Millisecond),
+			schedule.WithLockExpiration(20*time.Millisecond),
+		)
+
+		// 任务执行时间超过任务锁过期时间
+		s.Handle("greet", func(ctx context.Context, job *schedule.Job) error {
+			count.Add(1)
+			time.Sleep(200 * time.Millisecond)
+			return nil
+		})
+
+		s.Init()
+		s.Start()
+		defer s.Destroy()
+
+		schedulers[i] = s
+	}
+
+	if _, err := schedulers[0].AddDelayJob(context.Background(), "greet", 20*time.Millisecond, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	if n := count.Load(); n != 1 {
+		t.Fatalf("want 1 execution, got %d", n)
+	}
+}
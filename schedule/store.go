@@ -0,0 +1,50 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+)
+
+type Store interface {
+	// Save 保存任务
+	Save(ctx context.Context, job *Job) error
+	// Delete 删除任务
+	Delete(ctx context.Context, id string) error
+	// Load 加载所有任务
+	Load(ctx context.Context) ([]*Job, error)
+}
+
+type memoryStore struct {
+	jobs sync.Map
+}
+
+var _ Store = &memoryStore{}
+
+// NewMemoryStore 创建基于内存的任务存储器，仅适用于单节点场景
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+// Save 保存任务
+func (s *memoryStore) Save(_ context.Context, job *Job) error {
+	s.jobs.Store(job.ID, job.clone())
+	return nil
+}
+
+// Delete 删除任务
+func (s *memoryStore) Delete(_ context.Context, id string) error {
+	s.jobs.Delete(id)
+	return nil
+}
+
+// Load 加载所有任务
+func (s *memoryStore) Load(_ context.Context) ([]*Job, error) {
+	jobs := make([]*Job, 0)
+
+	s.jobs.Range(func(_, job any) bool {
+		jobs = append(jobs, job.(*Job).clone())
+		return true
+	})
+
+	return jobs, nil
+}
@@ -11,6 +11,8 @@ readonly modules=(
     "./config/nacos"
     "./crypto/rsa"
     "./crypto/ecc"
+    "./crypto/aes"
+    "./crypto/chacha20"
     "./eventbus/kafka"
     "./eventbus/nats"
     "./eventbus/redis"
@@ -19,12 +21,16 @@ readonly modules=(
     "./lock/memcache"
     "./log/aliyun"
     "./log/tencent"
+    "./metrics/prometheus"
+    "./network/http"
     "./network/kcp"
+    "./network/quic"
     "./network/tcp"
     "./network/ws"
     "./registry/consul"
     "./registry/etcd"
     "./registry/nacos"
+    "./schedule/redis"
     "./transport/rpcx"
     "./transport/grpc"
 )
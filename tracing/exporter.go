@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type Exporter interface {
+	// Export 导出跨度
+	Export(service string, span *Span) error
+	// Close 关闭导出器
+	Close() error
+}
+
+type record struct {
+	Service      string         `json:"service"`
+	TraceID      string         `json:"traceId"`
+	SpanID       string         `json:"spanId"`
+	ParentSpanID string         `json:"parentSpanId,omitempty"`
+	Name         string         `json:"name"`
+	Kind         string         `json:"kind"`
+	StartTime    int64          `json:"startTimeUnixNano"`
+	EndTime      int64          `json:"endTimeUnixNano"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+type WriterExporter struct {
+	mu     sync.Mutex
+	writer *bufio.Writer
+	closer io.Closer
+}
+
+// NewWriterExporter 创建写入器导出器，每个跨度以一行JSON的格式写入
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	e := &WriterExporter{writer: bufio.NewWriter(w)}
+
+	if c, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+		e.closer = c
+	}
+
+	return e
+}
+
+// NewStdoutExporter 创建标准输出导出器
+func NewStdoutExporter() *WriterExporter {
+	return NewWriterExporter(os.Stdout)
+}
+
+// NewFileExporter 创建文件导出器
+func NewFileExporter(file string) (*WriterExporter, error) {
+	if file == "" {
+		file = "./log/trace.log"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriterExporter(f), nil
+}
+
+// Export 导出跨度
+func (e *WriterExporter) Export(service string, span *Span) error {
+	r := &record{
+		Service:   service,
+		TraceID:   span.sc.TraceID.String(),
+		SpanID:    span.sc.SpanID.String(),
+		Name:      span.name,
+		Kind:      span.kind.String(),
+		StartTime: span.start.UnixNano(),
+		EndTime:   span.end.UnixNano(),
+	}
+
+	if span.parent.IsValid() {
+		r.ParentSpanID = span.parent.String()
+	}
+
+	if attrs := span.Attributes(); len(attrs) > 0 {
+		r.Attributes = make(map[string]any, len(attrs))
+
+		for _, attr := range attrs {
+			r.Attributes[attr.Key] = attr.Value
+		}
+	}
+
+	if err := span.Err(); err != nil {
+		r.Error = err.Error()
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err = e.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return e.writer.Flush()
+}
+
+// Close 关闭导出器
+func (e *WriterExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.writer.Flush(); err != nil {
+		return err
+	}
+
+	if e.closer != nil {
+		return e.closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"github.com/dobyte/due/v2/etc"
+	"github.com/dobyte/due/v2/log"
+)
+
+const (
+	defaultName       = "due" // 默认服务名称
+	defaultSampler    = 1.0   // 默认采样率
+	defaultBufferSize = 4096  // 默认导出缓冲区大小
+)
+
+const (
+	defaultNameKey       = "etc.tracing.name"
+	defaultSamplerKey    = "etc.tracing.sampler"
+	defaultExporterKey   = "etc.tracing.exporter"
+	defaultFileKey       = "etc.tracing.file"
+	defaultBufferSizeKey = "etc.tracing.bufferSize"
+)
+
+const (
+	stdoutExporter = "stdout"
+	fileExporter   = "file"
+)
+
+type Option func(o *options)
+
+type options struct {
+	name       string   // 服务名称
+	sampler    float64  // 采样率，取值范围[0, 1]；仅对链路的根跨度生效，子跨度跟随父级的采样结果
+	exporter   Exporter // 导出器
+	bufferSize int      // 导出缓冲区大小，缓冲区已满时将丢弃新结束的跨度
+}
+
+func defaultOptions() *options {
+	opts := &options{
+		name:       etc.Get(defaultNameKey, defaultName).String(),
+		sampler:    etc.Get(defaultSamplerKey, defaultSampler).Float64(),
+		bufferSize: etc.Get(defaultBufferSizeKey, defaultBufferSize).Int(),
+	}
+
+	switch name := etc.Get(defaultExporterKey).String(); name {
+	case stdoutExporter:
+		opts.exporter = NewStdoutExporter()
+	case fileExporter:
+		if exporter, err := NewFileExporter(etc.Get(defaultFileKey).String()); err != nil {
+			log.Errorf("create tracing file exporter failed: %v", err)
+		} else {
+			opts.exporter = exporter
+		}
+	case "":
+	default:
+		log.Warnf("the tracing exporter %s is not supported and will be ignored", name)
+	}
+
+	if opts.bufferSize <= 0 {
+		opts.bufferSize = defaultBufferSize
+	}
+
+	return opts
+}
+
+// WithName 设置服务名称
+func WithName(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// WithSampler 设置采样率
+func WithSampler(sampler float64) Option {
+	return func(o *options) { o.sampler = sampler }
+}
+
+// WithExporter 设置导出器
+func WithExporter(exporter Exporter) Option {
+	return func(o *options) { o.exporter = exporter }
+}
+
+// WithBufferSize 设置导出缓冲区大小
+func WithBufferSize(bufferSize int) Option {
+	return func(o *options) { o.bufferSize = bufferSize }
+}
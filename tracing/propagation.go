@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+)
+
+const TraceparentHeader = "traceparent"
+
+type Carrier interface {
+	// Get 获取值
+	Get(key string) string
+	// Set 设置值
+	Set(key, value string)
+}
+
+type MapCarrier map[string]string
+
+// Get 获取值
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set 设置值
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Inject 将上下文中的链路上下文注入到载体中
+func Inject(ctx context.Context, carrier Carrier) {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		carrier.Set(TraceparentHeader, FormatTraceparent(sc))
+	}
+}
+
+// Extract 从载体中提取链路上下文并存入上下文
+func Extract(ctx context.Context, carrier Carrier) context.Context {
+	traceparent := carrier.Get(TraceparentHeader)
+	if traceparent == "" {
+		return ctx
+	}
+
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx
+	}
+
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
@@ -0,0 +1,270 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dobyte/due/v2/errors"
+)
+
+type SpanKind uint8
+
+const (
+	SpanKindInternal SpanKind = iota // 内部操作
+	SpanKindServer                   // 服务端处理远程请求
+	SpanKindClient                   // 客户端发起远程请求
+	SpanKindProducer                 // 生产者投递异步消息
+	SpanKindConsumer                 // 消费者处理异步消息
+)
+
+// String 获取名称
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	case SpanKindProducer:
+		return "producer"
+	case SpanKindConsumer:
+		return "consumer"
+	default:
+		return "internal"
+	}
+}
+
+type TraceID [16]byte
+
+// IsValid 是否有效
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String 获取十六进制字符串
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+type SpanID [8]byte
+
+// IsValid 是否有效
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String 获取十六进制字符串
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext 链路上下文，兼容W3C Trace Context规范
+type SpanContext struct {
+	TraceID TraceID // 链路ID
+	SpanID  SpanID  // 跨度ID
+	Sampled bool    // 是否采样
+	Remote  bool    // 是否来自远端
+}
+
+// IsValid 是否有效
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String 字符串属性
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int64 整数属性
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int 整数属性
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// Bool 布尔属性
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+type Span struct {
+	tracer *Tracer
+	name   string
+	kind   SpanKind
+	sc     SpanContext
+	parent SpanID
+	start  time.Time
+	end    time.Time
+	ended  atomic.Bool
+	mu     sync.Mutex
+	attrs  []Attribute
+	err    error
+}
+
+// Name 获取名称
+func (s *Span) Name() string {
+	return s.name
+}
+
+// Kind 获取类型
+func (s *Span) Kind() SpanKind {
+	return s.kind
+}
+
+// SpanContext 获取链路上下文
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+
+	return s.sc
+}
+
+// Parent 获取父级跨度ID
+func (s *Span) Parent() SpanID {
+	return s.parent
+}
+
+// StartTime 获取开始时间
+func (s *Span) StartTime() time.Time {
+	return s.start
+}
+
+// EndTime 获取结束时间
+func (s *Span) EndTime() time.Time {
+	return s.end
+}
+
+// Attributes 获取属性
+func (s *Span) Attributes() []Attribute {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Attribute(nil), s.attrs...)
+}
+
+// Err 获取记录的错误
+func (s *Span) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// SetAttributes 设置属性
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil || s.ended.Load() {
+		return
+	}
+
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// RecordError 记录错误
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil || s.ended.Load() {
+		return
+	}
+
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// End 结束跨度
+func (s *Span) End() {
+	if s == nil || !s.ended.CompareAndSwap(false, true) {
+		return
+	}
+
+	s.end = time.Now()
+
+	if s.sc.Sampled && s.tracer != nil {
+		s.tracer.export(s)
+	}
+}
+
+// 生成链路ID
+func newTraceID() (id TraceID) {
+	for id == (TraceID{}) {
+		putUint64(id[:8], rand.Uint64())
+		putUint64(id[8:], rand.Uint64())
+	}
+
+	return
+}
+
+// 生成跨度ID
+func newSpanID() (id SpanID) {
+	for id == (SpanID{}) {
+		putUint64(id[:], rand.Uint64())
+	}
+
+	return
+}
+
+func putUint64(b []byte, v uint64) {
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
+
+const traceparentVersion = "00"
+
+// FormatTraceparent 格式化为W3C traceparent头
+func FormatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return traceparentVersion + "-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent 解析W3C traceparent头
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	sc := SpanContext{Remote: true}
+
+	if len(traceparent) != 55 || traceparent[2] != '-' || traceparent[35] != '-' || traceparent[52] != '-' {
+		return sc, errors.ErrInvalidFormat
+	}
+
+	if traceparent[:2] == "ff" {
+		return sc, errors.ErrInvalidFormat
+	}
+
+	if _, err := hex.Decode(sc.TraceID[:], []byte(traceparent[3:35])); err != nil {
+		return sc, errors.ErrInvalidFormat
+	}
+
+	if _, err := hex.Decode(sc.SpanID[:], []byte(traceparent[36:52])); err != nil {
+		return sc, errors.ErrInvalidFormat
+	}
+
+	flags, err := hex.DecodeString(traceparent[53:])
+	if err != nil {
+		return sc, errors.ErrInvalidFormat
+	}
+
+	sc.Sampled = flags[0]&0x01 == 0x01
+
+	if !sc.IsValid() {
+		return sc, errors.ErrInvalidFormat
+	}
+
+	return sc, nil
+}
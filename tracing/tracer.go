@@ -0,0 +1,137 @@
+package tracing
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/dobyte/due/v2/log"
+)
+
+type Tracer struct {
+	opts   *options
+	rw     sync.RWMutex
+	closed bool
+	spans  chan *Span
+	done   chan struct{}
+}
+
+func NewTracer(opts ...Option) *Tracer {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	t := &Tracer{opts: o}
+
+	if o.exporter != nil {
+		t.spans = make(chan *Span, o.bufferSize)
+		t.done = make(chan struct{})
+
+		go t.run()
+	}
+
+	return t
+}
+
+// Name 获取服务名称
+func (t *Tracer) Name() string {
+	return t.opts.name
+}
+
+// Start 开始一个跨度，ctx中存在跨度时作为其子跨度
+func (t *Tracer) Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	o := &startOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Span{tracer: t, name: name, kind: o.kind, start: time.Now(), attrs: o.attrs}
+
+	if parent := SpanContextFromContext(ctx); parent.IsValid() {
+		s.sc.TraceID = parent.TraceID
+		s.sc.Sampled = parent.Sampled
+		s.parent = parent.SpanID
+	} else {
+		s.sc.TraceID = newTraceID()
+		s.sc.Sampled = t.sample()
+	}
+
+	s.sc.SpanID = newSpanID()
+
+	return ContextWithSpan(ctx, s), s
+}
+
+// Close 关闭追踪器，等待缓冲区中的跨度导出完成
+func (t *Tracer) Close() error {
+	t.rw.Lock()
+	if t.closed || t.spans == nil {
+		t.closed = true
+		t.rw.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.spans)
+	t.rw.Unlock()
+
+	<-t.done
+
+	return t.opts.exporter.Close()
+}
+
+// 是否采样
+func (t *Tracer) sample() bool {
+	switch {
+	case t.opts.sampler >= 1:
+		return true
+	case t.opts.sampler <= 0:
+		return false
+	default:
+		return rand.Float64() < t.opts.sampler
+	}
+}
+
+// 导出跨度
+func (t *Tracer) export(s *Span) {
+	t.rw.RLock()
+	defer t.rw.RUnlock()
+
+	if t.closed || t.spans == nil {
+		return
+	}
+
+	select {
+	case t.spans <- s:
+	default:
+		log.Warnf("tracing buffer is full and the span will be dropped, name: %s", s.name)
+	}
+}
+
+// 执行导出
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	for s := range t.spans {
+		if err := t.opts.exporter.Export(t.opts.name, s); err != nil {
+			log.Errorf("export span failed: %v", err)
+		}
+	}
+}
+
+type StartOption func(o *startOptions)
+
+type startOptions struct {
+	kind  SpanKind
+	attrs []Attribute
+}
+
+// WithSpanKind 设置跨度类型
+func WithSpanKind(kind SpanKind) StartOption {
+	return func(o *startOptions) { o.kind = kind }
+}
+
+// WithAttributes 设置跨度属性
+func WithAttributes(attrs ...Attribute) StartOption {
+	return func(o *startOptions) { o.attrs = append(o.attrs, attrs...) }
+}
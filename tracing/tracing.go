@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/dobyte/due/v2/log"
+)
+
+var globalTracer atomic.Pointer[Tracer]
+
+// ContextKey 上下文中存储当前跨度的键
+// 仅用于无法派生上下文的场景（如rpcx的share.Context），一般情况下请使用ContextWithSpan
+type ContextKey struct{}
+
+// SetTracer 设置全局追踪器
+func SetTracer(tracer *Tracer) {
+	if tracer == nil {
+		log.Warn("cannot set a nil tracer")
+		return
+	}
+
+	if old := globalTracer.Swap(tracer); old != nil && old != tracer {
+		if err := old.Close(); err != nil {
+			log.Errorf("close tracer failed: %v", err)
+		}
+	}
+}
+
+// GetTracer 获取全局追踪器
+func GetTracer() *Tracer {
+	return globalTracer.Load()
+}
+
+// Start 使用全局追踪器开始一个跨度；未设置全局追踪器时返回原上下文及nil跨度，nil跨度的所有方法均可安全调用
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	if tracer := globalTracer.Load(); tracer != nil {
+		return tracer.Start(ctx, name, opts...)
+	}
+
+	return ctx, nil
+}
+
+// ContextWithSpan 将跨度存入上下文
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, ContextKey{}, span)
+}
+
+// ContextWithRemoteSpanContext 将远端传递的链路上下文存入上下文，作为后续跨度的父级
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	sc.Remote = true
+
+	return ContextWithSpan(ctx, nonRecordingSpan(sc))
+}
+
+// SpanFromContext 获取上下文中的跨度
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+
+	span, _ := ctx.Value(ContextKey{}).(*Span)
+
+	return span
+}
+
+// SpanContextFromContext 获取上下文中的链路上下文
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	return SpanFromContext(ctx).SpanContext()
+}
+
+// 创建不记录数据的跨度，仅用于传递链路上下文
+func nonRecordingSpan(sc SpanContext) *Span {
+	s := &Span{sc: sc}
+	s.ended.Store(true)
+
+	return s
+}
@@ -0,0 +1,132 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dobyte/due/v2/tracing"
+)
+
+func TestTraceparent(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sc, err := tracing.ParseTraceparent(traceparent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !sc.IsValid() || !sc.Sampled {
+		t.Fatalf("invalid span context: %+v", sc)
+	}
+
+	if s := tracing.FormatTraceparent(sc); s != traceparent {
+		t.Fatalf("traceparent mismatch: %s", s)
+	}
+
+	for _, s := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+	} {
+		if _, err = tracing.ParseTraceparent(s); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	tracer := tracing.NewTracer(tracing.WithSampler(1))
+	defer tracer.Close()
+
+	ctx, span := tracer.Start(context.Background(), "client", tracing.WithSpanKind(tracing.SpanKindClient))
+
+	carrier := make(tracing.MapCarrier)
+	tracing.Inject(ctx, carrier)
+	span.End()
+
+	ctx = tracing.Extract(context.Background(), carrier)
+
+	_, child := tracer.Start(ctx, "server", tracing.WithSpanKind(tracing.SpanKindServer))
+	defer child.End()
+
+	if child.SpanContext().TraceID != span.SpanContext().TraceID {
+		t.Fatal("trace id is not propagated")
+	}
+
+	if child.Parent() != span.SpanContext().SpanID {
+		t.Fatal("parent span id is not propagated")
+	}
+}
+
+func TestSampler(t *testing.T) {
+	tracer := tracing.NewTracer(tracing.WithSampler(0))
+	defer tracer.Close()
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	if root.SpanContext().Sampled {
+		t.Fatal("root span should not be sampled")
+	}
+
+	if _, child := tracer.Start(ctx, "child"); child.SpanContext().Sampled {
+		t.Fatal("child span should follow parent sampling")
+	}
+
+	sc, _ := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx = tracing.ContextWithRemoteSpanContext(context.Background(), sc)
+
+	if _, child := tracer.Start(ctx, "child"); !child.SpanContext().Sampled {
+		t.Fatal("child span should follow remote parent sampling")
+	}
+}
+
+func TestExporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	tracer := tracing.NewTracer(
+		tracing.WithName("test"),
+		tracing.WithSampler(1),
+		tracing.WithExporter(tracing.NewWriterExporter(buf)),
+	)
+
+	_, span := tracer.Start(context.Background(), "handle", tracing.WithAttributes(tracing.Int("route", 1)))
+	span.RecordError(errors.New("failed"))
+	span.End()
+
+	if err := tracer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(lines))
+	}
+
+	record := make(map[string]any)
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record["service"] != "test" || record["name"] != "handle" || record["error"] != "failed" {
+		t.Fatalf("unexpected record: %s", lines[0])
+	}
+}
+
+func TestNoopTracer(t *testing.T) {
+	ctx, span := tracing.Start(context.Background(), "noop")
+	if span != nil {
+		t.Fatal("span should be nil without global tracer")
+	}
+
+	span.SetAttributes(tracing.String("key", "value"))
+	span.RecordError(errors.New("failed"))
+	span.End()
+
+	if tracing.SpanContextFromContext(ctx).IsValid() {
+		t.Fatal("span context should be invalid")
+	}
+}
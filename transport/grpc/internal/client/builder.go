@@ -63,11 +63,12 @@ func NewBuilder(opts *Options) *Builder {
 	b.opts = opts
 	b.ctx, b.cancel = context.WithCancel(context.Background())
 	b.resolvers = resolvers
-	b.dialOpts = make([]grpc.DialOption, 0, len(opts.DialOpts)+2)
+	b.dialOpts = make([]grpc.DialOption, 0, len(opts.DialOpts)+5)
 	b.dialOpts = append(b.dialOpts, opts.DialOpts...)
 	b.dialOpts = append(b.dialOpts, grpc.WithTransportCredentials(cred))
 	b.dialOpts = append(b.dialOpts, grpc.WithResolvers(resolvers...))
 	b.dialOpts = append(b.dialOpts, grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin":{}}]}`))
+	b.dialOpts = append(b.dialOpts, grpc.WithChainUnaryInterceptor(tracingInterceptor))
 
 	if err := b.init(); err != nil {
 		return &Builder{err: err}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dobyte/due/v2/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type metadataCarrier metadata.MD
+
+// Get 获取值
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Set 设置值
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func tracingInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+
+	ctx, span := tracing.Start(ctx, "mesh.call",
+		tracing.WithSpanKind(tracing.SpanKindClient),
+		tracing.WithAttributes(tracing.String("service", service), tracing.String("method", name)),
+	)
+	defer span.End()
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	tracing.Inject(ctx, metadataCarrier(md))
+
+	err := invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
+
+	span.RecordError(err)
+
+	return err
+}
@@ -2,9 +2,15 @@ package server
 
 import (
 	"context"
+	"runtime"
+	"strings"
+	"time"
+
 	"github.com/dobyte/due/v2/log"
+	"github.com/dobyte/due/v2/metrics"
+	"github.com/dobyte/due/v2/tracing"
 	"google.golang.org/grpc"
-	"runtime"
+	"google.golang.org/grpc/metadata"
 )
 
 func recoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
@@ -21,3 +27,51 @@ func recoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo
 
 	return handler(ctx, req)
 }
+
+func metricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	reply, err := handler(ctx, req)
+
+	service, method, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+
+	metrics.MeshHandleDuration.Observe(time.Since(start).Seconds(), service, method, metrics.Result(err))
+
+	return reply, err
+}
+
+type metadataCarrier metadata.MD
+
+// Get 获取值
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Set 设置值
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func tracingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = tracing.Extract(ctx, metadataCarrier(md))
+	}
+
+	service, method, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+
+	ctx, span := tracing.Start(ctx, "mesh.handle",
+		tracing.WithSpanKind(tracing.SpanKindServer),
+		tracing.WithAttributes(tracing.String("service", service), tracing.String("method", method)),
+	)
+	defer span.End()
+
+	reply, err := handler(ctx, req)
+
+	span.RecordError(err)
+
+	return reply, err
+}
@@ -36,7 +36,7 @@ func NewServer(opts *Options) (*Server, error) {
 	isSecure := false
 	serverOpts := make([]grpc.ServerOption, 0, len(opts.ServerOpts)+2)
 	serverOpts = append(serverOpts, opts.ServerOpts...)
-	serverOpts = append(serverOpts, grpc.UnaryInterceptor(recoverInterceptor))
+	serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(recoverInterceptor, tracingInterceptor, metricsInterceptor))
 	if opts.CertFile != "" && opts.KeyFile != "" {
 		cred, err := credentials.NewServerTLSFromFile(opts.CertFile, opts.KeyFile)
 		if err != nil {
@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 
+	"github.com/dobyte/due/v2/tracing"
 	cli "github.com/smallnest/rpcx/client"
+	"github.com/smallnest/rpcx/share"
 )
 
 type Client struct {
@@ -16,10 +18,38 @@ func NewClient(cli *cli.OneClient) *Client {
 
 // Call 调用服务方法
 func (c *Client) Call(ctx context.Context, service, method string, args any, reply any, opts ...any) error {
-	return c.cli.Call(ctx, service, method, args, reply)
+	ctx, span := tracing.Start(ctx, "mesh.call",
+		tracing.WithSpanKind(tracing.SpanKindClient),
+		tracing.WithAttributes(tracing.String("service", service), tracing.String("method", method)),
+	)
+	defer span.End()
+
+	err := c.cli.Call(inject(ctx), service, method, args, reply)
+
+	span.RecordError(err)
+
+	return err
 }
 
 // Client 获取客户端
 func (c *Client) Client() any {
 	return c.cli
 }
+
+// 将链路上下文注入到请求元数据中
+func inject(ctx context.Context) context.Context {
+	if !tracing.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	md := make(tracing.MapCarrier)
+	if meta, ok := ctx.Value(share.ReqMetaDataKey).(map[string]string); ok {
+		for k, v := range meta {
+			md[k] = v
+		}
+	}
+
+	tracing.Inject(ctx, md)
+
+	return context.WithValue(ctx, share.ReqMetaDataKey, map[string]string(md))
+}
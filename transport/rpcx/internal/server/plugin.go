@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/dobyte/due/v2/metrics"
+	"github.com/dobyte/due/v2/tracing"
+	"github.com/smallnest/rpcx/share"
+)
+
+type startTimeKey struct{}
+
+type metricsPlugin struct{}
+
+// PreCall 记录调用开始时间
+func (p *metricsPlugin) PreCall(ctx context.Context, _, _ string, args any) (any, error) {
+	if c, ok := ctx.(*share.Context); ok {
+		c.SetValue(startTimeKey{}, time.Now())
+	}
+
+	return args, nil
+}
+
+// PostCall 统计调用耗时
+func (p *metricsPlugin) PostCall(ctx context.Context, serviceName, methodName string, _, reply any, err error) (any, error) {
+	if start, ok := ctx.Value(startTimeKey{}).(time.Time); ok {
+		metrics.MeshHandleDuration.Observe(time.Since(start).Seconds(), serviceName, methodName, metrics.Result(err))
+	}
+
+	return reply, nil
+}
+
+type tracingPlugin struct{}
+
+// PreCall 提取链路上下文并开启追踪
+func (p *tracingPlugin) PreCall(ctx context.Context, serviceName, methodName string, args any) (any, error) {
+	c, ok := ctx.(*share.Context)
+	if !ok {
+		return args, nil
+	}
+
+	parent := context.Context(c)
+	if meta, ok := ctx.Value(share.ReqMetaDataKey).(map[string]string); ok {
+		parent = tracing.Extract(parent, tracing.MapCarrier(meta))
+	}
+
+	parent, _ = tracing.Start(parent, "mesh.handle",
+		tracing.WithSpanKind(tracing.SpanKindServer),
+		tracing.WithAttributes(tracing.String("service", serviceName), tracing.String("method", methodName)),
+	)
+
+	if span := tracing.SpanFromContext(parent); span != nil {
+		c.SetValue(tracing.ContextKey{}, span)
+	}
+
+	return args, nil
+}
+
+// PostCall 结束追踪
+func (p *tracingPlugin) PostCall(ctx context.Context, _, _ string, _, reply any, err error) (any, error) {
+	if span, ok := ctx.Value(tracing.ContextKey{}).(*tracing.Span); ok {
+		span.RecordError(err)
+		span.End()
+	}
+
+	return reply, nil
+}